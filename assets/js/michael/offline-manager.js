@@ -167,6 +167,7 @@ window.Michael.OfflineManager = (function() {
    * @param {Object} data - Completion data
    * @param {string} data.bible - Bible ID that was cached
    * @param {number} data.itemCount - Number of items cached
+   * @param {number} [data.failedCount] - Number of books and chapters that failed to cache
    */
   function handleCacheComplete(data) {
     const bibleId = data.bible || downloadState.currentBible;
@@ -175,6 +176,7 @@ window.Michael.OfflineManager = (function() {
       detail: {
         bible: bibleId,
         itemCount: data.itemCount || downloadState.totalItems,
+        failedCount: data.failedCount || 0,
         success: true
       }
     });
@@ -58,6 +58,19 @@ let bibleData = null;
  */
 let basePath = '/bible'; // Default, can be overridden from bible-data JSON
 
+/**
+ * URL path of the verse alignments between versifications
+ * @type {string}
+ * @default '/versification/'
+ */
+let alignmentPath = '/versification/'; // Default, can be overridden from bible-data JSON
+
+/**
+ * Verse alignments fetched so far, keyed by "{from}-{to}" versification pair
+ * @type {Map<string, Promise<Object|null>>}
+ */
+const alignments = new Map();
+
 /**
  * Array of currently selected translation IDs for comparison
  * @type {Array<string>}
@@ -316,6 +329,9 @@ function init() {
       if (bibleData.basePath) {
         basePath = bibleData.basePath;
       }
+      if (bibleData.alignmentPath) {
+        alignmentPath = bibleData.alignmentPath;
+      }
     } catch (e) {
       console.error('Failed to parse Bible data:', e);
       return;
@@ -581,7 +597,7 @@ function applyColorSelection(color, picker) {
 
 /**
  * Register document-level listeners that close the picker when the user
- * clicks or touches outside of it.
+ * clicks or touches outside of it, or presses Escape.
  * @private
  * @param {HTMLElement} btn - The toggle button (excluded from dismiss logic)
  * @param {HTMLElement} picker - The picker to dismiss
@@ -596,6 +612,14 @@ function registerPickerDismiss(btn, picker) {
   // Use click for mouse, touchstart for touch (touchend may fire after click)
   document.addEventListener('click', closePicker);
   document.addEventListener('touchstart', closePicker, { passive: true });
+
+  // Escape closes the picker; focus inside it goes back to the button
+  document.addEventListener('keydown', (e) => {
+    if (e.key !== 'Escape' || picker.classList.contains('hidden')) return;
+    const hadFocus = picker.contains(document.activeElement);
+    picker.classList.add('hidden');
+    if (hadFocus) btn.focus();
+  });
 }
 
 /**
@@ -623,6 +647,8 @@ function setupColorPicker(btnId, pickerId, optionSelector) {
     addTapListener(option, (e) => {
       e.stopPropagation();
       applyColorSelection(option.dataset.color, picker);
+      // The option is now hidden, so keep focus on the button
+      btn.focus();
     });
   });
 
@@ -804,11 +830,12 @@ async function loadComparison() {
     updateURL();
     parallelContent.replaceChildren(window.Michael.DomUtils.createLoadingIndicator());
 
-    const chaptersData = await fetchAllChapters();
+    const chaptersData = await alignChapters(await fetchAllChapters());
     parallelContent.replaceChildren(buildComparisonHTML(chaptersData));
 
     processComparisonFootnotes();
     announceComparisonLoaded();
+    dispatchChapterLoaded('compare', selectedTranslations, currentBook, currentChapter);
   } finally {
     isLoadingComparison = false;
   }
@@ -850,6 +877,22 @@ async function fetchAllChapters() {
   return Promise.all(promises);
 }
 
+/**
+ * Line up every translation's verses with the first translation that has
+ * the chapter, so verses are compared by text rather than by number
+ * @private
+ * @param {Array<Array<Object>|null>} chaptersData - Per-translation verse arrays
+ * @returns {Promise<Array<Array<Object>|null>>}
+ */
+async function alignChapters(chaptersData) {
+  const frame = chaptersData.findIndex(verses => verses && verses.length > 0);
+  if (frame < 0) return chaptersData;
+
+  return Promise.all(chaptersData.map((verses, i) => i === frame ? verses : alignChapter(
+    chaptersData[frame], selectedTranslations[frame], selectedTranslations[i], verses, currentBook, currentChapter
+  )));
+}
+
 /**
  * Process footnotes for VVV mode content
  * @private
@@ -881,6 +924,112 @@ function announceComparisonLoaded() {
   announce(`${bookName} chapter ${currentChapter}${verseInfo} loaded with ${selectedTranslations.length} translation${plural}.`);
 }
 
+/**
+ * Notify listeners that a chapter has finished rendering
+ * Dispatched on document as 'michael:chapter-loaded' so other modules and
+ * the regression suite can react to rendered content without polling
+ * @private
+ * @param {string} mode - 'compare' or 'sss'
+ * @param {string[]} bibles - Bible IDs that were rendered
+ * @param {string} book - Book ID
+ * @param {number} chapter - Chapter number
+ */
+function dispatchChapterLoaded(mode, bibles, book, chapter) {
+  document.dispatchEvent(new CustomEvent('michael:chapter-loaded', {
+    detail: { mode, bibles: [...bibles], book, chapter }
+  }));
+}
+
+/* ========================================================================
+   VERSIFICATION ALIGNMENT
+   ======================================================================== */
+
+/**
+ * Get a Bible's versification scheme from the embedded metadata. The page
+ * only declares it when the Bible's text follows the scheme's chapter and
+ * verse numbers, so other Bibles are never realigned.
+ * @private
+ * @param {string} bibleId - Bible translation ID
+ * @returns {string} Scheme name (e.g., 'kjva', 'leningrad'), or '' if unknown
+ */
+function versificationOf(bibleId) {
+  return bibleData?.bibles?.find(b => b.id === bibleId)?.versification || '';
+}
+
+/**
+ * Fetch the verse alignment between two versifications
+ * Written to static/versification/{from}-{to}.json by cmd/versification-map
+ * @private
+ * @param {string} from - Versification whose numbering is shown
+ * @param {string} to - Versification of the Bible being lined up
+ * @returns {Promise<Object|null>} OSIS verses of `from` mapped to the OSIS
+ *   verses of `to` holding their text, or null if there is no alignment
+ */
+function fetchAlignment(from, to) {
+  const key = `${from}-${to}`;
+  if (!alignments.has(key)) {
+    alignments.set(key, fetch(`${alignmentPath}${key}.json`)
+      .then(response => response.ok ? response.json() : null)
+      .then(data => data?.verses || null)
+      .catch(error => {
+        console.warn(`Failed to load versification alignment ${key}:`, error);
+        alignments.delete(key);
+        return null;
+      }));
+  }
+  return alignments.get(key);
+}
+
+/**
+ * Renumber a Bible's chapter into the versification of the Bible shown as
+ * the frame, as Malachi 4:1 of an English Bible holds Malachi 3:19 of a
+ * Hebrew one. Each frame verse gets the text of the verses the alignment
+ * maps it to, fetching other chapters or books of the Bible as needed.
+ * Verses the alignment leaves out keep their number.
+ * @private
+ * @async
+ * @param {Array<Object>} frameVerses - Verses of the frame Bible's chapter
+ * @param {string} frameBible - Frame Bible ID
+ * @param {string} bibleId - Bible to renumber
+ * @param {Array<Object>|null} verses - Bible's verses at the same book and chapter
+ * @param {string} book - Book ID
+ * @param {number} chapter - Chapter number
+ * @returns {Promise<Array<Object>|null>} Verses numbered as the frame numbers them
+ */
+async function alignChapter(frameVerses, frameBible, bibleId, verses, book, chapter) {
+  const from = versificationOf(frameBible);
+  const to = versificationOf(bibleId);
+  if (!from || !to || from === to) return verses;
+
+  const alignment = await fetchAlignment(from, to);
+  if (!alignment) return verses;
+
+  const refs = frameVerses.map(v => {
+    const osis = `${book}.${chapter}.${v.number}`;
+    return (alignment[osis] || [osis]).map(id => id.split('.'));
+  });
+
+  // Load every chapter the aligned verses come from once
+  const chapters = new Map([[`${book}.${chapter}`, verses || []]]);
+  const missing = new Set();
+  refs.flat().forEach(([b, c]) => {
+    if (!chapters.has(`${b}.${c}`)) missing.add(`${b}.${c}`);
+  });
+  await Promise.all([...missing].map(async key => {
+    const [b, c] = key.split('.');
+    const fetched = await window.Michael.BibleAPI.fetchChapter(basePath, bibleId, b, Number(c));
+    chapters.set(key, fetched || []);
+  }));
+
+  return frameVerses.map((v, i) => ({
+    number: v.number,
+    text: refs[i]
+      .map(([b, c, n]) => chapters.get(`${b}.${c}`).find(cv => cv.number === Number(n))?.text)
+      .filter(Boolean)
+      .join(' ')
+  }));
+}
+
 /* ========================================================================
    VERSE DISPLAY
    ======================================================================== */
@@ -1808,14 +1957,19 @@ async function loadSSSComparison() {
   showSSSLoadingIndicators();
 
   // Fetch both chapters
-  const [leftVerses, rightVerses] = await Promise.all([
+  const [leftVerses, fetchedRight] = await Promise.all([
     window.Michael.BibleAPI.fetchChapter(basePath, sssLeftBible, sssBook, sssChapter),
     window.Michael.BibleAPI.fetchChapter(basePath, sssRightBible, sssBook, sssChapter)
   ]);
 
-  // If right Bible has no verses, try to pick another one automatically
-  const rightEmpty = !rightVerses || rightVerses.length === 0;
+  // Number the right Bible's verses as the left Bible numbers them
   const leftHasVerses = leftVerses && leftVerses.length > 0;
+  const rightVerses = leftHasVerses
+    ? await alignChapter(leftVerses, sssLeftBible, sssRightBible, fetchedRight, sssBook, sssChapter)
+    : fetchedRight;
+
+  // If right Bible has no verses, try to pick another one automatically
+  const rightEmpty = !rightVerses || !rightVerses.some(v => v.text);
   if (rightEmpty && leftHasVerses && tryAlternateSSSBible()) {
     return loadSSSComparison();
   }
@@ -1829,6 +1983,7 @@ async function loadSSSComparison() {
   renderSSSPanes(leftVerses, rightVerses);
   processSSSFootnotes();
   syncSSSVerseHeights();
+  dispatchChapterLoaded('sss', [sssLeftBible, sssRightBible], sssBook, sssChapter);
 }
 
 /**
@@ -0,0 +1,82 @@
+// Command content-manifest classifies the text of every Bible in a data
+// directory and writes the bibles_content/{id}.json manifests that
+// content/bible/_content.gotmpl builds pages from.
+//
+// Run from the repository root:
+//
+//	go run ./cmd/content-manifest                  # rewrite data/example/bibles_content
+//	go run ./cmd/content-manifest -v data/x        # also list every finding
+//	go run ./cmd/content-manifest -check           # fail if a manifest is stale
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/quality"
+)
+
+func main() {
+	check := flag.Bool("check", false, "verify instead of writing: every manifest is current")
+	verbose := flag.Bool("v", false, "list every finding, not just the totals")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: content-manifest [flags] [data-dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "data/example"
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(dir, *check, *verbose); err != nil {
+		fmt.Fprintln(os.Stderr, "content-manifest:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, check, verbose bool) error {
+	var manifests []*quality.Manifest
+	var err error
+	if check {
+		manifests, err = quality.Build(dir)
+	} else {
+		manifests, err = quality.WriteManifests(dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	for _, m := range manifests {
+		v := m.Verses
+		fmt.Printf("%s: %s; %d real, %d placeholder, %d empty, %d suspicious verses; %d findings\n",
+			m.Bible, m.Status, v.Real, v.Placeholder, v.Empty, v.Suspicious, len(m.Findings))
+		if verbose {
+			for _, f := range m.Findings {
+				fmt.Printf("  %s\n", f)
+			}
+		}
+		if check {
+			want, err := m.Encode()
+			if err != nil {
+				return err
+			}
+			if have, err := os.ReadFile(quality.ManifestPath(dir, m.Bible)); err != nil || !bytes.Equal(have, want) {
+				stale = append(stale, m.Bible)
+			}
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("manifests out of date for %v; run go run ./cmd/content-manifest %s", stale, dir)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Command validate-data checks a Bible data directory against the schemas in
+// static/schemas and the rules they cannot express: increasing chapter and
+// verse numbers, no duplicate books, OSIS book IDs valid for the declared
+// versification and testament, and an auxiliary file for every Bible.
+//
+// Run from the repository root:
+//
+//	go run ./cmd/validate-data                       # check data/example
+//	go run ./cmd/validate-data -format json data/x   # machine-readable report
+//
+// It exits 1 when the data has errors and 2 when it cannot run.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/datacheck"
+)
+
+func main() {
+	schemas := flag.String("schemas", "static/schemas", "directory containing the JSON schemas")
+	format := flag.String("format", "text", "report format: text or json")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: validate-data [flags] [data-dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "data/example"
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed, err := run(dir, *schemas, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "validate-data:", err)
+		os.Exit(2)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func run(dir, schemaDir, format string) (bool, error) {
+	if format != "text" && format != "json" {
+		return false, fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+	schemas, err := datacheck.LoadSchemas(schemaDir)
+	if err != nil {
+		return false, err
+	}
+	report, err := datacheck.Check(dir, schemas)
+	if err != nil {
+		return false, err
+	}
+	if format == "json" {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	return report.Errors > 0, err
+}
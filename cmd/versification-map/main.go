@@ -0,0 +1,73 @@
+// Command versification-map writes the verse alignments between the
+// versification schemes bibles.json declares, one {from}-{to}.json per pair
+// of schemes, for the compare and SSS views to line up Bibles that number
+// their verses differently.
+//
+// Run from the repository root:
+//
+//	go run ./cmd/versification-map                  # rewrite static/versification
+//	go run ./cmd/versification-map -check           # fail if an alignment is stale
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/versification"
+)
+
+func main() {
+	check := flag.Bool("check", false, "verify instead of writing: every alignment is current")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: versification-map [flags] [out-dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dir := "static/versification"
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(dir, *check); err != nil {
+		fmt.Fprintln(os.Stderr, "versification-map:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, check bool) error {
+	var alignments []*versification.Alignment
+	if check {
+		alignments = versification.Alignments()
+	} else {
+		var err error
+		if alignments, err = versification.WriteAlignments(dir); err != nil {
+			return err
+		}
+	}
+
+	var stale []string
+	for _, a := range alignments {
+		fmt.Printf("%s -> %s: %d verses moved\n", a.From, a.To, len(a.Verses))
+		if check {
+			want, err := a.Encode()
+			if err != nil {
+				return err
+			}
+			if have, err := os.ReadFile(versification.AlignmentPath(dir, a.From, a.To)); err != nil || !bytes.Equal(have, want) {
+				stale = append(stale, a.From+"-"+a.To)
+			}
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("alignments out of date for %v; run go run ./cmd/versification-map %s", stale, dir)
+	}
+	return nil
+}
@@ -28,6 +28,7 @@
  * Cache Strategy:
  * - Shell assets: Pre-cached on install (CSS, JS)
  * - Chapter pages: Cached on-demand as users browse
+ * - Verse alignments: Cached on-demand as the compare page loads them
  * - Offline fallback: Pre-cached for when all else fails
  *
  * Generated at build time by Hugo to include fingerprinted asset paths.
@@ -62,6 +63,8 @@ const SHELL_ASSETS = [
   // CSS with fingerprinted path from Hugo
   '{{ $theme.RelPermalink }}',
   // Core JS files (only those referenced in templates)
+  '/js/theme-init.js',
+  '/js/theme-toggle.js',
   '/js/michael/bible-api.js',
   '/js/michael/dom-utils.js',
   '/js/michael/share-menu.js',
@@ -0,0 +1,76 @@
+package bibles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AuxiliaryDir is the directory of a data directory holding the text of
+// each Bible as {id}.json.
+const AuxiliaryDir = "bibles_auxiliary"
+
+// Verse is a single numbered verse. Text may carry OSIS markup.
+type Verse struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// Chapter is a numbered list of verses.
+type Chapter struct {
+	Number int     `json:"number"`
+	Verses []Verse `json:"verses"`
+}
+
+// Book is a book of a Bible identified by its OSIS ID.
+type Book struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Abbrev    string    `json:"abbrev,omitempty"`
+	Testament string    `json:"testament,omitempty"`
+	Chapters  []Chapter `json:"chapters"`
+}
+
+// ExcludedBook is a book the source module declares but has no text for.
+type ExcludedBook struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Testament string `json:"testament"`
+	Reason    string `json:"reason"`
+}
+
+// Section is a custom section shown on the Bible's page.
+type Section struct {
+	Heading string `json:"heading"`
+	Content string `json:"content,omitempty"`
+}
+
+// Auxiliary is a bibles_auxiliary/{id}.json document. Unlike Index it does
+// not keep key order; the generator writes these files whole.
+type Auxiliary struct {
+	Content       string         `json:"content,omitempty"`
+	Books         []Book         `json:"books"`
+	ExcludedBooks []ExcludedBook `json:"excludedBooks,omitempty"`
+	Sections      []Section      `json:"sections,omitempty"`
+}
+
+// AuxiliaryPath returns the path of a Bible's auxiliary file in dir.
+func AuxiliaryPath(dir, id string) string {
+	return filepath.Join(dir, AuxiliaryDir, id+".json")
+}
+
+// LoadAuxiliary reads the text of the Bible with the given ID from a data
+// directory.
+func LoadAuxiliary(dir, id string) (*Auxiliary, error) {
+	path := AuxiliaryPath(dir, id)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var aux Auxiliary
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return &aux, nil
+}
@@ -0,0 +1,184 @@
+// Package bibles reads the Bible data in data/example: the bibles.json
+// index, the bibles_auxiliary texts, and bibles_override.json, which it
+// applies to the index the way the Hugo templates do.
+//
+// Decoded indexes remember their key order and any keys this package does
+// not know, so encoding a decoded document gives back the same bytes.
+package bibles
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// File names in a data directory.
+const (
+	IndexFile    = "bibles.json"
+	OverrideFile = "bibles_override.json"
+)
+
+// Bible is one entry of the bibles.json "bibles" array.
+type Bible struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Abbrev        string   `json:"abbrev"`
+	Language      string   `json:"language"`
+	License       string   `json:"license"`
+	LicenseText   string   `json:"licenseText"`
+	Versification string   `json:"versification"`
+	Features      []string `json:"features"`
+	Tags          []string `json:"tags"`
+	Weight        int      `json:"weight"`
+
+	// read is the object the entry was decoded from.
+	read *object
+}
+
+// plainBible is Bible without its JSON methods.
+type plainBible Bible
+
+// UnmarshalJSON decodes the entry and remembers its key order.
+func (b *Bible) UnmarshalJSON(data []byte) error {
+	var plain plainBible
+	read, err := unmarshalOrdered(data, &plain)
+	if err != nil {
+		return err
+	}
+	*b = Bible(plain)
+	b.read = read
+	return nil
+}
+
+// MarshalJSON encodes the entry in the key order it was decoded with.
+func (b Bible) MarshalJSON() ([]byte, error) {
+	return marshalOrdered(plainBible(b), b.read)
+}
+
+// Meta is the bibles.json "meta" object.
+type Meta struct {
+	Granularity string `json:"granularity"`
+	Generated   string `json:"generated"`
+	Version     string `json:"version"`
+
+	read *object
+}
+
+// plainMeta is Meta without its JSON methods.
+type plainMeta Meta
+
+// UnmarshalJSON decodes the object and remembers its key order.
+func (m *Meta) UnmarshalJSON(data []byte) error {
+	var plain plainMeta
+	read, err := unmarshalOrdered(data, &plain)
+	if err != nil {
+		return err
+	}
+	*m = Meta(plain)
+	m.read = read
+	return nil
+}
+
+// MarshalJSON encodes the object in the key order it was decoded with.
+func (m Meta) MarshalJSON() ([]byte, error) {
+	return marshalOrdered(plainMeta(m), m.read)
+}
+
+// Index is the bibles.json document.
+type Index struct {
+	Bibles []Bible `json:"bibles"`
+	Meta   Meta    `json:"meta"`
+
+	read *object
+}
+
+// plainIndex is Index without its JSON methods.
+type plainIndex Index
+
+// UnmarshalJSON decodes the document and remembers its key order.
+func (x *Index) UnmarshalJSON(data []byte) error {
+	var plain plainIndex
+	read, err := unmarshalOrdered(data, &plain)
+	if err != nil {
+		return err
+	}
+	*x = Index(plain)
+	x.read = read
+	return nil
+}
+
+// MarshalJSON encodes the document in the key order it was decoded with.
+func (x Index) MarshalJSON() ([]byte, error) {
+	return marshalOrdered(plainIndex(x), x.read)
+}
+
+// Bible returns the entry with the given ID.
+func (x *Index) Bible(id string) (*Bible, bool) {
+	for i := range x.Bibles {
+		if x.Bibles[i].ID == id {
+			return &x.Bibles[i], true
+		}
+	}
+	return nil, false
+}
+
+// Parse decodes a bibles.json document.
+func Parse(data []byte) (*Index, error) {
+	var x Index
+	if err := json.Unmarshal(data, &x); err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// Encode writes the index as the generator does: two-space indentation,
+// markup unescaped and a trailing newline.
+func (x *Index) Encode() ([]byte, error) {
+	data, err := marshal(x)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Load reads bibles.json from a data directory and applies
+// bibles_override.json when the directory has one, which gives the Bibles
+// the site shows.
+func Load(dir string) (*Index, error) {
+	path := filepath.Join(dir, IndexFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	x, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	path = filepath.Join(dir, OverrideFile)
+	data, err = os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return x, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	o, err := ParseOverrides(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if err := x.Apply(o); err != nil {
+		return nil, fmt.Errorf("failed to apply %s: %w", path, err)
+	}
+	return x, nil
+}
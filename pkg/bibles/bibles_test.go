@@ -0,0 +1,268 @@
+package bibles
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// exampleDir is the vendored data tree hugo.toml mounts.
+var exampleDir = filepath.Join("..", "..", "data", "example")
+
+// TestRoundTrip verifies that encoding a decoded bibles.json gives back the
+// same bytes, for the vendored index and for one with unusual key order and
+// unknown keys.
+func TestRoundTrip(t *testing.T) {
+	example := mustRead(t, filepath.Join(exampleDir, IndexFile))
+	reordered := `{
+  "meta": {
+    "version": "2.0.0",
+    "granularity": "chapter",
+    "generated": "2026-01-01T00:00:00Z",
+    "source": "juniper"
+  },
+  "bibles": [
+    {
+      "weight": 3,
+      "id": "x",
+      "abbrev": "X",
+      "title": "<i>X</i> & Y",
+      "extra": {
+        "b": 1,
+        "a": [
+          true
+        ]
+      },
+      "features": [],
+      "tags": [
+        "en"
+      ]
+    }
+  ]
+}
+`
+	for name, data := range map[string]string{"example": string(example), "reordered": reordered} {
+		t.Run(name, func(t *testing.T) {
+			x, err := Parse([]byte(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := x.Encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != data {
+				t.Errorf("round trip changed the document:\n%s", out)
+			}
+		})
+	}
+}
+
+// TestParseFields verifies every field is decoded.
+func TestParseFields(t *testing.T) {
+	x, err := Parse([]byte(`{"bibles":[{"id":"kjva","title":"KJV","description":"d","abbrev":"KJVA",
+		"language":"en","license":"public-domain","licenseText":"t","versification":"kjva",
+		"features":["StrongsNumbers"],"tags":["en"],"weight":4}],
+		"meta":{"granularity":"chapter","generated":"g","version":"2.0.0"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, ok := x.Bible("kjva")
+	if !ok {
+		t.Fatal("kjva not found")
+	}
+	got := []string{b.ID, b.Title, b.Description, b.Abbrev, b.Language, b.License, b.LicenseText,
+		b.Versification, strings.Join(b.Features, ","), strings.Join(b.Tags, ","), x.Meta.Granularity,
+		x.Meta.Generated, x.Meta.Version}
+	want := []string{"kjva", "KJV", "d", "KJVA", "en", "public-domain", "t", "kjva", "StrongsNumbers", "en",
+		"chapter", "g", "2.0.0"}
+	if !slices.Equal(got, want) || b.Weight != 4 {
+		t.Errorf("got %q weight %d, want %q weight 4", got, b.Weight, want)
+	}
+	if _, ok := x.Bible("KJVA"); ok {
+		t.Error("Bible IDs must match exactly")
+	}
+}
+
+// TestEncodeNew verifies an index built in Go encodes every field in
+// struct order.
+func TestEncodeNew(t *testing.T) {
+	x := &Index{Bibles: []Bible{{ID: "a", Title: "A", Abbrev: "A", Weight: 1}}}
+	out, err := x.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, out); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"bibles":[{"id":"a","title":"A","description":"","abbrev":"A","language":"","license":"",` +
+		`"licenseText":"","versification":"","features":null,"tags":null,"weight":1}],` +
+		`"meta":{"granularity":"","generated":"","version":""}}`
+	if compact.String() != want {
+		t.Errorf("got %s\nwant %s", compact.String(), want)
+	}
+}
+
+// TestApply verifies overrides follow Hugo's merge: present fields win,
+// absent ones are kept, names match ignoring case, null clears, unknown
+// fields are added and nested objects are merged.
+func TestApply(t *testing.T) {
+	base := `{"id":"asv","title":"ASV","abbrev":"ASV","tags":["en"],"weight":1,"extra":{"a":1,"b":2}}`
+	tests := []struct {
+		name     string
+		override Override
+		want     string
+	}{
+		{
+			name:     "replace",
+			override: Override{"title": json.RawMessage(`"American Standard Version"`)},
+			want:     `{"id":"asv","title":"American Standard Version","abbrev":"ASV","tags":["en"],"weight":1,"extra":{"a":1,"b":2}}`,
+		},
+		{
+			name:     "case-insensitive",
+			override: Override{"Title": json.RawMessage(`"T"`), "WEIGHT": json.RawMessage(`9`)},
+			want:     `{"id":"asv","title":"T","abbrev":"ASV","tags":["en"],"weight":9,"extra":{"a":1,"b":2}}`,
+		},
+		{
+			name:     "slices replace",
+			override: Override{"tags": json.RawMessage(`["la","x"]`)},
+			want:     `{"id":"asv","title":"ASV","abbrev":"ASV","tags":["la","x"],"weight":1,"extra":{"a":1,"b":2}}`,
+		},
+		{
+			name:     "null clears",
+			override: Override{"abbrev": json.RawMessage(`null`)},
+			want:     `{"id":"asv","title":"ASV","abbrev":"","tags":["en"],"weight":1,"extra":{"a":1,"b":2}}`,
+		},
+		{
+			name:     "new fields",
+			override: Override{"license": json.RawMessage(`"CC0-1.0"`), "badge": json.RawMessage(`"new"`)},
+			want:     `{"id":"asv","title":"ASV","abbrev":"ASV","tags":["en"],"weight":1,"extra":{"a":1,"b":2},"badge":"new","license":"CC0-1.0"}`,
+		},
+		{
+			name:     "nested merge",
+			override: Override{"extra": json.RawMessage(`{"b":3,"c":4}`)},
+			want:     `{"id":"asv","title":"ASV","abbrev":"ASV","tags":["en"],"weight":1,"extra":{"a":1,"b":3,"c":4}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Bible
+			if err := json.Unmarshal([]byte(base), &b); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Apply(tt.override); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+
+	var b Bible
+	if err := json.Unmarshal([]byte(base), &b); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Apply(Override{"weight": json.RawMessage(`"heavy"`)}); err == nil {
+		t.Error("expected an error for a weight that is not a number")
+	}
+}
+
+// TestLoad verifies Load applies the vendored overrides by exact ID and
+// leaves other fields and Bibles alone.
+func TestLoad(t *testing.T) {
+	x, err := Load(exampleDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, err := ParseOverrides(mustRead(t, filepath.Join(exampleDir, OverrideFile)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := Parse(mustRead(t, filepath.Join(exampleDir, IndexFile)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, b := range x.Bibles {
+		orig := plain.Bibles[i]
+		override, ok := o.Overrides[b.ID]
+		if !ok {
+			if b.Title != orig.Title || b.Description != orig.Description {
+				t.Errorf("%s has no override but changed", b.ID)
+			}
+			continue
+		}
+		var title string
+		if raw, ok := override["title"]; ok {
+			if err := json.Unmarshal(raw, &title); err != nil {
+				t.Fatal(err)
+			}
+			if b.Title != title {
+				t.Errorf("%s title is %q, override sets %q", b.ID, b.Title, title)
+			}
+		}
+		if _, ok := override["license"]; !ok && b.License != orig.License {
+			t.Errorf("%s license changed without an override", b.ID)
+		}
+	}
+
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("expected an error loading a directory without bibles.json")
+	}
+}
+
+// TestApplyIgnoresUnknownIDs verifies overrides for missing Bibles change
+// nothing.
+func TestApplyIgnoresUnknownIDs(t *testing.T) {
+	x, err := Parse([]byte(`{"bibles":[{"id":"a","title":"A","abbrev":"A"}],"meta":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = x.Apply(&Overrides{Overrides: map[string]Override{
+		"A": {"title": json.RawMessage(`"upper"`)},
+		"b": {"title": json.RawMessage(`"missing"`)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Bibles[0].Title != "A" {
+		t.Errorf("title is %q, want A", x.Bibles[0].Title)
+	}
+}
+
+// TestLoadAuxiliary verifies the vendored texts decode.
+func TestLoadAuxiliary(t *testing.T) {
+	aux, err := LoadAuxiliary(exampleDir, "tyndale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aux.Books) == 0 || aux.Books[0].ID != "Gen" || len(aux.Books[0].Chapters) != 50 {
+		t.Errorf("tyndale should start with the 50 chapters of Genesis, got %d books", len(aux.Books))
+	}
+	if len(aux.ExcludedBooks) == 0 {
+		t.Error("tyndale should list the books it lacks")
+	}
+	if _, err := LoadAuxiliary(exampleDir, "missing"); err == nil {
+		t.Error("expected an error for a Bible without an auxiliary file")
+	}
+}
+
+// mustRead reads a file, failing the test on error.
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,192 @@
+package bibles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// object is a JSON object that remembers the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// parseObject decodes a JSON object, keeping its key order. Later duplicates
+// of a key replace earlier ones, as encoding/json does.
+func parseObject(data []byte) (*object, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	o := &object{values: map[string]json.RawMessage{}}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		o.set(key, value)
+	}
+	return o, nil
+}
+
+// set stores a value, appending the key if it is new.
+func (o *object) set(key string, value json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// lookup finds a key the way Hugo and encoding/json do: exactly if present,
+// otherwise ignoring case.
+func (o *object) lookup(key string) (string, bool) {
+	if _, ok := o.values[key]; ok {
+		return key, true
+	}
+	for _, k := range o.keys {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// MarshalJSON writes the keys in order.
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshal encodes v without escaping <, > and &, which verse markup and
+// license texts are full of.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// unmarshalOrdered decodes data into v, a pointer to a type without its own
+// UnmarshalJSON, and returns the object it was read from.
+func unmarshalOrdered(data []byte, v any) (*object, error) {
+	o, err := parseObject(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// marshalOrdered encodes v, a type without its own MarshalJSON, in the key
+// order of read. Keys read did not know keep their value. Fields read did not
+// have are appended in struct order, unless they are zero, so that encoding
+// what was decoded gives the same keys back. A nil read encodes every field.
+func marshalOrdered(v any, read *object) ([]byte, error) {
+	data, err := marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if read == nil {
+		return data, nil
+	}
+	fields, err := parseObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &object{values: map[string]json.RawMessage{}}
+	used := map[string]bool{}
+	for _, key := range read.keys {
+		if field, ok := fields.lookup(key); ok && !used[field] {
+			out.set(key, fields.values[field])
+			used[field] = true
+		} else {
+			out.set(key, read.values[key])
+		}
+	}
+	for _, field := range fields.keys {
+		if !used[field] && !isZero(fields.values[field]) {
+			out.set(field, fields.values[field])
+		}
+	}
+	return out.MarshalJSON()
+}
+
+// isZero reports whether an encoded value is its type's zero value.
+func isZero(v json.RawMessage) bool {
+	switch string(v) {
+	case `""`, "0", "false", "null", "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// merge applies src to dst as Hugo's merge function does when src is the
+// later map: every key of src replaces the key of dst with the same name,
+// ignoring case, and nested objects are merged key by key. New keys are
+// appended in src's order.
+func merge(dst, src *object) error {
+	for _, key := range src.keys {
+		value := src.values[key]
+		existing, ok := dst.lookup(key)
+		if !ok {
+			dst.set(key, value)
+			continue
+		}
+		if isObject(value) && isObject(dst.values[existing]) {
+			inner, err := parseObject(dst.values[existing])
+			if err != nil {
+				return err
+			}
+			override, err := parseObject(value)
+			if err != nil {
+				return err
+			}
+			if err := merge(inner, override); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			if value, err = inner.MarshalJSON(); err != nil {
+				return err
+			}
+		}
+		dst.values[existing] = value
+	}
+	return nil
+}
+
+// isObject reports whether an encoded value is a JSON object.
+func isObject(v json.RawMessage) bool {
+	v = bytes.TrimSpace(v)
+	return len(v) > 0 && v[0] == '{'
+}
@@ -0,0 +1,83 @@
+package bibles
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Override is the fields bibles_override.json sets for one Bible, as
+// encoded JSON values by field name. Only the fields present are applied.
+type Override map[string]json.RawMessage
+
+// Overrides is the bibles_override.json document.
+type Overrides struct {
+	// Comment documents the file for people editing it.
+	Comment string `json:"_comment,omitempty"`
+	// Overrides maps Bible IDs to the fields to replace.
+	Overrides map[string]Override `json:"overrides"`
+}
+
+// ParseOverrides decodes a bibles_override.json document.
+func ParseOverrides(data []byte) (*Overrides, error) {
+	var o Overrides
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// Apply merges o onto the entry as partials/michael/bible-data.html does
+// with Hugo's merge: each field present in o replaces the entry's field of
+// the same name, ignoring case, and null clears it. Fields the entry does
+// not have are added after its own, in name order.
+func (b *Bible) Apply(o Override) error {
+	data, err := b.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	dst, err := parseObject(data)
+	if err != nil {
+		return err
+	}
+	src := &object{values: map[string]json.RawMessage{}}
+	names := make([]string, 0, len(o))
+	for name := range o {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		src.set(name, o[name])
+	}
+	if err := merge(dst, src); err != nil {
+		return err
+	}
+
+	merged, err := dst.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var out Bible
+	if err := out.UnmarshalJSON(merged); err != nil {
+		return err
+	}
+	*b = out
+	return nil
+}
+
+// Apply merges the override for each Bible in o onto its entry. The
+// template looks overrides up by exact ID, so overrides for IDs the index
+// does not have are ignored, as are empty ones.
+func (x *Index) Apply(o *Overrides) error {
+	for i := range x.Bibles {
+		b := &x.Bibles[i]
+		override := o.Overrides[b.ID]
+		if len(override) == 0 {
+			continue
+		}
+		if err := b.Apply(override); err != nil {
+			return fmt.Errorf("override for %s: %w", b.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+// Package datacheck validates a Bible data directory: bibles.json and every
+// bibles_auxiliary/{id}.json against the schemas in static/schemas, and the
+// rules the schemas cannot express, such as increasing chapter and verse
+// numbers, book IDs that belong to the declared versification and verse
+// numbers it has.
+//
+// Every problem names its file and a JSON pointer into it.
+package datacheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/bibles"
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/schema"
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/versification"
+)
+
+// Schema file names in static/schemas.
+const (
+	IndexSchemaFile     = "bibles.schema.json"
+	AuxiliarySchemaFile = "bibles-auxiliary.schema.json"
+)
+
+// Severity is how serious a problem is. Only errors fail a check.
+type Severity string
+
+// Severities.
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Problem is one finding in a data directory.
+type Problem struct {
+	// File is the path of the file relative to the data directory, with
+	// forward slashes.
+	File string `json:"file"`
+	// Pointer is the JSON pointer to the value at fault, "" for the file.
+	Pointer  string   `json:"pointer"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s#%s: %s: %s", p.File, p.Pointer, p.Severity, p.Message)
+}
+
+// Report is the result of checking a data directory.
+type Report struct {
+	Dir      string    `json:"dir"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Problems []Problem `json:"problems"`
+}
+
+// add records a problem.
+func (r *Report) add(file, pointer string, severity Severity, format string, args ...any) {
+	if severity == Error {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Problems = append(r.Problems, Problem{File: file, Pointer: pointer, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// WriteText writes one line per problem and a summary.
+func (r *Report) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	for _, p := range r.Problems {
+		sb.WriteString(p.String())
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(&sb, "%s: %d errors, %d warnings\n", r.Dir, r.Errors, r.Warnings)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteJSON writes the report as an indented JSON document.
+func (r *Report) WriteJSON(w io.Writer) error {
+	if r.Problems == nil {
+		r.Problems = []Problem{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Schemas are the compiled schemas a directory is checked against.
+type Schemas struct {
+	Index     *schema.Schema
+	Auxiliary *schema.Schema
+}
+
+// LoadSchemas compiles the schemas in dir, normally static/schemas.
+func LoadSchemas(dir string) (*Schemas, error) {
+	index, err := schema.Load(filepath.Join(dir, IndexSchemaFile))
+	if err != nil {
+		return nil, err
+	}
+	aux, err := schema.Load(filepath.Join(dir, AuxiliarySchemaFile))
+	if err != nil {
+		return nil, err
+	}
+	return &Schemas{Index: index, Auxiliary: aux}, nil
+}
+
+// Check validates the data directory dir. It returns an error only when the
+// directory cannot be read; everything wrong with its contents is in the
+// report.
+func Check(dir string, schemas *Schemas) (*Report, error) {
+	r := &Report{Dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, bibles.IndexFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		r.add(bibles.IndexFile, "", Error, "file is missing")
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !validate(r, bibles.IndexFile, schemas.Index, data) {
+		return r, nil
+	}
+	index, err := bibles.Parse(data)
+	if err != nil {
+		// The schema errors already say why
+		return r, nil
+	}
+
+	overrides := loadOverrides(dir)
+	known := map[string]bool{}
+	for i, b := range index.Bibles {
+		at := fmt.Sprintf("/bibles/%d", i)
+		if known[b.ID] {
+			r.add(bibles.IndexFile, at+"/id", Error, "duplicate Bible ID %q", b.ID)
+			continue
+		}
+		known[b.ID] = true
+
+		decl := declaration{name: b.Versification, file: bibles.IndexFile, pointer: at + "/versification"}
+		decl.override(overrides[b.ID], b.ID)
+		var scheme *versification.Scheme
+		if decl.name == "" {
+			r.add(bibles.IndexFile, at, Warning, "no versification; book IDs are only checked against OSIS")
+		} else if s, ok := versification.Lookup(decl.name); ok {
+			scheme = s
+		} else {
+			r.add(decl.file, decl.pointer, Error, "unknown versification %q, expected one of %s",
+				decl.name, strings.Join(versification.Names(), ", "))
+		}
+
+		file := filepath.ToSlash(filepath.Join(bibles.AuxiliaryDir, b.ID+".json"))
+		data, err := os.ReadFile(bibles.AuxiliaryPath(dir, b.ID))
+		if errors.Is(err, fs.ErrNotExist) {
+			r.add(bibles.IndexFile, at+"/id", Error, "%s is missing", file)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		schemaOK := validate(r, file, schemas.Auxiliary, data)
+		var aux bibles.Auxiliary
+		if err := json.Unmarshal(data, &aux); err != nil {
+			if schemaOK {
+				r.add(file, "", Error, "cannot be decoded: %v", err)
+			}
+			continue
+		}
+		checkAuxiliary(r, file, &aux, scheme)
+		if scheme != nil {
+			checkNumbering(r, file, &aux, scheme, decl)
+		}
+	}
+
+	if err := checkOrphans(r, dir, known); err != nil {
+		return nil, err
+	}
+	checkOverrides(r, dir, known)
+	return r, nil
+}
+
+// validate checks data against s, recording each schema error. It returns
+// false when data is not JSON at all.
+func validate(r *Report, file string, s *schema.Schema, data []byte) bool {
+	errs, err := s.Validate(data)
+	if err != nil {
+		r.add(file, "", Error, "invalid JSON: %v", err)
+		return false
+	}
+	for _, e := range errs {
+		r.add(file, e.Pointer, Error, "%s", e.Message)
+	}
+	return true
+}
+
+// checkAuxiliary applies the rules the auxiliary schema cannot express.
+// scheme is nil when the Bible declares no known versification.
+func checkAuxiliary(r *Report, file string, aux *bibles.Auxiliary, scheme *versification.Scheme) {
+	seen := map[string]int{}
+	for i, book := range aux.Books {
+		at := fmt.Sprintf("/books/%d", i)
+		if first, ok := seen[book.ID]; ok {
+			r.add(file, at+"/id", Error, "duplicate book %s, first at /books/%d", book.ID, first)
+		} else {
+			seen[book.ID] = i
+		}
+		checkBook(r, file, at, book.ID, book.Testament, scheme)
+
+		prev := 0
+		for j, ch := range book.Chapters {
+			chAt := fmt.Sprintf("%s/chapters/%d", at, j)
+			if ch.Number <= prev {
+				r.add(file, chAt+"/number", Error, "%s chapter %d follows chapter %d", book.ID, ch.Number, prev)
+			}
+			prev = ch.Number
+
+			prevVerse := 0
+			for k, v := range ch.Verses {
+				if v.Number <= prevVerse {
+					r.add(file, fmt.Sprintf("%s/verses/%d/number", chAt, k), Error, "%s %d verse %d follows verse %d",
+						book.ID, ch.Number, v.Number, prevVerse)
+				}
+				prevVerse = v.Number
+			}
+		}
+	}
+
+	for i, book := range aux.ExcludedBooks {
+		at := fmt.Sprintf("/excludedBooks/%d", i)
+		if first, ok := seen[book.ID]; ok {
+			r.add(file, at+"/id", Error, "%s is excluded but has text at /books/%d", book.ID, first)
+		}
+		checkBook(r, file, at, book.ID, book.Testament, nil)
+	}
+}
+
+// checkBook checks that an OSIS book ID exists, belongs to the scheme when
+// there is one, and suits its testament. A book whose text the scheme
+// numbers as part of another, as kjva numbers the Epistle of Jeremiah as
+// Baruch 6, belongs to it.
+func checkBook(r *Report, file, at, id, testament string, scheme *versification.Scheme) {
+	info, ok := versification.LookupBook(id)
+	if !ok {
+		r.add(file, at+"/id", Error, "unknown OSIS book %q", id)
+		return
+	}
+	if scheme != nil && !scheme.Holds(id) {
+		r.add(file, at+"/id", Error, "%s is not in the %s versification", id, scheme.Name)
+	}
+	if testament != "" && !info.FitsTestament(versification.Testament(testament)) {
+		r.add(file, at+"/testament", Error, "%s belongs to %s, not %s", id, info.Testament, testament)
+	}
+}
+
+// declaration is where a Bible's versification is declared: bibles.json,
+// or bibles_override.json when an override replaces it.
+type declaration struct {
+	name, file, pointer string
+}
+
+// override applies the versification field of a Bible's override, matched
+// without regard to case as Hugo's merge matches it.
+func (d *declaration) override(o bibles.Override, id string) {
+	for key, value := range o {
+		if !strings.EqualFold(key, "versification") {
+			continue
+		}
+		var name *string
+		if err := json.Unmarshal(value, &name); err != nil {
+			continue
+		}
+		d.name = ""
+		if name != nil {
+			d.name = *name
+		}
+		d.file = bibles.OverrideFile
+		d.pointer = "/overrides/" + schema.Escape(id) + "/" + schema.Escape(key)
+	}
+}
+
+// checkNumbering checks that the scheme has every verse of the text, which
+// the compare views need to align the Bible with others. Text that another
+// scheme numbers throughout has the wrong versification declared.
+func checkNumbering(r *Report, file string, aux *bibles.Auxiliary, scheme *versification.Scheme, decl declaration) {
+	var refs []versification.Ref
+	var pointers []string
+	for i, book := range aux.Books {
+		for j, ch := range book.Chapters {
+			for k, v := range ch.Verses {
+				refs = append(refs, versification.Ref{Book: book.ID, Chapter: ch.Number, Verse: v.Number})
+				pointers = append(pointers, fmt.Sprintf("/books/%d/chapters/%d/verses/%d/number", i, j, k))
+			}
+		}
+	}
+	n := scheme.Unnumbered(refs)
+	if n < 0 {
+		return
+	}
+	var fits []string
+	for _, name := range versification.Names() {
+		if s, _ := versification.Lookup(name); s.Unnumbered(refs) < 0 {
+			fits = append(fits, name)
+		}
+	}
+	if len(fits) > 0 {
+		r.add(decl.file, decl.pointer, Error, "text is numbered as %s, not %s: %s is not a %s verse",
+			strings.Join(fits, " or "), scheme.Name, refs[n], scheme.Name)
+		return
+	}
+	r.add(file, pointers[n], Warning, "%s is not a verse of the %s versification; the compare views will not align this Bible",
+		refs[n], scheme.Name)
+}
+
+// checkOrphans warns about auxiliary files no bibles.json entry uses; the
+// site never builds them.
+func checkOrphans(r *Report, dir string, known map[string]bool) error {
+	entries, err := os.ReadDir(filepath.Join(dir, bibles.AuxiliaryDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if ok && !e.IsDir() && !known[id] {
+			r.add(filepath.ToSlash(filepath.Join(bibles.AuxiliaryDir, e.Name())), "", Warning,
+				"no bibles.json entry has ID %q", id)
+		}
+	}
+	return nil
+}
+
+// checkOverrides warns about overrides for Bibles bibles.json does not have,
+// which the templates silently ignore.
+func checkOverrides(r *Report, dir string, known map[string]bool) {
+	data, err := os.ReadFile(filepath.Join(dir, bibles.OverrideFile))
+	if err != nil {
+		return
+	}
+	o, err := bibles.ParseOverrides(data)
+	if err != nil {
+		r.add(bibles.OverrideFile, "", Error, "cannot be decoded: %v", err)
+		return
+	}
+	ids := make([]string, 0, len(o.Overrides))
+	for id := range o.Overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			r.add(bibles.OverrideFile, "/overrides/"+schema.Escape(id), Warning, "no bibles.json entry has ID %q", id)
+		}
+	}
+}
+
+// loadOverrides returns the overrides of bibles_override.json by Bible ID,
+// or none when the file is missing or cannot be decoded; checkOverrides
+// reports the latter.
+func loadOverrides(dir string) map[string]bibles.Override {
+	data, err := os.ReadFile(filepath.Join(dir, bibles.OverrideFile))
+	if err != nil {
+		return nil
+	}
+	o, err := bibles.ParseOverrides(data)
+	if err != nil {
+		return nil
+	}
+	return o.Overrides
+}
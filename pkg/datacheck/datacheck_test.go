@@ -0,0 +1,298 @@
+package datacheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var (
+	schemaDir  = filepath.Join("..", "..", "static", "schemas")
+	exampleDir = filepath.Join("..", "..", "data", "example")
+)
+
+// cleanIndex is a bibles.json with one Bible whose text is cleanAux.
+const cleanIndex = `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"protestant"}]}`
+
+const cleanAux = `{"books":[
+  {"id":"Gen","name":"Genesis","testament":"OT","chapters":[
+    {"number":1,"verses":[{"number":1,"text":"a"},{"number":2,"text":"b"}]},
+    {"number":2,"verses":[{"number":1,"text":"c"}]}]},
+  {"id":"Matt","name":"Matthew","testament":"NT","chapters":[
+    {"number":1,"verses":[{"number":1,"text":"d"}]}]}]}`
+
+// malachi4 is text numbered with a fourth chapter of Malachi, which the
+// Hebrew numbering ends in chapter 3.
+const malachi4 = `{"books":[{"id":"Mal","name":"Malachi","testament":"OT","chapters":[
+  {"number":4,"verses":[{"number":1,"text":"a"}]}]}]}`
+
+// writeTree creates a data directory holding the given files.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func loadSchemas(t *testing.T) *Schemas {
+	t.Helper()
+	s, err := LoadSchemas(schemaDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// problems renders each problem as "file#pointer: severity".
+func problems(r *Report) []string {
+	var out []string
+	for _, p := range r.Problems {
+		out = append(out, p.File+"#"+p.Pointer+": "+string(p.Severity))
+	}
+	return out
+}
+
+// TestCheck verifies each semantic rule and where it points.
+func TestCheck(t *testing.T) {
+	schemas := loadSchemas(t)
+	aux := "bibles_auxiliary/t.json"
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string
+	}{
+		{"clean", map[string]string{"bibles.json": cleanIndex, aux: cleanAux}, nil},
+		{
+			"missing index",
+			map[string]string{},
+			[]string{"bibles.json#: error"},
+		},
+		{
+			"invalid index JSON",
+			map[string]string{"bibles.json": `{"bibles":`},
+			[]string{"bibles.json#: error"},
+		},
+		{
+			"schema error",
+			map[string]string{"bibles.json": `{"bibles":[{"id":"T","title":"T","abbrev":"T","versification":"protestant"}]}`},
+			[]string{"bibles.json#/bibles/0/id: error", "bibles.json#/bibles/0/id: error"},
+		},
+		{
+			"missing auxiliary",
+			map[string]string{"bibles.json": cleanIndex},
+			[]string{"bibles.json#/bibles/0/id: error"},
+		},
+		{
+			"duplicate Bible",
+			map[string]string{
+				"bibles.json": `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"protestant"},{"id":"t","title":"U","abbrev":"U"}]}`,
+				aux:           cleanAux,
+			},
+			[]string{"bibles.json#/bibles/1/id: error"},
+		},
+		{
+			"no versification",
+			map[string]string{"bibles.json": `{"bibles":[{"id":"t","title":"T","abbrev":"T"}]}`, aux: cleanAux},
+			[]string{"bibles.json#/bibles/0: warning"},
+		},
+		{
+			"unknown versification",
+			map[string]string{"bibles.json": `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"kjv"}]}`, aux: cleanAux},
+			[]string{"bibles.json#/bibles/0/versification: error"},
+		},
+		{
+			"duplicate book",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[
+				{"id":"Gen","name":"Genesis","chapters":[]},
+				{"id":"Gen","name":"Genesis","chapters":[]}]}`},
+			[]string{aux + "#/books/1/id: error"},
+		},
+		{
+			"chapters out of order",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Gen","name":"Genesis","chapters":[
+				{"number":2,"verses":[]},{"number":2,"verses":[]},{"number":1,"verses":[]}]}]}`},
+			[]string{aux + "#/books/0/chapters/1/number: error", aux + "#/books/0/chapters/2/number: error"},
+		},
+		{
+			"verses out of order",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Gen","name":"Genesis","chapters":[
+				{"number":1,"verses":[{"number":1,"text":"a"},{"number":3,"text":"b"},{"number":2,"text":"c"}]}]}]}`},
+			[]string{aux + "#/books/0/chapters/0/verses/2/number: error"},
+		},
+		{
+			"unknown OSIS book",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Genesis","name":"Genesis","chapters":[]}]}`},
+			[]string{aux + "#/books/0/id: error"},
+		},
+		{
+			"book outside versification",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Tob","name":"Tobit","testament":"AP","chapters":[]}]}`},
+			[]string{aux + "#/books/0/id: error"},
+		},
+		{
+			"book held within another",
+			map[string]string{
+				"bibles.json": `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"kjva","features":["StrongsNumbers"]}]}`,
+				aux:           `{"books":[{"id":"EpJer","name":"Epistle of Jeremiah","testament":"AP","chapters":[]}]}`,
+			},
+			nil,
+		},
+		{
+			"verse outside every versification",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Gen","name":"Genesis","chapters":[
+				{"number":1,"verses":[{"number":1,"text":"a"},{"number":99,"text":"b"}]}]}]}`},
+			[]string{aux + "#/books/0/chapters/0/verses/1/number: warning"},
+		},
+		{
+			"text numbered as another versification",
+			map[string]string{
+				"bibles.json": `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"leningrad"}]}`,
+				aux:           malachi4,
+			},
+			[]string{"bibles.json#/bibles/0/versification: error"},
+		},
+		{
+			"override corrects versification",
+			map[string]string{
+				"bibles.json":          `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"leningrad"}]}`,
+				aux:                    malachi4,
+				"bibles_override.json": `{"overrides":{"t":{"versification":"protestant"}}}`,
+			},
+			nil,
+		},
+		{
+			"override declares wrong versification",
+			map[string]string{
+				"bibles.json":          cleanIndex,
+				aux:                    malachi4,
+				"bibles_override.json": `{"overrides":{"t":{"Versification":"leningrad"}}}`,
+			},
+			[]string{"bibles_override.json#/overrides/t/Versification: error"},
+		},
+		{
+			"testament mismatch",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Matt","name":"Matthew","testament":"OT","chapters":[]}]}`},
+			[]string{aux + "#/books/0/testament: error"},
+		},
+		{
+			"deuterocanon filed as OT",
+			map[string]string{
+				"bibles.json": `{"bibles":[{"id":"t","title":"T","abbrev":"T","versification":"catholic"}]}`,
+				aux:           `{"books":[{"id":"Tob","name":"Tobit","testament":"OT","chapters":[]}]}`,
+			},
+			nil,
+		},
+		{
+			"excluded book with text",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Gen","name":"Genesis","chapters":[]}],
+				"excludedBooks":[{"id":"Gen","name":"Genesis","testament":"OT","reason":"x"},
+				                 {"id":"Rev","name":"Revelation","testament":"OT","reason":"x"}]}`},
+			[]string{aux + "#/excludedBooks/0/id: error", aux + "#/excludedBooks/1/testament: error"},
+		},
+		{
+			"auxiliary schema error",
+			map[string]string{"bibles.json": cleanIndex, aux: `{"books":[{"id":"Gen","name":"Genesis","chapters":[{"number":0,"verses":[]}]}]}`},
+			[]string{aux + "#/books/0/chapters/0/number: error", aux + "#/books/0/chapters/0/number: error"},
+		},
+		{
+			"orphan auxiliary",
+			map[string]string{"bibles.json": cleanIndex, aux: cleanAux, "bibles_auxiliary/u.json": cleanAux},
+			[]string{"bibles_auxiliary/u.json#: warning"},
+		},
+		{
+			"override for unknown Bible",
+			map[string]string{
+				"bibles.json":          cleanIndex,
+				aux:                    cleanAux,
+				"bibles_override.json": `{"overrides":{"t":{"title":"T2"},"a/b":{"title":"X"}}}`,
+			},
+			[]string{"bibles_override.json#/overrides/a~1b: warning"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Check(writeTree(t, tt.files), schemas)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := problems(r); !slices.Equal(got, tt.want) {
+				t.Errorf("problems = %q, want %q", got, tt.want)
+			}
+			errors := 0
+			for _, p := range r.Problems {
+				if p.Severity == Error {
+					errors++
+				}
+			}
+			if r.Errors != errors || r.Warnings != len(r.Problems)-errors {
+				t.Errorf("counts = %d errors, %d warnings for %d problems", r.Errors, r.Warnings, len(r.Problems))
+			}
+		})
+	}
+}
+
+// TestReport verifies both output forms.
+func TestReport(t *testing.T) {
+	r, err := Check(writeTree(t, map[string]string{"bibles.json": cleanIndex}), loadSchemas(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Dir = "data"
+
+	var text bytes.Buffer
+	if err := r.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	want := "bibles.json#/bibles/0/id: error: bibles_auxiliary/t.json is missing\ndata: 1 errors, 0 warnings\n"
+	if text.String() != want {
+		t.Errorf("text report = %q, want %q", text.String(), want)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Errors != 1 || len(decoded.Problems) != 1 || decoded.Problems[0].Pointer != "/bibles/0/id" {
+		t.Errorf("JSON report = %s", buf.String())
+	}
+
+	empty := &Report{Dir: "data"}
+	buf.Reset()
+	if err := empty.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"problems": []`) {
+		t.Errorf("empty JSON report has no problems array: %s", buf.String())
+	}
+}
+
+// TestExample checks the vendored texts. The example index itself has
+// known problems, Bibles without text among them, so only the auxiliary
+// files are held to a clean result.
+func TestExample(t *testing.T) {
+	r, err := Check(exampleDir, loadSchemas(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range r.Problems {
+		if p.Severity == Error && p.File != "bibles.json" {
+			t.Errorf("unexpected problem: %s", p)
+		}
+	}
+}
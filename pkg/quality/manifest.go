@@ -0,0 +1,220 @@
+package quality
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/bibles"
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/versification"
+)
+
+// ManifestDir is the directory of a data directory holding each Bible's
+// content manifest as {id}.json, which Hugo reads as
+// .Site.Data.bibles_content.
+const ManifestDir = "bibles_content"
+
+// Manifest is the content manifest of one Bible.
+type Manifest struct {
+	Bible  string `json:"bible"`
+	Status Status `json:"status"`
+	// Verses counts the Bible's verses by status.
+	Verses Counts `json:"verses"`
+	Books  []Book `json:"books"`
+	// Versifications are the schemes, in versification.Names order, that
+	// number every verse of the text. The compare views only align a Bible
+	// whose declared versification is one of them.
+	Versifications []string `json:"versifications,omitempty"`
+	// Findings are the verses and bibles.json fields that are not plain
+	// real text.
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Book is a book of a manifest. Its status is that of its chapters.
+type Book struct {
+	ID       string    `json:"id"`
+	Status   Status    `json:"status"`
+	Verses   Counts    `json:"verses"`
+	Chapters []Chapter `json:"chapters"`
+}
+
+// Chapter is a chapter of a manifest. Its status is that of its verses.
+type Chapter struct {
+	Number int    `json:"number"`
+	Status Status `json:"status"`
+}
+
+// Finding is one verse or metadata field that is not real text.
+type Finding struct {
+	// File is relative to the data directory, with forward slashes.
+	File    string `json:"file"`
+	Pointer string `json:"pointer"`
+	Status  Status `json:"status"`
+	Reason  string `json:"reason"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s#%s: %s: %s", f.File, f.Pointer, f.Status, f.Reason)
+}
+
+// Book returns the manifest entry for the book with the given OSIS ID.
+func (m *Manifest) Book(id string) (*Book, bool) {
+	for i := range m.Books {
+		if m.Books[i].ID == id {
+			return &m.Books[i], true
+		}
+	}
+	return nil, false
+}
+
+// Analyze classifies the text of the Bible at position i of x; aux is nil
+// when the Bible has no auxiliary file, which leaves only its metadata to
+// check. Metadata findings point into x as it was read, before any
+// overrides.
+func Analyze(x *bibles.Index, i int, aux *bibles.Auxiliary) *Manifest {
+	b := &x.Bibles[i]
+	m := &Manifest{Bible: b.ID}
+
+	at := fmt.Sprintf("/bibles/%d", i)
+	for _, field := range []struct{ name, value string }{
+		{"title", b.Title},
+		{"description", b.Description},
+		{"licenseText", b.LicenseText},
+	} {
+		if reason, ok := Suspect(field.value); ok {
+			m.Findings = append(m.Findings, Finding{File: bibles.IndexFile, Pointer: at + "/" + field.name, Status: Suspicious, Reason: reason})
+		}
+	}
+
+	file := filepath.ToSlash(filepath.Join(bibles.AuxiliaryDir, b.ID+".json"))
+	var books Counts
+	var refs []versification.Ref
+	if aux == nil {
+		aux = &bibles.Auxiliary{}
+	}
+	for bi, book := range aux.Books {
+		entry := Book{ID: book.ID, Chapters: []Chapter{}}
+		var chapters Counts
+		for ci, ch := range book.Chapters {
+			var verses Counts
+			for vi, v := range ch.Verses {
+				refs = append(refs, versification.Ref{Book: book.ID, Chapter: ch.Number, Verse: v.Number})
+				status, reason := ClassifyVerse(v.Text)
+				verses.Add(status, 1)
+				if status != Real {
+					m.Findings = append(m.Findings, Finding{
+						File:    file,
+						Pointer: fmt.Sprintf("/books/%d/chapters/%d/verses/%d/text", bi, ci, vi),
+						Status:  status,
+						Reason:  reason,
+					})
+				}
+			}
+			status := verses.Status()
+			entry.Chapters = append(entry.Chapters, Chapter{Number: ch.Number, Status: status})
+			entry.Verses = entry.Verses.Plus(verses)
+			chapters.Add(status, 1)
+		}
+		entry.Status = chapters.Status()
+		m.Books = append(m.Books, entry)
+		m.Verses = m.Verses.Plus(entry.Verses)
+		books.Add(entry.Status, 1)
+	}
+	m.Status = books.Status()
+	if len(refs) > 0 {
+		for _, name := range versification.Names() {
+			if s, _ := versification.Lookup(name); s.Unnumbered(refs) < 0 {
+				m.Versifications = append(m.Versifications, name)
+			}
+		}
+	}
+	if m.Books == nil {
+		m.Books = []Book{}
+	}
+	return m
+}
+
+// Build analyzes every Bible in bibles.json in the data directory dir, in
+// bibles.json order, including those without an auxiliary file.
+func Build(dir string) ([]*Manifest, error) {
+	path := filepath.Join(dir, bibles.IndexFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	x, err := bibles.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	var manifests []*Manifest
+	for i, b := range x.Bibles {
+		aux, err := bibles.LoadAuxiliary(dir, b.ID)
+		if errors.Is(err, fs.ErrNotExist) {
+			aux = nil
+		} else if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, Analyze(x, i, aux))
+	}
+	return manifests, nil
+}
+
+// WriteManifests builds the manifests for dir and writes them to its
+// bibles_content directory, replacing any there.
+func WriteManifests(dir string) ([]*Manifest, error) {
+	manifests, err := Build(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ManifestDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", ManifestDir, err)
+	}
+	for _, m := range manifests {
+		data, err := m.Encode()
+		if err != nil {
+			return nil, err
+		}
+		path := ManifestPath(dir, m.Bible)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return manifests, nil
+}
+
+// Encode writes the manifest as the other data files are written:
+// two-space indentation, markup unescaped and a trailing newline.
+func (m *Manifest) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ManifestPath returns the path of a Bible's content manifest in dir.
+func ManifestPath(dir, id string) string {
+	return filepath.Join(dir, ManifestDir, id+".json")
+}
+
+// LoadManifest reads the content manifest of the Bible with the given ID
+// from a data directory.
+func LoadManifest(dir, id string) (*Manifest, error) {
+	path := ManifestPath(dir, id)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return &m, nil
+}
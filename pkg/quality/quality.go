@@ -0,0 +1,183 @@
+// Package quality classifies the text of each Bible in a data directory and
+// writes the content manifests the site's content adapter builds pages from.
+//
+// Every verse is real, a placeholder such as "Gen 1:2:" that a converter
+// left where a module has no text, empty, or suspicious: text with signs of
+// encoding damage, such as a replacement character, UTF-8 read as Latin-1,
+// or a word that mixes scripts. Chapters, books and Bibles take the status
+// of the majority of their parts; see Counts.Status.
+package quality
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Status is the classification of a verse, chapter, book or Bible.
+type Status string
+
+// Statuses.
+const (
+	Real        Status = "real"
+	Placeholder Status = "placeholder"
+	Empty       Status = "empty"
+	Suspicious  Status = "suspicious"
+)
+
+// HasText reports whether content of this status gets pages: real text,
+// including text that looks damaged, which is better shown than dropped.
+func (s Status) HasText() bool {
+	return s == Real || s == Suspicious
+}
+
+// Counts counts parts by status.
+type Counts struct {
+	Real        int `json:"real,omitempty"`
+	Placeholder int `json:"placeholder,omitempty"`
+	Empty       int `json:"empty,omitempty"`
+	Suspicious  int `json:"suspicious,omitempty"`
+}
+
+// Add counts n parts of status s.
+func (c *Counts) Add(s Status, n int) {
+	switch s {
+	case Real:
+		c.Real += n
+	case Placeholder:
+		c.Placeholder += n
+	case Empty:
+		c.Empty += n
+	case Suspicious:
+		c.Suspicious += n
+	}
+}
+
+// Plus returns the sum of two counts.
+func (c Counts) Plus(o Counts) Counts {
+	return Counts{
+		Real:        c.Real + o.Real,
+		Placeholder: c.Placeholder + o.Placeholder,
+		Empty:       c.Empty + o.Empty,
+		Suspicious:  c.Suspicious + o.Suspicious,
+	}
+}
+
+// Status is the status of a whole made of the counted parts. Empty parts do
+// not count against the rest. A whole with no text is a placeholder if any
+// part is, and empty otherwise. A whole with more placeholders than parts
+// with text is a placeholder too, so a converter's filler is not published
+// for the sake of a few real verses. Otherwise it is suspicious if any part
+// is, and real.
+func (c Counts) Status() Status {
+	text := c.Real + c.Suspicious
+	switch {
+	case text == 0 && c.Placeholder == 0:
+		return Empty
+	case c.Placeholder > text:
+		return Placeholder
+	case c.Suspicious > 0:
+		return Suspicious
+	default:
+		return Real
+	}
+}
+
+var (
+	markup = regexp.MustCompile(`<[^>]*>`)
+	// reference is a bare verse reference, optionally after a book name or
+	// OSIS ID: "Gen 1:2:", "1John 3:16", "Song of Solomon 2:1", "4:5".
+	reference = regexp.MustCompile(`^(?:(?:[1-4] ?)?\p{L}[\p{L}.' ]*\s)?\d+[:.]\d+:?$`)
+	// latin1 is UTF-8 decoded as Latin-1 or Windows-1252: "Ã©" for "é",
+	// "â€™" for "’".
+	latin1 = regexp.MustCompile(`[ÂÃ][\x{80}-\x{BF}‚ƒ„…†‡ˆ‰Š‹ŒŽ‘’“”•–—˜™š›œžŸ]|â€`)
+)
+
+// Plain returns verse text without OSIS markup or character references,
+// trimmed.
+func Plain(text string) string {
+	return strings.TrimSpace(html.UnescapeString(markup.ReplaceAllString(text, "")))
+}
+
+// ClassifyVerse classifies one verse's text, which may carry OSIS markup,
+// and says why when it is not real.
+func ClassifyVerse(text string) (Status, string) {
+	plain := Plain(text)
+	if !strings.ContainsFunc(plain, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) {
+		return Empty, "no text"
+	}
+	if reference.MatchString(plain) {
+		return Placeholder, fmt.Sprintf("only a reference: %q", plain)
+	}
+	if reason, ok := Suspect(plain); ok {
+		return Suspicious, reason
+	}
+	return Real, ""
+}
+
+// Suspect reports whether s shows signs of encoding damage and describes the
+// first it finds.
+func Suspect(s string) (string, bool) {
+	for _, r := range s {
+		switch {
+		case r == unicode.ReplacementChar:
+			return "replacement character U+FFFD", true
+		case unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r':
+			return fmt.Sprintf("control character U+%04X", r), true
+		}
+	}
+	if m := latin1.FindString(s); m != "" {
+		return fmt.Sprintf("%q looks like UTF-8 read as Latin-1", m), true
+	}
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsMark(r) }) {
+		if a, b, ok := mixedScripts(word); ok {
+			return fmt.Sprintf("%q mixes %s and %s letters", word, a, b), true
+		}
+	}
+	return "", false
+}
+
+// likelyScripts are tried before the rest of unicode.Scripts.
+var likelyScripts = []string{"Latin", "Greek", "Hebrew", "Cyrillic", "Arabic", "Syriac", "Coptic"}
+
+// mixedScripts reports the first two scripts a word's letters come from
+// when there is more than one.
+func mixedScripts(word string) (string, string, bool) {
+	first := ""
+	for _, r := range word {
+		script := scriptOf(r)
+		if script == "" {
+			continue
+		}
+		if first == "" {
+			first = script
+		} else if script != first {
+			return first, script, true
+		}
+	}
+	return "", "", false
+}
+
+// scriptOf names the script of a letter, or returns "" for characters that
+// belong to no single script, such as combining marks.
+func scriptOf(r rune) string {
+	if r < unicode.MaxASCII {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return ""
+	}
+	for _, name := range likelyScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	for name, table := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
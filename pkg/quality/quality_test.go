@@ -0,0 +1,226 @@
+package quality
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/bibles"
+)
+
+// exampleDir is the vendored data tree hugo.toml mounts.
+var exampleDir = filepath.Join("..", "..", "data", "example")
+
+// TestClassifyVerse verifies each status on the kinds of text converters
+// produce.
+func TestClassifyVerse(t *testing.T) {
+	tests := []struct {
+		text string
+		want Status
+	}{
+		{"In the beginning God created the heaven and the earth.", Real},
+		{"Jesus wept.", Real},
+		{"Amen.", Real},
+		{`<w lemma="strong:G2424">Jesus</w> <w lemma="strong:G1145">wept</w>.`, Real},
+		{"Ἐν ἀρχῇ ἦν ὁ λόγος", Real},
+		{"בְּרֵאשִׁית בָּרָא אֱלֹהִים", Real},
+		{"In principio creavit Deus cælum et terram.", Real},
+		{"Thou art my son &amp; heir.", Real},
+		{"Gen 1:2:", Placeholder},
+		{"Book 1:2:", Placeholder},
+		{"1John 3:16", Placeholder},
+		{"Song of Solomon 2:1", Placeholder},
+		{" 4:5: ", Placeholder},
+		{`<verse osisID="Gen.1.2"/>Gen 1:2:`, Placeholder},
+		{"", Empty},
+		{"   ", Empty},
+		{`<milestone type="x-p"/>`, Empty},
+		{"¶ —", Empty},
+		{"Godߴs name", Suspicious},
+		{"cafÃ©", Suspicious},
+		{"the Lordâ€™s", Suspicious},
+		{"broken � text", Suspicious},
+		{"bell\x07", Suspicious},
+		{"Latin with a Greek ο in a word: wοrd", Suspicious},
+	}
+	for _, tt := range tests {
+		got, reason := ClassifyVerse(tt.text)
+		if got != tt.want {
+			t.Errorf("ClassifyVerse(%q) = %s (%s), want %s", tt.text, got, reason, tt.want)
+		}
+		if (got == Real) != (reason == "") {
+			t.Errorf("ClassifyVerse(%q) = %s with reason %q", tt.text, got, reason)
+		}
+	}
+}
+
+// TestCountsStatus verifies how a whole takes the status of its parts.
+func TestCountsStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts Counts
+		want   Status
+	}{
+		{"nothing", Counts{}, Empty},
+		{"all empty", Counts{Empty: 3}, Empty},
+		{"all real", Counts{Real: 3}, Real},
+		{"real with empty", Counts{Real: 1, Empty: 9}, Real},
+		{"placeholders only", Counts{Placeholder: 2, Empty: 1}, Placeholder},
+		{"mostly placeholders", Counts{Real: 1, Placeholder: 2}, Placeholder},
+		{"half placeholders", Counts{Real: 2, Placeholder: 2}, Real},
+		{"suspicious counts as text", Counts{Suspicious: 1, Placeholder: 1}, Suspicious},
+		{"some suspicious", Counts{Real: 5, Suspicious: 1}, Suspicious},
+	}
+	for _, tt := range tests {
+		if got := tt.counts.Status(); got != tt.want {
+			t.Errorf("%s: Status() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestAnalyze verifies statuses roll up from verses to the Bible and every
+// finding points at its value.
+func TestAnalyze(t *testing.T) {
+	x := &bibles.Index{Bibles: []bibles.Bible{
+		{ID: "other"},
+		{ID: "t", Title: "T", LicenseText: "Jeromeߴs Vulgate"},
+	}}
+	verses := func(texts ...string) []bibles.Verse {
+		var vs []bibles.Verse
+		for i, text := range texts {
+			vs = append(vs, bibles.Verse{Number: i + 1, Text: text})
+		}
+		return vs
+	}
+	aux := &bibles.Auxiliary{Books: []bibles.Book{
+		{ID: "Gen", Chapters: []bibles.Chapter{
+			{Number: 1, Verses: verses("In the beginning.", "", "Gen 1:3:")},
+			{Number: 2, Verses: verses("Gen 2:1:", "Gen 2:2:")},
+			{Number: 3, Verses: verses()},
+		}},
+		{ID: "Obad", Chapters: []bibles.Chapter{
+			{Number: 1, Verses: verses("Obad 1:1:")},
+		}},
+		{ID: "Phlm", Chapters: []bibles.Chapter{
+			{Number: 1, Verses: verses("Amen.", "cafÃ©")},
+		}},
+	}}
+
+	m := Analyze(x, 1, aux)
+	if m.Bible != "t" || m.Status != Suspicious {
+		t.Errorf("Bible %s is %s, want t suspicious", m.Bible, m.Status)
+	}
+	want := map[string]Status{"Gen": Real, "Obad": Placeholder, "Phlm": Suspicious}
+	for _, b := range m.Books {
+		if b.Status != want[b.ID] {
+			t.Errorf("%s is %s, want %s", b.ID, b.Status, want[b.ID])
+		}
+	}
+	gen, _ := m.Book("Gen")
+	var chapters []string
+	for _, ch := range gen.Chapters {
+		chapters = append(chapters, string(ch.Status))
+	}
+	if got := strings.Join(chapters, " "); got != "real placeholder empty" {
+		t.Errorf("Gen chapters are %s", got)
+	}
+	if v := m.Verses; v != (Counts{Real: 2, Placeholder: 4, Empty: 1, Suspicious: 1}) {
+		t.Errorf("verse counts = %+v", v)
+	}
+
+	var findings []string
+	for _, f := range m.Findings {
+		findings = append(findings, f.File+"#"+f.Pointer+" "+string(f.Status))
+	}
+	wantFindings := []string{
+		"bibles.json#/bibles/1/licenseText suspicious",
+		"bibles_auxiliary/t.json#/books/0/chapters/0/verses/1/text empty",
+		"bibles_auxiliary/t.json#/books/0/chapters/0/verses/2/text placeholder",
+		"bibles_auxiliary/t.json#/books/0/chapters/1/verses/0/text placeholder",
+		"bibles_auxiliary/t.json#/books/0/chapters/1/verses/1/text placeholder",
+		"bibles_auxiliary/t.json#/books/1/chapters/0/verses/0/text placeholder",
+		"bibles_auxiliary/t.json#/books/2/chapters/0/verses/1/text suspicious",
+	}
+	if got, want := strings.Join(findings, "\n"), strings.Join(wantFindings, "\n"); got != want {
+		t.Errorf("findings:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Leningrad has no Philemon
+	if got := strings.Join(m.Versifications, " "); got != "catholic kjva nrsv orthodox protestant" {
+		t.Errorf("versifications = %s", got)
+	}
+	hebrew := &bibles.Auxiliary{Books: []bibles.Book{
+		{ID: "Mal", Chapters: []bibles.Chapter{{Number: 3, Verses: []bibles.Verse{{Number: 24, Text: "Amen."}}}}},
+	}}
+	if got := strings.Join(Analyze(x, 1, hebrew).Versifications, " "); got != "leningrad orthodox" {
+		t.Errorf("versifications of Mal 3:24 = %s", got)
+	}
+
+	if m := Analyze(x, 0, nil); m.Status != Empty || len(m.Books) != 0 || len(m.Findings) != 0 || m.Versifications != nil {
+		t.Errorf("Bible without text = %+v", m)
+	}
+}
+
+// TestWriteManifests verifies manifests round-trip through the data
+// directory.
+func TestWriteManifests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(bibles.IndexFile, `{"bibles":[{"id":"a","title":"A","abbrev":"A"},{"id":"b","title":"B","abbrev":"B"}]}`)
+	write(filepath.Join(bibles.AuxiliaryDir, "a.json"), `{"books":[{"id":"Gen","name":"Genesis","chapters":[{"number":1,"verses":[{"number":1,"text":"Amen."}]}]}]}`)
+
+	written, err := WriteManifests(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 2 {
+		t.Fatalf("wrote %d manifests, want 2", len(written))
+	}
+	for _, w := range written {
+		m, err := LoadManifest(dir, w.Bible)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Status != w.Status || len(m.Books) != len(w.Books) {
+			t.Errorf("loaded %s = %+v, wrote %+v", w.Bible, m, w)
+		}
+	}
+	if a, _ := LoadManifest(dir, "a"); a.Status != Real {
+		t.Errorf("a is %s, want real", a.Status)
+	}
+}
+
+// TestExampleManifests verifies the manifests built from the example tree
+// classify the Bibles whose text is checked in and record the damaged
+// apostrophes in the example metadata.
+func TestExampleManifests(t *testing.T) {
+	manifests, err := Build(exampleDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suspicious := map[string]bool{}
+	for _, m := range manifests {
+		if m.Bible == "tyndale" && (m.Status != Real || len(m.Books) == 0) {
+			t.Errorf("tyndale is %s with %d books, want real", m.Status, len(m.Books))
+		}
+		for _, f := range m.Findings {
+			if f.File == bibles.IndexFile {
+				suspicious[m.Bible+f.Pointer[strings.LastIndex(f.Pointer, "/"):]] = true
+			}
+		}
+	}
+	for _, want := range []string{"asv/licenseText", "vulgate/description", "vulgate/licenseText"} {
+		if !suspicious[want] {
+			t.Errorf("no finding for %s", want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package reference
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/versification"
+)
+
+// Format writes passages as a reference list in the language, such as
+// "John 3:16, 18; Romans 8" in English or "Johannes 3,16.18; Römer 8" in
+// German. A passage in the book before it leaves out the book's name, and a
+// verse or range of verses in the chapter the passage before it ends in
+// joins its list; passages outside kjva numbering name their versification.
+// Parse reads the result back to the same passages.
+func (l *Language) Format(passages []Passage) string {
+	itoa := strconv.Itoa
+	var b strings.Builder
+	for i, p := range passages {
+		s := p.scheme()
+		var prev *Passage
+		if i > 0 && passages[i-1].Start.Book == p.Start.Book && passages[i-1].Scheme == p.Scheme {
+			prev = &passages[i-1]
+		}
+
+		single := s.Chapters(p.Start.Book) == 1
+		within := p.Start.Chapter == p.End.Chapter && !p.wholeChapters(s)
+		var text string
+		switch {
+		case p.wholeBook(s):
+		case p.wholeChapters(s) && p.Start.Chapter == p.End.Chapter:
+			text = itoa(p.Start.Chapter)
+		case p.wholeChapters(s):
+			text = itoa(p.Start.Chapter) + "–" + itoa(p.End.Chapter)
+		case within && p.Start.Verse == p.End.Verse:
+			text = itoa(p.Start.Verse)
+		case within:
+			text = itoa(p.Start.Verse) + "–" + itoa(p.End.Verse)
+		default:
+			text = itoa(p.Start.Chapter) + l.verse + itoa(p.Start.Verse) + "–" +
+				itoa(p.End.Chapter) + l.verse + itoa(p.End.Verse)
+		}
+		if within && !single && (prev == nil || !prev.continues(p)) {
+			text = itoa(p.Start.Chapter) + l.verse + text
+		}
+
+		switch {
+		case prev != nil && within && prev.continues(p):
+			b.WriteString(l.list)
+			if l.list == "," {
+				b.WriteString(" ")
+			}
+			b.WriteString(text)
+			continue
+		case i > 0:
+			b.WriteString(l.qualifier(passages[i-1]) + "; ")
+		}
+		if prev == nil || text == "" {
+			b.WriteString(l.Name(p.Start.Book))
+			if text != "" {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(text)
+	}
+	if len(passages) > 0 {
+		b.WriteString(l.qualifier(passages[len(passages)-1]))
+	}
+	return b.String()
+}
+
+// continues reports whether q, in the same book and versification, can
+// join p's list of verses: p ends in the chapter q lies in, and neither is
+// whole chapters.
+func (p *Passage) continues(q Passage) bool {
+	s := p.scheme()
+	return !p.wholeChapters(s) && p.End.Chapter == q.Start.Chapter
+}
+
+// qualifier returns the versification Format writes after p, if any.
+func (l *Language) qualifier(p Passage) string {
+	if label, ok := labels[p.Scheme]; ok {
+		return " (" + label + ")"
+	}
+	return ""
+}
+
+// URL returns the path of the site's page for the chapter a passage starts
+// in, in the language's part of the site: "/bible/kjva/john/3/" for John
+// 3:16 in English and "/de/bible/kjva/john/3/" in German, for basePath
+// "/bible" as params.michael.basePath sets it and the Bible "kjva". scheme is
+// the versification the Bible's bibles.json entry declares; the passage is
+// renumbered into it first, so "Ps 23 (LXX)" opens Psalm 24 of a kjva Bible.
+// An empty scheme keeps the passage's numbering.
+func (l *Language) URL(basePath, bible, scheme string, p Passage) (string, error) {
+	if scheme != "" && scheme != p.scheme().Name {
+		if _, ok := versification.Lookup(scheme); !ok {
+			return "", fmt.Errorf("unknown versification %q", scheme)
+		}
+		q, ok := p.In(scheme)
+		if !ok {
+			return "", fmt.Errorf("%s has no text of %s", scheme, p.OSIS())
+		}
+		p = q
+	}
+	prefix := ""
+	if l.Code != DefaultLanguage {
+		prefix = "/" + l.Code
+	}
+	return prefix + strings.TrimSuffix(basePath, "/") + "/" + bible + "/" +
+		strings.ToLower(p.Start.Book) + "/" + strconv.Itoa(p.Start.Chapter) + "/", nil
+}
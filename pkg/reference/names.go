@@ -0,0 +1,350 @@
+package reference
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultLanguage is the language whose pages have no language prefix.
+const DefaultLanguage = "en"
+
+// nameKey is the prefix of the i18n IDs that name books, as in bookNameGen.
+const nameKey = "bookName"
+
+// minPrefix is the fewest letters a shortened book name may have.
+const minPrefix = 2
+
+// english names every book, including the deuterocanon the i18n files do
+// not translate, with the abbreviations and other names references use for
+// it beyond its OSIS ID and prefixes of its name.
+var english = []struct {
+	id, name string
+	aliases  []string
+}{
+	{"Gen", "Genesis", []string{"Gn", "Ge"}},
+	{"Exod", "Exodus", []string{"Ex", "Exo"}},
+	{"Lev", "Leviticus", []string{"Lv", "Le"}},
+	{"Num", "Numbers", []string{"Nm", "Nu", "Numb"}},
+	{"Deut", "Deuteronomy", []string{"Dt", "De"}},
+	{"Josh", "Joshua", []string{"Jos", "Jsh"}},
+	{"Judg", "Judges", []string{"Jdg", "Jg", "Jdgs"}},
+	{"Ruth", "Ruth", []string{"Ru", "Rth"}},
+	{"1Sam", "1 Samuel", []string{"1 Sm", "1 Sa"}},
+	{"2Sam", "2 Samuel", []string{"2 Sm", "2 Sa"}},
+	{"1Kgs", "1 Kings", []string{"1 Ki", "1 Kg"}},
+	{"2Kgs", "2 Kings", []string{"2 Ki", "2 Kg"}},
+	{"1Chr", "1 Chronicles", []string{"1 Ch", "1 Chron"}},
+	{"2Chr", "2 Chronicles", []string{"2 Ch", "2 Chron"}},
+	{"Ezra", "Ezra", []string{"Ezr"}},
+	{"Neh", "Nehemiah", []string{"Ne"}},
+	{"Esth", "Esther", []string{"Est", "Es"}},
+	{"Job", "Job", []string{"Jb"}},
+	{"Ps", "Psalms", []string{"Psalm", "Pss", "Psa", "Psm", "Pslm"}},
+	{"Prov", "Proverbs", []string{"Pr", "Prv"}},
+	{"Eccl", "Ecclesiastes", []string{"Eccles", "Ecc", "Ec", "Qoh", "Qoheleth"}},
+	{"Song", "Song of Solomon", []string{"Song of Songs", "Canticles", "Cant", "SS", "Sg", "SOS"}},
+	{"Isa", "Isaiah", []string{"Is"}},
+	{"Jer", "Jeremiah", []string{"Je", "Jr"}},
+	{"Lam", "Lamentations", []string{"La"}},
+	{"Ezek", "Ezekiel", []string{"Eze", "Ezk"}},
+	{"Dan", "Daniel", []string{"Dn", "Da"}},
+	{"Hos", "Hosea", []string{"Ho"}},
+	{"Joel", "Joel", []string{"Jl"}},
+	{"Amos", "Amos", []string{"Am"}},
+	{"Obad", "Obadiah", []string{"Ob", "Oba"}},
+	{"Jonah", "Jonah", []string{"Jon", "Jnh"}},
+	{"Mic", "Micah", []string{"Mi"}},
+	{"Nah", "Nahum", []string{"Na"}},
+	{"Hab", "Habakkuk", []string{"Hb"}},
+	{"Zeph", "Zephaniah", []string{"Zep", "Zp"}},
+	{"Hag", "Haggai", []string{"Hg"}},
+	{"Zech", "Zechariah", []string{"Zec", "Zc"}},
+	{"Mal", "Malachi", []string{"Ml"}},
+	{"Matt", "Matthew", []string{"Mt", "Mat"}},
+	{"Mark", "Mark", []string{"Mk", "Mr", "Mrk"}},
+	{"Luke", "Luke", []string{"Lk", "Lu"}},
+	{"John", "John", []string{"Jn", "Jhn", "Joh"}},
+	{"Acts", "Acts", []string{"Ac", "Act", "Acts of the Apostles"}},
+	{"Rom", "Romans", []string{"Ro", "Rm"}},
+	{"1Cor", "1 Corinthians", []string{"1 Co"}},
+	{"2Cor", "2 Corinthians", []string{"2 Co"}},
+	{"Gal", "Galatians", []string{"Ga"}},
+	{"Eph", "Ephesians", []string{"Ephes"}},
+	{"Phil", "Philippians", []string{"Php", "Pp"}},
+	{"Col", "Colossians", nil},
+	{"1Thess", "1 Thessalonians", []string{"1 Th", "1 Thes"}},
+	{"2Thess", "2 Thessalonians", []string{"2 Th", "2 Thes"}},
+	{"1Tim", "1 Timothy", []string{"1 Ti", "1 Tm"}},
+	{"2Tim", "2 Timothy", []string{"2 Ti", "2 Tm"}},
+	{"Titus", "Titus", []string{"Tit"}},
+	{"Phlm", "Philemon", []string{"Philem", "Phm", "Pm"}},
+	{"Heb", "Hebrews", nil},
+	{"Jas", "James", []string{"Jm"}},
+	{"1Pet", "1 Peter", []string{"1 Pe", "1 Pt"}},
+	{"2Pet", "2 Peter", []string{"2 Pe", "2 Pt"}},
+	{"1John", "1 John", []string{"1 Jn", "1 Jo", "1 Jhn"}},
+	{"2John", "2 John", []string{"2 Jn", "2 Jo", "2 Jhn"}},
+	{"3John", "3 John", []string{"3 Jn", "3 Jo", "3 Jhn"}},
+	{"Jude", "Jude", []string{"Jud", "Jd"}},
+	{"Rev", "Revelation", []string{"Re", "Rv", "Revelations", "Apocalypse", "Apoc"}},
+	{"Tob", "Tobit", []string{"Tb"}},
+	{"Jdt", "Judith", []string{"Jth"}},
+	{"AddEsth", "Additions to Esther", []string{"Add Esth", "Rest of Esther"}},
+	{"EsthGr", "Greek Esther", []string{"Esth Gr"}},
+	{"Wis", "Wisdom of Solomon", []string{"Wisdom", "Wisd", "Ws"}},
+	{"Sir", "Sirach", []string{"Ecclesiasticus", "Ecclus", "Ben Sira"}},
+	{"Bar", "Baruch", nil},
+	{"EpJer", "Letter of Jeremiah", []string{"Epistle of Jeremiah", "Ep Jer", "Let Jer"}},
+	{"PrAzar", "Prayer of Azariah", []string{"Pr Azar", "Song of the Three", "Sg Three"}},
+	{"Sus", "Susanna", nil},
+	{"Bel", "Bel and the Dragon", nil},
+	{"1Macc", "1 Maccabees", []string{"1 Mac", "1 Ma"}},
+	{"2Macc", "2 Maccabees", []string{"2 Mac", "2 Ma"}},
+	{"3Macc", "3 Maccabees", []string{"3 Mac", "3 Ma"}},
+	{"4Macc", "4 Maccabees", []string{"4 Mac", "4 Ma"}},
+	{"1Esd", "1 Esdras", []string{"1 Esdr"}},
+	{"2Esd", "2 Esdras", []string{"2 Esdr"}},
+	{"PrMan", "Prayer of Manasseh", []string{"Pr Man", "Prayer of Manasses"}},
+	{"AddPs", "Psalm 151", nil},
+	{"Odes", "Odes", nil},
+	{"PssSol", "Psalms of Solomon", []string{"Pss Sol", "Ps Sol"}},
+}
+
+var (
+	// englishNames maps each book's OSIS ID to its English name.
+	englishNames = map[string]string{}
+	// englishKeys maps normalized OSIS IDs, English names and aliases to
+	// books.
+	englishKeys = map[string]string{}
+	// englishNameKeys maps normalized English names alone to books, for
+	// prefix matches.
+	englishNameKeys = map[string][]string{}
+)
+
+func init() {
+	for _, b := range english {
+		englishNames[b.id] = b.name
+		englishNameKeys[normalizeName(b.name)] = []string{b.id}
+		for _, name := range append([]string{b.id, b.name}, b.aliases...) {
+			key := normalizeName(name)
+			if other, ok := englishKeys[key]; ok && other != b.id {
+				panic(fmt.Sprintf("reference: %q names both %s and %s", name, other, b.id))
+			}
+			englishKeys[key] = b.id
+		}
+	}
+}
+
+// ordinal matches a Roman numeral or English ordinal before a book name.
+var ordinal = regexp.MustCompile(`^(iv|iii|ii|i|first|second|third|fourth|1st|2nd|3rd|4th)[\s.]+`)
+
+var ordinals = map[string]string{
+	"i": "1", "ii": "2", "iii": "3", "iv": "4",
+	"first": "1", "second": "2", "third": "3", "fourth": "4",
+	"1st": "1", "2nd": "2", "3rd": "3", "4th": "4",
+}
+
+// normalizeName reduces a book name to the form names are compared in:
+// lowercase letters, marks and digits, with a leading Roman numeral or
+// English ordinal as a digit, so "II Cor.", "2 Cor" and "2cor" agree.
+func normalizeName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if m := ordinal.FindStringSubmatch(s); m != nil && len(m[0]) < len(s) {
+		s = ordinals[m[1]] + s[len(m[0]):]
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// Language reads and writes references in one of the languages in i18n/.
+type Language struct {
+	// Code is the i18n file name without .toml, such as "de" or "gez".
+	Code string
+	// Names maps OSIS book IDs to the names the language gives them.
+	Names map[string]string
+
+	// keys maps normalized names to the books they name.
+	keys map[string][]string
+	// verse separates a chapter from a verse, and list verses in a list.
+	verse, list string
+	// item matches the numbers of one reference; see itemPattern.
+	item *regexp.Regexp
+}
+
+// commaLanguages write a comma between chapter and verse, and a period
+// between the verses of a list: "Joh 3,16.18".
+var commaLanguages = map[string]bool{"cs": true, "de": true, "hu": true, "pl": true}
+
+// NewLanguage returns the language with the given code and book names by
+// OSIS ID. Books it does not name keep their English names.
+func NewLanguage(code string, names map[string]string) *Language {
+	l := &Language{Code: code, Names: names, keys: map[string][]string{}, verse: ":", list: ","}
+	if commaLanguages[code] {
+		l.verse, l.list = ",", "."
+	}
+	l.item = itemPattern(l.verse)
+	for _, id := range sortedKeys(names) {
+		key := normalizeName(names[id])
+		l.keys[key] = append(l.keys[key], id)
+	}
+	return l
+}
+
+// Name returns the name the language gives a book, its English name when it
+// has none, or the OSIS ID of a book the package does not know.
+func (l *Language) Name(book string) string {
+	if name, ok := l.Names[book]; ok {
+		return name
+	}
+	if name, ok := englishNames[book]; ok {
+		return name
+	}
+	return book
+}
+
+// Book returns the OSIS ID of the book a name refers to. It tries, in order,
+// the language's names, unambiguous prefixes of them of at least two
+// letters, OSIS IDs and English names and abbreviations, and unambiguous
+// prefixes of English names.
+func (l *Language) Book(name string) (string, error) {
+	key := normalizeName(name)
+	if key == "" {
+		return "", fmt.Errorf("no book name in %q", name)
+	}
+	if ids := l.keys[key]; len(ids) == 1 {
+		return ids[0], nil
+	}
+	local := prefixed(key, l.keys)
+	if len(local) == 1 {
+		return local[0], nil
+	}
+	if id, ok := englishKeys[key]; ok {
+		return id, nil
+	}
+	if len(local) > 1 {
+		return "", fmt.Errorf("ambiguous book %q: %s", name, strings.Join(local, ", "))
+	}
+	switch ids := prefixed(key, englishNameKeys); len(ids) {
+	case 0:
+		return "", fmt.Errorf("unknown book %q", name)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous book %q: %s", name, strings.Join(ids, ", "))
+	}
+}
+
+// prefixed returns the books whose names in keys start with key, sorted.
+func prefixed(key string, keys map[string][]string) []string {
+	if utf8.RuneCountInString(key) < minPrefix {
+		return nil
+	}
+	seen := map[string]bool{}
+	for k, ids := range keys {
+		if strings.HasPrefix(k, key) {
+			for _, id := range ids {
+				seen[id] = true
+			}
+		}
+	}
+	return sortedKeys(seen)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadLanguage reads the book names of one language from the i18n directory
+// dir.
+func LoadLanguage(dir, code string) (*Language, error) {
+	path := filepath.Join(dir, code+".toml")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+	strs, err := parseI18n(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	names := map[string]string{}
+	for id, s := range strs {
+		if book, ok := strings.CutPrefix(id, nameKey); ok && book != "" {
+			names[book] = s
+		}
+	}
+	return NewLanguage(code, names), nil
+}
+
+// LoadLanguages reads every language in the i18n directory dir, English
+// first and the rest by code.
+func LoadLanguages(dir string) ([]*Language, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no languages in %s", dir)
+	}
+	var langs []*Language
+	for _, path := range paths {
+		l, err := LoadLanguage(dir, strings.TrimSuffix(filepath.Base(path), ".toml"))
+		if err != nil {
+			return nil, err
+		}
+		langs = append(langs, l)
+	}
+	sort.SliceStable(langs, func(i, j int) bool {
+		if (langs[i].Code == DefaultLanguage) != (langs[j].Code == DefaultLanguage) {
+			return langs[i].Code == DefaultLanguage
+		}
+		return langs[i].Code < langs[j].Code
+	})
+	return langs, nil
+}
+
+// parseI18n reads the subset of TOML the i18n files use: a [id] table per
+// string holding a quoted other = "..." value.
+func parseI18n(f *os.File) (map[string]string, error) {
+	strs := map[string]string{}
+	id := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			id = strings.Trim(line, "[] ")
+		case strings.HasPrefix(line, "other"):
+			_, value, ok := strings.Cut(line, "=")
+			if !ok || id == "" {
+				return nil, fmt.Errorf("line %d: other outside a table", n)
+			}
+			s, err := strconv.Unquote(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			strs[id] = s
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", n, line)
+		}
+	}
+	return strs, scanner.Err()
+}
@@ -0,0 +1,408 @@
+// Package reference reads Scripture references as people write them, such
+// as "Gen 1:1-3; Jn 3:16", "1 Cor 13" or "Ps 23:1–4 (LXX)", into passages
+// of OSIS verses, and writes passages back out as OSIS references, as text
+// in any language in i18n/ and as the URLs of the site's chapter pages.
+//
+// Books are recognized by the names the i18n files give them under
+// bookName{OSIS ID}, by unambiguous prefixes of those names, and by OSIS IDs
+// and the usual English names and abbreviations. Verse numbers follow the
+// kjva versification unless a reference names another in parentheses after
+// it: LXX for orthodox, MT for leningrad, Vulg for catholic or NRSV for
+// nrsv. A book kjva lacks, such as the Letter of Jeremiah, is read in
+// orthodox.
+package reference
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/versification"
+)
+
+// Passage is a verse, a range of verses or whole chapters or a whole book,
+// in one book.
+type Passage struct {
+	// Start and End are the first and last verse; whole chapters and books
+	// run from the first verse of the first chapter to the last verse of the
+	// last.
+	Start, End versification.Ref
+	// Scheme is the versification the verse numbers follow.
+	Scheme string
+}
+
+// qualifiers maps the versification names references qualify verse numbers
+// with, lowercased, to schemes.
+var qualifiers = map[string]string{
+	"lxx": versification.Orthodox, "mt": versification.Leningrad,
+	"vulg": versification.Catholic, "vg": versification.Catholic,
+	"nrsv": versification.NRSV, "kjv": versification.KJVA, "kjva": versification.KJVA,
+}
+
+// labels is the qualifier Format writes after passages of each scheme that
+// kjva numbers differently.
+var labels = map[string]string{
+	versification.Orthodox: "LXX", versification.Leningrad: "MT",
+	versification.Catholic: "Vulg", versification.NRSV: "NRSV",
+}
+
+var (
+	// bookPattern matches a book name at the start of a reference: letters,
+	// perhaps after a number as in "1 Cor" or "5. Mose", up to the chapter.
+	// Joiners belong to names such as the Persian "غزل غزل‌ها".
+	bookPattern = regexp.MustCompile(`^(?:[1-5]\s*\.?\s*)?\pL[\pL\pM'’.\s\x{200C}\x{200D}-]*`)
+	// qualifierPattern matches a versification named after a reference.
+	qualifierPattern = regexp.MustCompile(`^\(\s*([\pL\d]+)\s*\)`)
+)
+
+// itemPattern returns the pattern of the numbers of one reference, in a
+// language whose chapters and verses are separated by verse: a chapter or
+// verse, optionally with a verse after the separator, optionally followed by
+// a dash and another such pair. Verse parts, as in "16a", are read as the
+// whole verse. Languages that separate them with a colon also accept a
+// period, as in "Jn 3.16".
+func itemPattern(verse string) *regexp.Regexp {
+	class := `[:.]`
+	if verse != ":" {
+		class = `[` + regexp.QuoteMeta(verse) + `:]`
+	}
+	point := `(\d+)[a-z]?(?:\s*` + class + `\s*(\d+)[a-z]?)?`
+	return regexp.MustCompile(`^\s*` + point + `(?:\s*-\s*` + point + `)?`)
+}
+
+// zeros are the zero digits of the scripts the i18n languages write
+// numbers in besides ASCII: Arabic, Persian, Devanagari, Bengali, Gurmukhi,
+// Tamil, Telugu and Thai.
+var zeros = []rune{'٠', '۰', '०', '০', '੦', '௦', '౦', '๐'}
+
+// normalizeText returns s with every digit in ASCII and every dash a hyphen.
+func normalizeText(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '–', '—', '‒', '−', '‐', '‑':
+			return '-'
+		}
+		for _, z := range zeros {
+			if r >= z && r <= z+9 {
+				return '0' + r - z
+			}
+		}
+		return r
+	}, s)
+}
+
+// item is one reference as written: a book, if named, and up to four
+// numbers, -1 where absent: chapter, verse, then after a dash chapter and
+// verse again. Which numbers are chapters depends on the context.
+type item struct {
+	book string
+	n    [4]int
+}
+
+// Parse reads a list of references, separated by semicolons or by the
+// language's list separator, into passages in the order written. A
+// reference without a book continues the book before it, and a number after
+// a verse in the same reference is another verse: "Jn 3:16, 18; 4" is John
+// 3:16, John 3:18 and John 4.
+func (l *Language) Parse(s string) ([]Passage, error) {
+	var passages []Passage
+	book := ""
+	for _, part := range strings.Split(normalizeText(s), ";") {
+		items, qualifier, err := l.scan(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference %q: %w", s, err)
+		}
+		var ctx context
+		for _, it := range items {
+			if it.book != "" {
+				book, ctx = it.book, context{}
+			}
+			if book == "" {
+				return nil, fmt.Errorf("invalid reference %q: no book", s)
+			}
+			scheme, err := schemeFor(book, qualifier)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reference %q: %w", s, err)
+			}
+			p, err := interpret(scheme, book, it.n, &ctx)
+			if err != nil {
+				return nil, fmt.Errorf("invalid reference %q: %w", s, err)
+			}
+			passages = append(passages, p)
+		}
+	}
+	return passages, nil
+}
+
+// scan splits one semicolon-separated part of a reference list into items
+// and the qualifier, if any, after the last.
+func (l *Language) scan(part string) ([]item, string, error) {
+	var items []item
+	rest := part
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		it := item{n: [4]int{-1, -1, -1, -1}}
+		if name := bookPattern.FindString(rest); name != "" {
+			id, err := l.Book(name)
+			if err != nil {
+				return nil, "", err
+			}
+			it.book = id
+			rest = rest[len(name):]
+		}
+		if m := l.item.FindStringSubmatch(rest); m != nil {
+			for i, num := range m[1:] {
+				if num != "" {
+					it.n[i], _ = strconv.Atoi(num)
+				}
+			}
+			rest = rest[len(m[0]):]
+		} else if it.book == "" {
+			if strings.TrimSpace(rest) == "" {
+				return nil, "", fmt.Errorf("empty reference")
+			}
+			return nil, "", fmt.Errorf("expected a book or chapter at %q", strings.TrimSpace(rest))
+		}
+		items = append(items, it)
+
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if after, ok := strings.CutPrefix(rest, l.list); ok {
+			rest = after
+			continue
+		}
+		qualifier := ""
+		if m := qualifierPattern.FindStringSubmatch(rest); m != nil {
+			qualifier = strings.ToLower(m[1])
+			rest = strings.TrimSpace(rest[len(m[0]):])
+		}
+		if rest != "" {
+			return nil, "", fmt.Errorf("unexpected %q", rest)
+		}
+		return items, qualifier, nil
+	}
+}
+
+// schemeFor returns the versification a reference to book follows.
+func schemeFor(book, qualifier string) (*versification.Scheme, error) {
+	name := versification.KJVA
+	if qualifier != "" {
+		var ok bool
+		if name, ok = qualifiers[qualifier]; !ok {
+			return nil, fmt.Errorf("unknown versification %q", qualifier)
+		}
+	}
+	s, _ := versification.Lookup(name)
+	if qualifier == "" && !s.Has(book) {
+		s, _ = versification.Lookup(versification.Orthodox)
+	}
+	if !s.Has(book) {
+		return nil, fmt.Errorf("%s has no %s", s.Name, book)
+	}
+	if s.Chapters(book) == 0 {
+		return nil, fmt.Errorf("%s has no verse tables for %s", s.Name, book)
+	}
+	return s, nil
+}
+
+// context is what earlier items of a reference tell about the next: the
+// chapter it continues, and whether its numbers are verses of it.
+type context struct {
+	chapter int
+	verses  bool
+}
+
+// interpret turns the numbers n of one item in book into a passage of s,
+// checking every verse is one of s.
+func interpret(s *versification.Scheme, book string, n [4]int, ctx *context) (Passage, error) {
+	ref := func(chapter, verse int) versification.Ref {
+		return versification.Ref{Book: book, Chapter: chapter, Verse: verse}
+	}
+	first := func(chapter int) versification.Ref {
+		v, _ := s.Verses(book, chapter)
+		return ref(chapter, v)
+	}
+	last := func(chapter int) versification.Ref {
+		_, v := s.Verses(book, chapter)
+		return ref(chapter, v)
+	}
+	// A number alone is a verse after a verse, and in a book of one chapter
+	chapter, verses := ctx.chapter, ctx.verses
+	if !verses && s.Chapters(book) == 1 {
+		chapter, verses = 1, true
+	}
+
+	a, b, c, d := n[0], n[1], n[2], n[3]
+	var start, end versification.Ref
+	switch {
+	case a < 0:
+		start, end = first(firstChapter(s, book)), last(s.Chapters(book))
+	case b < 0 && c < 0 && verses:
+		start, end = ref(chapter, a), ref(chapter, a)
+	case b < 0 && c < 0:
+		start, end = first(a), last(a)
+	case c < 0:
+		start, end = ref(a, b), ref(a, b)
+	case b < 0 && d < 0 && verses:
+		start, end = ref(chapter, a), ref(chapter, c)
+	case b < 0 && d < 0:
+		start, end = first(a), last(c)
+	case b < 0 && verses:
+		start, end = ref(chapter, a), ref(c, d)
+	case b < 0:
+		start, end = first(a), ref(c, d)
+	case d < 0:
+		start, end = ref(a, b), ref(a, c)
+	default:
+		start, end = ref(a, b), ref(c, d)
+	}
+
+	for _, r := range []versification.Ref{start, end} {
+		if !s.Contains(r) {
+			if _, v := s.Verses(book, r.Chapter); v == 0 {
+				return Passage{}, fmt.Errorf("%s has no chapter %s.%d%s", s.Name, book, r.Chapter, numberedIn(s, r))
+			}
+			return Passage{}, fmt.Errorf("%s has no verse %s%s", s.Name, r, numberedIn(s, r))
+		}
+	}
+	if start.Chapter > end.Chapter || start.Chapter == end.Chapter && start.Verse > end.Verse {
+		return Passage{}, fmt.Errorf("%s comes after %s", start, end)
+	}
+
+	p := Passage{Start: start, End: end, Scheme: s.Name}
+	*ctx = context{chapter: end.Chapter, verses: !p.wholeChapters(s)}
+	return p, nil
+}
+
+// hints are the versifications numberedIn suggests, with the qualifier that
+// selects each, in the order it tries them.
+var hints = []struct{ scheme, qualifier string }{
+	{versification.KJVA, "KJV"}, {versification.Leningrad, "MT"},
+	{versification.Orthodox, "LXX"}, {versification.Catholic, "Vulg"},
+	{versification.NRSV, "NRSV"},
+}
+
+// numberedIn names a versification other than s that has r, as the Hebrew
+// numbering has Malachi 3:19, for the error about a verse s lacks.
+func numberedIn(s *versification.Scheme, r versification.Ref) string {
+	for _, h := range hints {
+		if h.scheme == s.Name {
+			continue
+		}
+		if other, ok := versification.Lookup(h.scheme); ok && other.Contains(r) {
+			return fmt.Sprintf("; %s numbers it, write (%s) after the reference", h.scheme, h.qualifier)
+		}
+	}
+	return ""
+}
+
+// firstChapter returns the book's first chapter in s, which for the
+// Additions to Esther is 10.
+func firstChapter(s *versification.Scheme, book string) int {
+	for ch := 1; ch <= s.Chapters(book); ch++ {
+		if _, last := s.Verses(book, ch); last > 0 {
+			return ch
+		}
+	}
+	return 1
+}
+
+// scheme returns the passage's versification.
+func (p Passage) scheme() *versification.Scheme {
+	s, ok := versification.Lookup(p.Scheme)
+	if !ok {
+		s, _ = versification.Lookup(versification.KJVA)
+	}
+	return s
+}
+
+// wholeChapters reports whether the passage is one or more whole chapters.
+func (p Passage) wholeChapters(s *versification.Scheme) bool {
+	first, _ := s.Verses(p.Start.Book, p.Start.Chapter)
+	_, last := s.Verses(p.End.Book, p.End.Chapter)
+	return p.Start.Verse == first && p.End.Verse == last
+}
+
+// wholeBook reports whether the passage is a whole book.
+func (p Passage) wholeBook(s *versification.Scheme) bool {
+	return p.Start.Chapter == firstChapter(s, p.Start.Book) && p.End.Chapter == s.Chapters(p.End.Book) && p.wholeChapters(s)
+}
+
+// OSIS returns the passage as an OSIS reference: "Ruth" for a whole book,
+// "1Cor.13" or "Gen.1-Gen.3" for whole chapters, "John.3.16" for a verse and
+// "Gen.1.31-Gen.2.3" for a range.
+func (p Passage) OSIS() string {
+	s := p.scheme()
+	chapter := func(r versification.Ref) string { return r.Book + "." + strconv.Itoa(r.Chapter) }
+	switch {
+	case p.wholeBook(s):
+		return p.Start.Book
+	case p.wholeChapters(s) && p.Start.Chapter == p.End.Chapter:
+		return chapter(p.Start)
+	case p.wholeChapters(s):
+		return chapter(p.Start) + "-" + chapter(p.End)
+	case p.Start == p.End:
+		return p.Start.String()
+	default:
+		return p.Start.String() + "-" + p.End.String()
+	}
+}
+
+// OSIS returns passages as a list of OSIS references separated by spaces,
+// as an osisRef attribute holds them.
+func OSIS(passages []Passage) string {
+	refs := make([]string, len(passages))
+	for i, p := range passages {
+		refs[i] = p.OSIS()
+	}
+	return strings.Join(refs, " ")
+}
+
+// In returns the passage in another versification: from where the text of
+// its first verse the scheme has to where the text of its last ends. It
+// reports false when the scheme has none of its text, or the text runs
+// into another book, as Daniel 3 of the Septuagint runs into the Prayer of
+// Azariah in kjva.
+func (p Passage) In(scheme string) (Passage, bool) {
+	to, ok := versification.Lookup(scheme)
+	if !ok {
+		return Passage{}, false
+	}
+	from := p.scheme()
+	verses := p.verses(from)
+	var start, end []versification.Ref
+	for i := 0; i < len(verses) && len(start) == 0; i++ {
+		start = versification.Map(from, to, verses[i])
+	}
+	for i := len(verses) - 1; i >= 0 && len(end) == 0; i-- {
+		end = versification.Map(from, to, verses[i])
+	}
+	if len(start) == 0 || len(end) == 0 {
+		return Passage{}, false
+	}
+	q := Passage{Start: start[0], End: end[len(end)-1], Scheme: to.Name}
+	if q.Start.Book != q.End.Book || q.Start.Chapter > q.End.Chapter ||
+		q.Start.Chapter == q.End.Chapter && q.Start.Verse > q.End.Verse {
+		return Passage{}, false
+	}
+	return q, true
+}
+
+// verses returns every verse of the passage in s, in order.
+func (p Passage) verses(s *versification.Scheme) []versification.Ref {
+	var refs []versification.Ref
+	for ch := p.Start.Chapter; ch <= p.End.Chapter; ch++ {
+		first, last := s.Verses(p.Start.Book, ch)
+		if ch == p.Start.Chapter {
+			first = p.Start.Verse
+		}
+		if ch == p.End.Chapter {
+			last = p.End.Verse
+		}
+		for v := first; v <= last; v++ {
+			refs = append(refs, versification.Ref{Book: p.Start.Book, Chapter: ch, Verse: v})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,515 @@
+package reference
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/versification"
+)
+
+// i18nDir is the site's translations directory.
+var i18nDir = filepath.Join("..", "..", "i18n")
+
+// loadLanguages reads every language in i18n/ by code.
+func loadLanguages(t *testing.T) map[string]*Language {
+	t.Helper()
+	langs, err := LoadLanguages(i18nDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := map[string]*Language{}
+	for _, l := range langs {
+		m[l.Code] = l
+	}
+	return m
+}
+
+// TestLanguages verifies every language names every book of the protestant
+// canon, English as the package does, and that each name reads back as its
+// book.
+func TestLanguages(t *testing.T) {
+	langs, err := LoadLanguages(i18nDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(langs) != 42 || langs[0].Code != DefaultLanguage {
+		t.Fatalf("loaded %d languages starting with %s, want 42 starting with en", len(langs), langs[0].Code)
+	}
+	protestant, _ := versification.Lookup(versification.Protestant)
+	for _, l := range langs {
+		if len(l.Names) != len(protestant.Books) {
+			t.Errorf("%s names %d books, want %d", l.Code, len(l.Names), len(protestant.Books))
+		}
+		for _, id := range protestant.Books {
+			name, ok := l.Names[id]
+			if !ok {
+				t.Errorf("%s has no name for %s", l.Code, id)
+				continue
+			}
+			if l.Code == DefaultLanguage && name != englishNames[id] {
+				t.Errorf("en names %s %q, the package %q", id, name, englishNames[id])
+			}
+			if got, err := l.Book(name); err != nil || got != id {
+				t.Errorf("%s: Book(%q) = %s, %v, want %s", l.Code, name, got, err, id)
+			}
+		}
+		for key, ids := range l.keys {
+			if len(ids) > 1 {
+				t.Errorf("%s: %q names %v", l.Code, key, ids)
+			}
+		}
+	}
+}
+
+// TestBook verifies how names, abbreviations and prefixes resolve.
+func TestBook(t *testing.T) {
+	langs := loadLanguages(t)
+	tests := []struct {
+		lang, name, want string
+	}{
+		{"en", "Genesis", "Gen"},
+		{"en", "gen", "Gen"},
+		{"en", "Gn", "Gen"},
+		{"en", "Exod.", "Exod"},
+		{"en", "1 Cor", "1Cor"},
+		{"en", "1Cor", "1Cor"},
+		{"en", "1 Co.", "1Cor"},
+		{"en", "I Corinthians", "1Cor"},
+		{"en", "II Kings", "2Kgs"},
+		{"en", "First John", "1John"},
+		{"en", "3rd John", "3John"},
+		{"en", "Jn", "John"},
+		{"en", "Jhn", "John"},
+		{"en", "Ps", "Ps"},
+		{"en", "Psalm", "Ps"},
+		{"en", "Pss", "Ps"},
+		{"en", "Song of Songs", "Song"},
+		{"en", "Canticles", "Song"},
+		{"en", "Is", "Isa"},
+		{"en", "Phil", "Phil"},
+		{"en", "Philem", "Phlm"},
+		{"en", "Jud", "Jude"},
+		{"en", "Judg", "Judg"},
+		{"en", "Rev", "Rev"},
+		{"en", "Revelations", "Rev"},
+		{"en", "Apocalypse", "Rev"},
+		{"en", "Hab", "Hab"},
+		{"en", "Obad", "Obad"},
+		{"en", "Tobit", "Tob"},
+		{"en", "Ecclus", "Sir"},
+		{"en", "Wisdom", "Wis"},
+		{"en", "Macc", ""},
+		{"en", "1 Macc", "1Macc"},
+		{"en", "Bel and the Dragon", "Bel"},
+		{"en", "Letter of Jer", "EpJer"},
+		{"de", "1. Mose", "Gen"},
+		{"de", "1 Mose", "Gen"},
+		{"de", "1Mo", "Gen"},
+		{"de", "Joh", "John"},
+		{"de", "1 Joh", "1John"},
+		{"de", "Röm", "Rom"},
+		{"de", "Offenb", "Rev"},
+		{"de", "Offb", ""},
+		{"de", "Mt", "Matt"},
+		{"de", "Phil", "Phil"},
+		{"de", "Gen", "Gen"},
+		{"fr", "Genèse", "Gen"},
+		{"fr", "Jean", "John"},
+		{"fr", "1 Jean", "1John"},
+		{"fr", "Apoc", "Rev"},
+		{"es", "Juan", "John"},
+		{"es", "Hch", ""},
+		{"es", "Hech", "Acts"},
+		{"ru", "Бытие", "Gen"},
+		{"ru", "Иоанна", "John"},
+		{"ru", "3 Царств", "1Kgs"},
+		{"el", "Α' Κορινθίους", "1Cor"},
+		{"he", "שמואל א", "1Sam"},
+		{"zh", "约翰福音", "John"},
+		{"ja", "創世記", "Gen"},
+		{"ko", "요한복음", "John"},
+		{"ar", "يوحنا", "John"},
+		{"tr", "İbraniler", "Heb"},
+		{"vi", "Giăng", "John"},
+		{"vi", "giang", ""},
+		{"en", "Jo", ""},
+		{"en", "Foo", ""},
+		{"en", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := langs[tt.lang].Book(tt.name)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: Book(%q) = %s, want an error", tt.lang, tt.name, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: Book(%q) = %s, %v, want %s", tt.lang, tt.name, got, err, tt.want)
+		}
+	}
+}
+
+// TestParse verifies references parse to the expected OSIS references and
+// versifications.
+func TestParse(t *testing.T) {
+	en := loadLanguages(t)["en"]
+	tests := []struct {
+		ref, want string
+		scheme    string
+	}{
+		{"Gen 1:1", "Gen.1.1", "kjva"},
+		{"Gen 1:1-3", "Gen.1.1-Gen.1.3", "kjva"},
+		{"Gen 1:1–3", "Gen.1.1-Gen.1.3", "kjva"},
+		{"Gen 1:1—3", "Gen.1.1-Gen.1.3", "kjva"},
+		{"Gen 1:1 - 3", "Gen.1.1-Gen.1.3", "kjva"},
+		{"Gen 1.1", "Gen.1.1", "kjva"},
+		{"Genesis 1:1-3; Jn 3:16", "Gen.1.1-Gen.1.3 John.3.16", "kjva"},
+		{"1 Cor 13", "1Cor.13", "kjva"},
+		{"1 Corinthians 13:1-13", "1Cor.13", "kjva"},
+		{"Gen 1-3", "Gen.1-Gen.3", "kjva"},
+		{"Gen 1:31-2:3", "Gen.1.31-Gen.2.3", "kjva"},
+		{"John 3:16–4:2", "John.3.16-John.4.2", "kjva"},
+		{"Gen 1-2:3", "Gen.1.1-Gen.2.3", "kjva"},
+		{"John 3:16, 18", "John.3.16 John.3.18", "kjva"},
+		{"John 3:16,18-20", "John.3.16 John.3.18-John.3.20", "kjva"},
+		{"John 3:16, 4:1", "John.3.16 John.4.1", "kjva"},
+		{"John 3:16, 18-4:2", "John.3.16 John.3.18-John.4.2", "kjva"},
+		{"Ps 1, 2", "Ps.1 Ps.2", "kjva"},
+		{"Ps 1; 2", "Ps.1 Ps.2", "kjva"},
+		{"Gen 1:1; 2:4", "Gen.1.1 Gen.2.4", "kjva"},
+		{"Gen 1:1; 3", "Gen.1.1 Gen.3", "kjva"},
+		{"Gen 1:1, Exod 2:3", "Gen.1.1 Exod.2.3", "kjva"},
+		{"Gen 1:1; Exod 2:3, 5; Lev 4", "Gen.1.1 Exod.2.3 Exod.2.5 Lev.4", "kjva"},
+		{"Jn 3:16a", "John.3.16", "kjva"},
+		{"Ruth", "Ruth", "kjva"},
+		{"Ruth 1-4", "Ruth", "kjva"},
+		{"Jude 3", "Jude.1.3", "kjva"},
+		{"Jude 3-5", "Jude.1.3-Jude.1.5", "kjva"},
+		{"Jude 1:3", "Jude.1.3", "kjva"},
+		{"Jude 3, 5", "Jude.1.3 Jude.1.5", "kjva"},
+		{"Jude", "Jude", "kjva"},
+		{"Obad 21", "Obad.1.21", "kjva"},
+		{"Philem 1-25", "Phlm", "kjva"},
+		{"Ps 23:1–4 (LXX)", "Ps.23.1-Ps.23.4", "orthodox"},
+		{"Ps 151 (LXX)", "Ps.151", "orthodox"},
+		{"Ps 51:1 (MT)", "Ps.51.1", "leningrad"},
+		{"Mal 4:1", "Mal.4.1", "kjva"},
+		{"Mal 3:19-24 (MT)", "Mal.3.19-Mal.3.24", "leningrad"},
+		{"Joel 4 (mt)", "Joel.4", "leningrad"},
+		{"3 John 15 (NRSV)", "3John.1.15", "nrsv"},
+		{"Dan 3:24-90 (Vulg)", "Dan.3.24-Dan.3.90", "catholic"},
+		{"Rev 12:17 (KJV)", "Rev.12.17", "kjva"},
+		{"Rev 22:21", "Rev.22.21", "kjva"},
+		{"Tob 1:1", "Tob.1.1", "kjva"},
+		{"Sir 1", "Sir.1", "kjva"},
+		{"Bar 6:1", "Bar.6.1", "kjva"},
+		{"EpJer 6 (LXX)", "EpJer.1.6", "orthodox"},
+		{"Letter of Jeremiah 1-3", "EpJer.1.1-EpJer.1.3", "orthodox"},
+		{"AddEsth", "AddEsth", "kjva"},
+		{"AddEsth 10:4", "AddEsth.10.4", "kjva"},
+		{"II Kings 2:11", "2Kgs.2.11", "kjva"},
+		{"  Gen 1:1  ;  Gen 1:2  ", "Gen.1.1 Gen.1.2", "kjva"},
+		{"Gen 1:1-1", "Gen.1.1", "kjva"},
+	}
+	for _, tt := range tests {
+		ps, err := en.Parse(tt.ref)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.ref, err)
+			continue
+		}
+		if got := OSIS(ps); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.ref, got, tt.want)
+		}
+		for _, p := range ps {
+			if p.Scheme != tt.scheme {
+				t.Errorf("Parse(%q) reads %s in %s, want %s", tt.ref, p.OSIS(), p.Scheme, tt.scheme)
+			}
+		}
+	}
+}
+
+// TestParseErrors verifies references that name no verse are errors, each
+// naming the problem.
+func TestParseErrors(t *testing.T) {
+	en := loadLanguages(t)["en"]
+	tests := []struct {
+		ref, want string
+	}{
+		{"", "empty reference"},
+		{"Gen 1:1;", "empty reference"},
+		{"3:16", "no book"},
+		{"Foo 1:1", `unknown book "Foo "`},
+		{"Jo 1:1", `ambiguous book "Jo "`},
+		{"Gen 51", "kjva has no chapter Gen.51"},
+		{"Gen 0", "kjva has no chapter Gen.0"},
+		{"Gen 1:32", "kjva has no verse Gen.1.32"},
+		{"Gen 1:3-1", "Gen.1.3 comes after Gen.1.1"},
+		{"Gen 3-1", "Gen.3.1 comes after Gen.1.31"},
+		{"Ps 151", "kjva has no chapter Ps.151"},
+		{"Rev 12:18", "kjva has no verse Rev.12.18"},
+		{"Mal 3:19 (Vulg)", "catholic has no verse Mal.3.19; leningrad numbers it, write (MT)"},
+		{"Mal 4:1 (MT)", "leningrad has no chapter Mal.4; kjva numbers it, write (KJV)"},
+		{"Tob 1:1 (MT)", "leningrad has no Tob"},
+		{"Odes 1:1", "orthodox has no verse tables for Odes"},
+		{"Gen 1:1 (Foo)", `unknown versification "foo"`},
+		{"Gen 1:1 and 2", `unexpected "and 2"`},
+		{"Gen 1:1 (LXX) 2", `unexpected "2"`},
+		{"Gen 1:1, ,", "expected a book or chapter"},
+	}
+	for _, tt := range tests {
+		ps, err := en.Parse(tt.ref)
+		if err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", tt.ref, OSIS(ps))
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q): %v, want %q", tt.ref, err, tt.want)
+		}
+	}
+}
+
+// TestParseLocalized verifies references in other languages, with their
+// book names, separators and digits.
+func TestParseLocalized(t *testing.T) {
+	langs := loadLanguages(t)
+	tests := []struct {
+		lang, ref, want string
+	}{
+		{"de", "1. Mose 1,1-3", "Gen.1.1-Gen.1.3"},
+		{"de", "Joh 3,16.18", "John.3.16 John.3.18"},
+		{"de", "Joh 3,16; Röm 8", "John.3.16 Rom.8"},
+		{"de", "1 Kor 13,1–13", "1Cor.13"},
+		{"de", "Offenb 21,1-22,5", "Rev.21.1-Rev.22.5"},
+		{"de", "Ps 23,1-4 (LXX)", "Ps.23.1-Ps.23.4"},
+		{"de", "Johannes 3:16", "John.3.16"},
+		{"pl", "Jana 3,16", "John.3.16"},
+		{"pl", "1 Jana 4,8.16", "1John.4.8 1John.4.16"},
+		{"fr", "Jean 3:16", "John.3.16"},
+		{"fr", "Genèse 1:1–2:3", "Gen.1.1-Gen.2.3"},
+		{"fr", "1 Corinthiens 13", "1Cor.13"},
+		{"es", "Juan 3:16, 18", "John.3.16 John.3.18"},
+		{"es", "Génesis 1; Éxodo 20:1-17", "Gen.1 Exod.20.1-Exod.20.17"},
+		{"it", "1 Re 17", "1Kgs.17"},
+		{"pt", "João 1:1", "John.1.1"},
+		{"nl", "Handelingen 2:1-4", "Acts.2.1-Acts.2.4"},
+		{"sv", "1 Mosebok 1:1", "Gen.1.1"},
+		{"ru", "Иоанна 3:16", "John.3.16"},
+		{"ru", "3 Царств 17:1", "1Kgs.17.1"},
+		{"uk", "Івана 3:16", "John.3.16"},
+		{"el", "Α' Κορινθίους 13", "1Cor.13"},
+		{"la", "Ioannes 1:1-5", "John.1.1-John.1.5"},
+		{"he", "שמואל א 3:1", "1Sam.3.1"},
+		{"ar", "يوحنا ٣:١٦", "John.3.16"},
+		{"fa", "یوحنا ۳:۱۶", "John.3.16"},
+		{"hi", "यूहन्ना ३:१६", "John.3.16"},
+		{"bn", "যোহন ৩:১৬", "John.3.16"},
+		{"th", "ยอห์น ๓:๑๖", "John.3.16"},
+		{"zh", "约翰福音3:16", "John.3.16"},
+		{"zh", "创世记 1:1-3; 约翰福音 3:16", "Gen.1.1-Gen.1.3 John.3.16"},
+		{"ja", "ヨハネによる福音書3:16", "John.3.16"},
+		{"ko", "요한복음 3:16", "John.3.16"},
+		{"ko", "시편 23", "Ps.23"},
+		{"sw", "Yohana 3:16", "John.3.16"},
+		{"id", "Hakim-hakim 6:11", "Judg.6.11"},
+		{"tr", "Yuhanna 3:16", "John.3.16"},
+		{"vi", "Giăng 3:16", "John.3.16"},
+		{"am", "ዮሐንስ 3:16", "John.3.16"},
+		{"fr", "Jn 3:16", "John.3.16"},
+	}
+	for _, tt := range tests {
+		ps, err := langs[tt.lang].Parse(tt.ref)
+		if err != nil {
+			t.Errorf("%s: Parse(%q): %v", tt.lang, tt.ref, err)
+			continue
+		}
+		if got := OSIS(ps); got != tt.want {
+			t.Errorf("%s: Parse(%q) = %s, want %s", tt.lang, tt.ref, got, tt.want)
+		}
+	}
+}
+
+// TestFormat verifies passages are written back in each language, and read
+// back to the same passages.
+func TestFormat(t *testing.T) {
+	langs := loadLanguages(t)
+	tests := []struct {
+		lang, ref, want string
+	}{
+		{"en", "Gen 1:1-3; Jn 3:16", "Genesis 1:1–3; John 3:16"},
+		{"en", "1 Cor 13", "1 Corinthians 13"},
+		{"en", "Gen 1-3", "Genesis 1–3"},
+		{"en", "Gen 1:31-2:3", "Genesis 1:31–2:3"},
+		{"en", "Gen 1-2:3", "Genesis 1:1–2:3"},
+		{"en", "John 3:16,18-20", "John 3:16, 18–20"},
+		{"en", "John 3:16; 4:1", "John 3:16; 4:1"},
+		{"en", "John 3:16, 4:1", "John 3:16; 4:1"},
+		{"en", "John 3; 3:16", "John 3; 3:16"},
+		{"en", "Gen 1:31-2:3, 5", "Genesis 1:31–2:3, 5"},
+		{"en", "Ps 1, 2", "Psalms 1; 2"},
+		{"en", "Ruth", "Ruth"},
+		{"en", "Jude 3, 5", "Jude 3, 5"},
+		{"en", "Jude; Jude 4", "Jude; 4"},
+		{"en", "Ps 23:1–4 (LXX)", "Psalms 23:1–4 (LXX)"},
+		{"en", "Ps 23:1, 3 (LXX); 24", "Psalms 23:1, 3 (LXX); Psalms 24"},
+		{"en", "Ps 23; 24 (LXX)", "Psalms 23; Psalms 24 (LXX)"},
+		{"en", "Ps 23:1 (LXX); 24:2 (LXX)", "Psalms 23:1 (LXX); 24:2 (LXX)"},
+		{"en", "Tob 1:1; EpJer 2", "Tobit 1:1; Letter of Jeremiah 2 (LXX)"},
+		{"de", "Gen 1,1-3; Jn 3,16.18", "1. Mose 1,1–3; Johannes 3,16.18"},
+		{"de", "Röm 8; Tob 1,1", "Römer 8; Tobit 1,1"},
+		{"fr", "Jn 3:16, 18", "Jean 3:16, 18"},
+		{"he", "1 Sam 3:1", "שמואל א 3:1"},
+		{"zh", "John 3:16", "约翰福音 3:16"},
+		{"ja", "Rev 22", "ヨハネの黙示録 22"},
+	}
+	for _, tt := range tests {
+		l := langs[tt.lang]
+		ps, err := l.Parse(tt.ref)
+		if err != nil {
+			t.Errorf("%s: Parse(%q): %v", tt.lang, tt.ref, err)
+			continue
+		}
+		got := l.Format(ps)
+		if got != tt.want {
+			t.Errorf("%s: Format(%q) = %q, want %q", tt.lang, tt.ref, got, tt.want)
+		}
+		again, err := l.Parse(got)
+		if err != nil {
+			t.Errorf("%s: Parse(%q): %v", tt.lang, got, err)
+		} else if !reflect.DeepEqual(again, ps) {
+			t.Errorf("%s: %q reads back as %s, want %s", tt.lang, got, OSIS(again), OSIS(ps))
+		}
+	}
+	if got := langs["en"].Format(nil); got != "" {
+		t.Errorf("Format(nil) = %q", got)
+	}
+}
+
+// TestFormatRoundTrip verifies every language reads back what it writes of
+// the last verse of every book of the kjva and orthodox canons.
+func TestFormatRoundTrip(t *testing.T) {
+	langs := loadLanguages(t)
+	var refs []string
+	for _, q := range []struct{ scheme, label string }{
+		{versification.KJVA, "KJV"},
+		{versification.Orthodox, "LXX"},
+	} {
+		s, _ := versification.Lookup(q.scheme)
+		for _, book := range s.Books {
+			last := s.Chapters(book)
+			if last == 0 {
+				continue
+			}
+			_, v := s.Verses(book, last)
+			refs = append(refs, fmt.Sprintf("%s %d:%d (%s)", book, last, v, q.label))
+		}
+	}
+	passages, err := langs["en"].Parse(strings.Join(refs, "; "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for code, l := range langs {
+		text := l.Format(passages)
+		again, err := l.Parse(text)
+		if err != nil {
+			t.Errorf("%s: %v", code, err)
+			continue
+		}
+		if !reflect.DeepEqual(again, passages) {
+			t.Errorf("%s: %q reads back as %s", code, text, OSIS(again))
+		}
+	}
+}
+
+// TestIn verifies passages move between versifications.
+func TestIn(t *testing.T) {
+	en := loadLanguages(t)["en"]
+	tests := []struct {
+		ref, scheme, want string
+	}{
+		{"Ps 23:1–4 (LXX)", versification.KJVA, "Ps.24.1-Ps.24.4"},
+		{"Ps 23 (LXX)", versification.Protestant, "Ps.24"},
+		{"Ps 50 (LXX)", versification.KJVA, "Ps.51"},
+		{"Ps 51 (MT)", versification.KJVA, "Ps.51"},
+		{"Ps 51", versification.Leningrad, "Ps.51.3-Ps.51.21"},
+		{"Ps 51:1", versification.Leningrad, "Ps.51.3"},
+		{"Mal 4", versification.Leningrad, "Mal.3.19-Mal.3.24"},
+		{"Joel 3:1 (MT)", versification.KJVA, "Joel.2.28"},
+		{"John 3:16", versification.Orthodox, "John.3.16"},
+		{"Gen 1:1-3", versification.KJVA, "Gen.1.1-Gen.1.3"},
+		{"Tob 1", versification.Protestant, ""},
+		{"Gen 1", "nonesuch", ""},
+	}
+	for _, tt := range tests {
+		ps, err := en.Parse(tt.ref)
+		if err != nil || len(ps) != 1 {
+			t.Errorf("Parse(%q) = %v, %v", tt.ref, ps, err)
+			continue
+		}
+		got, ok := ps[0].In(tt.scheme)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s in %s = %s, want none", tt.ref, tt.scheme, got.OSIS())
+			}
+			continue
+		}
+		if !ok || got.OSIS() != tt.want || got.Scheme != tt.scheme {
+			t.Errorf("%s in %s = %s in %s, %v, want %s", tt.ref, tt.scheme, got.OSIS(), got.Scheme, ok, tt.want)
+		}
+	}
+}
+
+// TestURL verifies passages link to their chapter pages, under the
+// language's prefix and in the versification of the Bible linked to.
+func TestURL(t *testing.T) {
+	langs := loadLanguages(t)
+	tests := []struct {
+		lang, ref, basePath, scheme, want string
+	}{
+		{"en", "John 3:16", "/bible", "kjva", "/bible/kjva/john/3/"},
+		{"en", "1 Cor 13", "/bible", "kjva", "/bible/kjva/1cor/13/"},
+		{"en", "Gen 1:31-2:3", "/bible/", "kjva", "/bible/kjva/gen/1/"},
+		{"en", "Jude", "/bible", "kjva", "/bible/kjva/jude/1/"},
+		{"de", "Joh 3,16", "/bible", "kjva", "/de/bible/kjva/john/3/"},
+		{"gez", "John 3:16", "/bibles", "kjva", "/gez/bibles/kjva/john/3/"},
+		{"en", "Ps 23", "/bible", "", "/bible/kjva/ps/23/"},
+		{"en", "Ps 23 (LXX)", "/bible", "kjva", "/bible/kjva/ps/24/"},
+		{"en", "Ps 23 (LXX)", "/bible", "orthodox", "/bible/kjva/ps/23/"},
+		{"en", "Ps 23", "/bible", "orthodox", "/bible/kjva/ps/22/"},
+		{"en", "Ps 51 (MT)", "/bible", "kjva", "/bible/kjva/ps/51/"},
+		{"en", "Ps 51:1", "/bible", "leningrad", "/bible/kjva/ps/51/"},
+		{"en", "Mal 4:1", "/bible", "leningrad", "/bible/kjva/mal/3/"},
+		{"en", "Mal 4:1", "/bible", "catholic", "/bible/kjva/mal/4/"},
+		{"en", "Mal 3:19 (MT)", "/bible", "kjva", "/bible/kjva/mal/4/"},
+		{"en", "Mal 3:19 (MT)", "/bible", "catholic", "/bible/kjva/mal/4/"},
+	}
+	for _, tt := range tests {
+		l := langs[tt.lang]
+		ps, err := l.Parse(tt.ref)
+		if err != nil {
+			t.Errorf("%s: Parse(%q): %v", tt.lang, tt.ref, err)
+			continue
+		}
+		got, err := l.URL(tt.basePath, "kjva", tt.scheme, ps[0])
+		if err != nil || got != tt.want {
+			t.Errorf("%s: URL(%q) in %s = %s, %v, want %s", tt.lang, tt.ref, tt.scheme, got, err, tt.want)
+		}
+	}
+
+	en := langs["en"]
+	ps, err := en.Parse("Tob 1:1")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for scheme, want := range map[string]string{
+		"protestant": "protestant has no text of Tob.1.1",
+		"foo":        `unknown versification "foo"`,
+	} {
+		if _, err := en.URL("/bible", "kjva", scheme, ps[0]); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("URL(Tob 1:1) in %s: %v, want %q", scheme, err, want)
+		}
+	}
+}
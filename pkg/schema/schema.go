@@ -0,0 +1,343 @@
+// Package schema validates JSON documents against the draft-07 JSON Schemas
+// in static/schemas.
+//
+// It implements the keywords those schemas use: type, required, properties,
+// additionalProperties, items, enum, pattern, minimum, maximum and
+// minLength. Compile rejects any other validation keyword, so a schema
+// change the validator cannot enforce fails loudly instead of passing.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// annotations are the keywords that describe a schema without constraining
+// documents.
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true,
+	"title": true, "description": true, "examples": true, "default": true,
+}
+
+// Schema is a compiled schema.
+type Schema struct {
+	types      []string
+	required   []string
+	properties map[string]*Schema
+	// additional is nil when additionalProperties is absent or true.
+	additional *Schema
+	noExtra    bool
+	items      *Schema
+	enum       []any
+	pattern    *regexp.Regexp
+	minimum    *float64
+	maximum    *float64
+	minLength  *int
+}
+
+// Error is a place where a document breaks its schema.
+type Error struct {
+	// Pointer is the JSON pointer to the offending value, "" for the root.
+	Pointer string
+	Message string
+}
+
+func (e Error) Error() string {
+	return "#" + e.Pointer + ": " + e.Message
+}
+
+// Load reads and compiles the schema at path.
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	s, err := Compile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Compile parses a schema document.
+func Compile(data []byte) (*Schema, error) {
+	raw, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return compile(raw, "")
+}
+
+// compile builds a schema from its decoded form; at is its pointer within
+// the schema document, for errors.
+func compile(raw any, at string) (*Schema, error) {
+	obj, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("#%s: schema must be an object", at)
+	}
+	s := &Schema{}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := obj[key]
+		here := at + "/" + Escape(key)
+		bad := func() error { return fmt.Errorf("#%s: invalid %s", here, key) }
+		switch {
+		case annotations[key]:
+		case key == "type":
+			switch t := v.(type) {
+			case string:
+				s.types = []string{t}
+			case []any:
+				for _, e := range t {
+					name, ok := e.(string)
+					if !ok {
+						return nil, bad()
+					}
+					s.types = append(s.types, name)
+				}
+			default:
+				return nil, bad()
+			}
+		case key == "required":
+			list, ok := v.([]any)
+			if !ok {
+				return nil, bad()
+			}
+			for _, e := range list {
+				name, ok := e.(string)
+				if !ok {
+					return nil, bad()
+				}
+				s.required = append(s.required, name)
+			}
+		case key == "properties":
+			props, ok := v.(map[string]any)
+			if !ok {
+				return nil, bad()
+			}
+			s.properties = map[string]*Schema{}
+			for name, sub := range props {
+				compiled, err := compile(sub, here+"/"+Escape(name))
+				if err != nil {
+					return nil, err
+				}
+				s.properties[name] = compiled
+			}
+		case key == "additionalProperties":
+			switch a := v.(type) {
+			case bool:
+				s.noExtra = !a
+			default:
+				compiled, err := compile(a, here)
+				if err != nil {
+					return nil, err
+				}
+				s.additional = compiled
+			}
+		case key == "items":
+			compiled, err := compile(v, here)
+			if err != nil {
+				return nil, err
+			}
+			s.items = compiled
+		case key == "enum":
+			list, ok := v.([]any)
+			if !ok {
+				return nil, bad()
+			}
+			s.enum = list
+		case key == "pattern":
+			p, ok := v.(string)
+			if !ok {
+				return nil, bad()
+			}
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("#%s: %w", here, err)
+			}
+			s.pattern = re
+		case key == "minimum" || key == "maximum":
+			n, ok := number(v)
+			if !ok {
+				return nil, bad()
+			}
+			if key == "minimum" {
+				s.minimum = &n
+			} else {
+				s.maximum = &n
+			}
+		case key == "minLength":
+			n, ok := number(v)
+			if !ok || n != float64(int(n)) {
+				return nil, bad()
+			}
+			length := int(n)
+			s.minLength = &length
+		default:
+			return nil, fmt.Errorf("#%s: unsupported keyword %q", here, key)
+		}
+	}
+	return s, nil
+}
+
+// Validate checks a JSON document against the schema and returns every
+// error in document order.
+func (s *Schema) Validate(data []byte) ([]Error, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	var errs []Error
+	s.validate(doc, "", &errs)
+	return errs, nil
+}
+
+// validate checks one value.
+func (s *Schema) validate(v any, at string, errs *[]Error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, Error{Pointer: at, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.types) > 0 && !slices.ContainsFunc(s.types, func(t string) bool { return hasType(v, t) }) {
+		fail("expected %s, got %s", strings.Join(s.types, " or "), typeName(v))
+		return
+	}
+
+	if len(s.enum) > 0 && !slices.ContainsFunc(s.enum, func(e any) bool { return equal(e, v) }) {
+		fail("%s is not one of %s", format(v), format(s.enum))
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			here := at + "/" + Escape(k)
+			if sub, ok := s.properties[k]; ok {
+				sub.validate(v[k], here, errs)
+			} else if s.additional != nil {
+				s.additional.validate(v[k], here, errs)
+			} else if s.noExtra {
+				*errs = append(*errs, Error{Pointer: here, Message: "property is not allowed"})
+			}
+		}
+	case []any:
+		if s.items != nil {
+			for i, e := range v {
+				s.items.validate(e, at+"/"+strconv.Itoa(i), errs)
+			}
+		}
+	case string:
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			fail("%q does not match %s", v, s.pattern)
+		}
+		if s.minLength != nil && len([]rune(v)) < *s.minLength {
+			fail("%q is shorter than %d characters", v, *s.minLength)
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if s.minimum != nil && n < *s.minimum {
+			fail("%s is below the minimum %v", v, *s.minimum)
+		}
+		if s.maximum != nil && n > *s.maximum {
+			fail("%s is above the maximum %v", v, *s.maximum)
+		}
+	}
+}
+
+// decode parses JSON keeping numbers exact.
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// hasType reports whether v is of the named JSON Schema type.
+func hasType(v any, name string) bool {
+	switch v := v.(type) {
+	case map[string]any:
+		return name == "object"
+	case []any:
+		return name == "array"
+	case string:
+		return name == "string"
+	case bool:
+		return name == "boolean"
+	case nil:
+		return name == "null"
+	case json.Number:
+		if name == "number" {
+			return true
+		}
+		if name == "integer" {
+			f, err := v.Float64()
+			return err == nil && f == float64(int64(f))
+		}
+	}
+	return false
+}
+
+// typeName names the JSON type of v.
+func typeName(v any) string {
+	for _, name := range []string{"object", "array", "string", "boolean", "null", "integer", "number"} {
+		if hasType(v, name) {
+			return name
+		}
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// number reads a numeric schema keyword.
+func number(v any) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+// equal compares decoded JSON values.
+func equal(a, b any) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(x, y)
+}
+
+// format renders a decoded value as compact JSON for messages.
+func format(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Escape encodes an object key as a JSON pointer reference token.
+func Escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
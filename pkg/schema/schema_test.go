@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testSchema uses every supported keyword.
+const testSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "Test",
+  "type": "object",
+  "required": ["id", "items"],
+  "additionalProperties": false,
+  "properties": {
+    "id": {"type": "string", "pattern": "^[a-z]+$", "minLength": 2},
+    "kind": {"type": "string", "enum": ["a", "b"]},
+    "count": {"type": "integer", "minimum": 1, "maximum": 10},
+    "note": {"type": ["string", "null"]},
+    "items": {
+      "type": "array",
+      "items": {"type": "object", "required": ["n"], "properties": {"n": {"type": "number"}}}
+    },
+    "a/b": {"type": "boolean"}
+  }
+}`
+
+// TestValidate verifies each keyword and the JSON pointer of each error.
+func TestValidate(t *testing.T) {
+	s, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{"valid", `{"id":"ab","kind":"a","count":3,"note":null,"items":[{"n":1.5}],"a/b":true}`, nil},
+		{"root type", `[]`, []string{"#: expected object, got array"}},
+		{"required", `{"id":"ab"}`, []string{`#: missing required property "items"`}},
+		{"additional", `{"id":"ab","items":[],"extra":1}`, []string{"#/extra: property is not allowed"}},
+		{"pattern", `{"id":"AB","items":[]}`, []string{`#/id: "AB" does not match ^[a-z]+$`}},
+		{"minLength", `{"id":"a","items":[]}`, []string{`#/id: "a" is shorter than 2 characters`}},
+		{"enum", `{"id":"ab","kind":"c","items":[]}`, []string{`#/kind: "c" is not one of ["a","b"]`}},
+		{"integer", `{"id":"ab","count":1.5,"items":[]}`, []string{"#/count: expected integer, got number"}},
+		{"minimum", `{"id":"ab","count":0,"items":[]}`, []string{"#/count: 0 is below the minimum 1"}},
+		{"maximum", `{"id":"ab","count":11,"items":[]}`, []string{"#/count: 11 is above the maximum 10"}},
+		{"type list", `{"id":"ab","note":1,"items":[]}`, []string{"#/note: expected string or null, got integer"}},
+		{"items", `{"id":"ab","items":[{"n":1},{},{"n":"x"}]}`, []string{
+			`#/items/1: missing required property "n"`,
+			"#/items/2/n: expected number, got string",
+		}},
+		{"escaped pointer", `{"id":"ab","items":[],"a/b":1}`, []string{"#/a~1b: expected boolean, got integer"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := s.Validate([]byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got\n  %s\nwant\n  %s", strings.Join(got, "\n  "), strings.Join(tt.want, "\n  "))
+			}
+		})
+	}
+
+	if _, err := s.Validate([]byte(`{`)); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
+
+// TestCompileRejects verifies keywords the validator cannot enforce fail
+// compilation.
+func TestCompileRejects(t *testing.T) {
+	for _, doc := range []string{
+		`{"type":"object","oneOf":[]}`,
+		`{"properties":{"a":{"format":"date-time"}}}`,
+		`{"pattern":"("}`,
+		`{"required":"id"}`,
+		`[]`,
+	} {
+		if _, err := Compile([]byte(doc)); err == nil {
+			t.Errorf("expected %s to fail compilation", doc)
+		}
+	}
+}
+
+// TestRepoSchemas verifies the schemas in static/schemas compile.
+func TestRepoSchemas(t *testing.T) {
+	for _, name := range []string{"bibles.schema.json", "bibles-auxiliary.schema.json"} {
+		if _, err := Load(filepath.Join("..", "..", "static", "schemas", name)); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package versification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Alignment is the verse-aligned mapping from one scheme to another, which
+// the compare and SSS views read to line up two Bibles verse by verse.
+type Alignment struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Verses maps each OSIS verse of From whose text To holds under another
+	// reference, or not at all, to the verses of To that hold it. Verses
+	// missing from the map keep their reference, and verses of books To
+	// does not have are missing unless another of To's books holds them.
+	Verses map[string][]string `json:"verses"`
+}
+
+// Align returns the alignment of scheme from with scheme to.
+func Align(from, to *Scheme) *Alignment {
+	a := &Alignment{From: from.Name, To: to.Name, Verses: map[string][]string{}}
+	for _, book := range from.Books {
+		for ch := 1; ch <= from.Chapters(book); ch++ {
+			first, last := from.Verses(book, ch)
+			for v := first; v <= last && last > 0; v++ {
+				r := Ref{Book: book, Chapter: ch, Verse: v}
+				refs := Map(from, to, r)
+				if len(refs) == 1 && refs[0] == r || len(refs) == 0 && !to.Has(book) {
+					continue
+				}
+				ids := make([]string, len(refs))
+				for i, t := range refs {
+					ids[i] = t.String()
+				}
+				a.Verses[r.String()] = ids
+			}
+		}
+	}
+	return a
+}
+
+// Alignments returns the alignment of every scheme with every other, in
+// Names order.
+func Alignments() []*Alignment {
+	var all []*Alignment
+	for _, from := range Names() {
+		for _, to := range Names() {
+			if from != to {
+				all = append(all, Align(schemes[from], schemes[to]))
+			}
+		}
+	}
+	return all
+}
+
+// Encode writes the alignment as compact JSON with a trailing newline; the
+// views fetch it, so it carries no indentation.
+func (a *Alignment) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(a); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AlignmentPath returns the path of the alignment of scheme from with scheme
+// to in dir.
+func AlignmentPath(dir, from, to string) string {
+	return filepath.Join(dir, from+"-"+to+".json")
+}
+
+// WriteAlignments writes every alignment to dir, replacing any there.
+func WriteAlignments(dir string) ([]*Alignment, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	all := Alignments()
+	for _, a := range all {
+		data, err := a.Encode()
+		if err != nil {
+			return nil, err
+		}
+		path := AlignmentPath(dir, a.From, a.To)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return all, nil
+}
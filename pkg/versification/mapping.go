@@ -0,0 +1,530 @@
+package versification
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Ref is a verse reference. Verse 0 of a psalm is its title, which the kjva
+// scheme prints unnumbered and the Hebrew and Greek schemes count as verses.
+type Ref struct {
+	Book    string
+	Chapter int
+	Verse   int
+}
+
+// String returns the reference as an OSIS ID, such as "Ps.23.1".
+func (r Ref) String() string {
+	return r.Book + "." + strconv.Itoa(r.Chapter) + "." + strconv.Itoa(r.Verse)
+}
+
+// ParseRef parses an OSIS verse ID such as "Ps.23.1".
+func ParseRef(s string) (Ref, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return Ref{}, fmt.Errorf("invalid OSIS verse %q", s)
+	}
+	chapter, err := strconv.Atoi(parts[1])
+	if err != nil || chapter < 1 {
+		return Ref{}, fmt.Errorf("invalid chapter in OSIS verse %q", s)
+	}
+	verse, err := strconv.Atoi(parts[2])
+	if err != nil || verse < 0 {
+		return Ref{}, fmt.Errorf("invalid verse in OSIS verse %q", s)
+	}
+	return Ref{Book: parts[0], Chapter: chapter, Verse: verse}, nil
+}
+
+// verseRange is the first and last verse of a chapter; a zero last verse is
+// a chapter the book does not have.
+type verseRange struct{ first, last int }
+
+// Chapters returns the number of the book's last chapter, or 0 when the
+// scheme does not have the book or has no tables for it.
+func (s *Scheme) Chapters(book string) int {
+	return len(s.verses[book])
+}
+
+// Verses returns the first and last verse of a chapter, or 0, 0 when the
+// scheme does not have the chapter.
+func (s *Scheme) Verses(book string, chapter int) (first, last int) {
+	chapters := s.verses[book]
+	if chapter < 1 || chapter > len(chapters) {
+		return 0, 0
+	}
+	r := chapters[chapter-1]
+	return r.first, r.last
+}
+
+// Contains reports whether r is a verse of the scheme.
+func (s *Scheme) Contains(r Ref) bool {
+	first, last := s.Verses(r.Book, r.Chapter)
+	return last > 0 && r.Verse >= first && r.Verse <= last
+}
+
+// Unnumbered returns the index of the first of a text's verses that is not a
+// verse of the scheme, or -1 when the scheme numbers them all. A text may
+// lack verses the scheme has. Verses of a book the scheme holds within
+// another are numbered as that book and are not checked.
+func (s *Scheme) Unnumbered(refs []Ref) int {
+	held := map[string]bool{}
+	for i, r := range refs {
+		if !s.Has(r.Book) {
+			if _, ok := held[r.Book]; !ok {
+				held[r.Book] = s.Holds(r.Book)
+			}
+			if held[r.Book] {
+				continue
+			}
+			return i
+		}
+		if !s.Contains(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Map returns the verses of scheme to that hold the text of verse r of
+// scheme from, in order. It returns none when to does not have the text, as
+// the protestant scheme has no Tobit, or when r is not a verse of from. A
+// verse one scheme splits in two maps to both halves, and both halves map
+// back to it.
+func Map(from, to *Scheme, r Ref) []Ref {
+	var refs []Ref
+	for _, h := range from.toHub(r) {
+		for _, t := range to.fromHub(h) {
+			if !slices.Contains(refs, t) {
+				refs = append(refs, t)
+			}
+		}
+	}
+	return refs
+}
+
+// toHub returns the kjva verses holding the text of verse r of s.
+func (s *Scheme) toHub(r Ref) []Ref {
+	if !s.Contains(r) {
+		return nil
+	}
+	if refs, ok := s.toKJVA[r]; ok {
+		return refs
+	}
+	return []Ref{r}
+}
+
+// fromHub returns the verses of s holding the text of kjva verse h.
+func (s *Scheme) fromHub(h Ref) []Ref {
+	if refs, ok := s.fromKJVA[h]; ok {
+		return refs
+	}
+	if s.Has(h.Book) && schemes[KJVA].Contains(h) {
+		return []Ref{h}
+	}
+	return nil
+}
+
+// span is verses first through last of one chapter.
+type span struct {
+	book        string
+	chapter     int
+	first, last int
+}
+
+func at(book string, chapter, first, last int) span {
+	return span{book: book, chapter: chapter, first: first, last: last}
+}
+
+func (s span) refs() []Ref {
+	if s.book == "" {
+		return nil
+	}
+	refs := make([]Ref, 0, s.last-s.first+1)
+	for v := s.first; v <= s.last; v++ {
+		refs = append(refs, Ref{Book: s.book, Chapter: s.chapter, Verse: v})
+	}
+	return refs
+}
+
+// rule relates verses of a scheme to the kjva verses that hold the same
+// text. The two sides are the same length, or one side is a single verse
+// the other side's verses all share. An empty kjva side marks text the kjva
+// scheme does not have at all, and an empty scheme side kjva text the
+// scheme does not have. Verses no rule covers keep their reference.
+type rule struct {
+	scheme, kjva span
+}
+
+// titles is how many verses each psalm's title takes in the Hebrew and
+// Greek numbering, where the kjva scheme prints the title unnumbered.
+var titles = func() map[int]int {
+	m := map[int]int{}
+	for _, p := range []int{
+		3, 4, 5, 6, 7, 8, 9, 12, 18, 19, 20, 21, 22, 30, 31, 34, 36, 38, 39, 40,
+		41, 42, 44, 45, 46, 47, 48, 49, 53, 55, 56, 57, 58, 59, 61, 62, 63, 64, 65, 67,
+		68, 69, 70, 75, 76, 77, 80, 81, 83, 84, 85, 88, 89, 92, 102, 108, 140, 142,
+	} {
+		m[p] = 1
+	}
+	for _, p := range []int{51, 52, 54, 60} {
+		m[p] = 2
+	}
+	return m
+}()
+
+// hebrewPsalmVerses returns the number of verses of a psalm in Hebrew
+// numbering.
+func hebrewPsalmVerses(p int) int {
+	return kjvaVerses["Ps"][p-1] + titles[p]
+}
+
+// hebrewPsalm returns the kjva verse holding verse v of psalm p in Hebrew
+// numbering.
+func hebrewPsalm(p, v int) span {
+	if v <= titles[p] {
+		return at("Ps", p, 0, 0)
+	}
+	return at("Ps", p, v-titles[p], v-titles[p])
+}
+
+// hebrewPsalms numbers the Psalms as the Hebrew Bible does, counting titles
+// as verses.
+func hebrewPsalms() []rule {
+	var rules []rule
+	for p := 1; p <= 150; p++ {
+		if t := titles[p]; t > 0 {
+			n := kjvaVerses["Ps"][p-1]
+			rules = append(rules,
+				rule{at("Ps", p, 1, t), at("Ps", p, 0, 0)},
+				rule{at("Ps", p, t+1, t+n), at("Ps", p, 1, n)})
+		}
+	}
+	return rules
+}
+
+// greekPsalms numbers the Psalms as the Septuagint and Vulgate do: Psalms 9
+// and 10 are one psalm, as are 114 and 115, while 116 and 147 are split in
+// two, so most psalms are numbered one lower than in the Hebrew. Titles
+// count as verses as they do in the Hebrew, and verses are divided as the
+// Hebrew divides them.
+func greekPsalms() []rule {
+	var rules []rule
+	// psalm relates n verses of Greek psalm g from verse gv to Hebrew psalm
+	// h from verse hv.
+	psalm := func(g, gv, h, hv, n int) {
+		for i := range n {
+			rules = append(rules, rule{at("Ps", g, gv+i, gv+i), hebrewPsalm(h, hv+i)})
+		}
+	}
+	for h := 1; h <= 150; h++ {
+		n := hebrewPsalmVerses(h)
+		switch {
+		case h <= 9 || h >= 148:
+			psalm(h, 1, h, 1, n)
+		case h == 10:
+			psalm(9, hebrewPsalmVerses(9)+1, 10, 1, n)
+		case h == 114:
+			psalm(113, 1, 114, 1, n)
+		case h == 115:
+			psalm(113, hebrewPsalmVerses(114)+1, 115, 1, n)
+		case h == 116:
+			psalm(114, 1, 116, 1, 9)
+			psalm(115, 1, 116, 10, n-9)
+		case h == 147:
+			psalm(146, 1, 147, 1, 11)
+			psalm(147, 1, 147, 12, n-11)
+		default:
+			psalm(h-1, 1, h, 1, n)
+		}
+	}
+	return rules
+}
+
+// hebrewJoel divides Joel into four chapters, starting the third at the
+// outpouring of the spirit.
+var hebrewJoel = []rule{
+	{at("Joel", 3, 1, 5), at("Joel", 2, 28, 32)},
+	{at("Joel", 4, 1, 21), at("Joel", 3, 1, 21)},
+}
+
+// hebrewDaniel moves Nebuchadnezzar's proclamation to the end of chapter 3
+// and Darius's accession to the start of chapter 6.
+var hebrewDaniel = []rule{
+	{at("Dan", 3, 31, 33), at("Dan", 4, 1, 3)},
+	{at("Dan", 4, 1, 34), at("Dan", 4, 4, 37)},
+	{at("Dan", 6, 1, 1), at("Dan", 5, 31, 31)},
+	{at("Dan", 6, 2, 29), at("Dan", 6, 1, 28)},
+}
+
+// greekDaniel sets the Prayer of Azariah and the Song of the Three inside
+// Daniel 3, as Daniel 3:24-90, before the proclamation that the Hebrew also
+// moves. The English numbers one more verse of the song than the Greek, so
+// its last two verses share Daniel 3:90.
+var greekDaniel = []rule{
+	{at("Dan", 3, 24, 89), at("PrAzar", 1, 1, 66)},
+	{at("Dan", 3, 90, 90), at("PrAzar", 1, 67, 68)},
+	{at("Dan", 3, 91, 97), at("Dan", 3, 24, 30)},
+	{at("Dan", 3, 98, 100), at("Dan", 4, 1, 3)},
+	{at("Dan", 4, 1, 34), at("Dan", 4, 4, 37)},
+}
+
+// vulgateDaniel adds Susanna as Daniel 13 and Bel and the Dragon as Daniel
+// 14, whose last verse the English does not have.
+var vulgateDaniel = []rule{
+	{at("Dan", 13, 1, 64), at("Sus", 1, 1, 64)},
+	{at("Dan", 13, 65, 65), at("Bel", 1, 1, 1)},
+	{at("Dan", 14, 1, 41), at("Bel", 1, 2, 42)},
+	{at("Dan", 14, 42, 42), span{}},
+}
+
+// vulgateEsther appends the Additions to Esther to Esther as chapters 10:4
+// to 16, as the Vulgate does, with three verses of Mordecai's charge to
+// Esther at the head of chapter 15 that the English does not have.
+var vulgateEsther = []rule{
+	{at("Esth", 10, 4, 13), at("AddEsth", 10, 4, 13)},
+	{at("Esth", 11, 1, 12), at("AddEsth", 11, 1, 12)},
+	{at("Esth", 12, 1, 6), at("AddEsth", 12, 1, 6)},
+	{at("Esth", 13, 1, 18), at("AddEsth", 13, 1, 18)},
+	{at("Esth", 14, 1, 19), at("AddEsth", 14, 1, 19)},
+	{at("Esth", 15, 1, 3), span{}},
+	{at("Esth", 15, 4, 19), at("AddEsth", 15, 1, 16)},
+	{at("Esth", 16, 1, 24), at("AddEsth", 16, 1, 24)},
+}
+
+// hebrewChapters moves the chapter boundaries the Hebrew Bible draws
+// elsewhere than the English, as it starts Genesis 32 a verse early and ends
+// Isaiah 8 a verse late. The English divides some verses the Hebrew keeps
+// whole, or the other way round, and numbers a verse of Nehemiah 7 the
+// Hebrew does not have.
+var hebrewChapters = []rule{
+	{at("Gen", 32, 1, 1), at("Gen", 31, 55, 55)},
+	{at("Gen", 32, 2, 33), at("Gen", 32, 1, 32)},
+	{at("Exod", 7, 26, 29), at("Exod", 8, 1, 4)},
+	{at("Exod", 8, 1, 28), at("Exod", 8, 5, 32)},
+	{at("Exod", 21, 37, 37), at("Exod", 22, 1, 1)},
+	{at("Exod", 22, 1, 30), at("Exod", 22, 2, 31)},
+	{at("Lev", 5, 20, 26), at("Lev", 6, 1, 7)},
+	{at("Lev", 6, 1, 23), at("Lev", 6, 8, 30)},
+	{at("Num", 17, 1, 15), at("Num", 16, 36, 50)},
+	{at("Num", 17, 16, 28), at("Num", 17, 1, 13)},
+	{at("Num", 30, 1, 1), at("Num", 29, 40, 40)},
+	{at("Num", 30, 2, 17), at("Num", 30, 1, 16)},
+	{at("Deut", 13, 1, 1), at("Deut", 12, 32, 32)},
+	{at("Deut", 13, 2, 19), at("Deut", 13, 1, 18)},
+	{at("Deut", 23, 1, 1), at("Deut", 22, 30, 30)},
+	{at("Deut", 23, 2, 26), at("Deut", 23, 1, 25)},
+	{at("Deut", 28, 69, 69), at("Deut", 29, 1, 1)},
+	{at("Deut", 29, 1, 28), at("Deut", 29, 2, 29)},
+	{at("1Sam", 20, 42, 42), at("1Sam", 20, 42, 42)},
+	{at("1Sam", 21, 1, 1), at("1Sam", 20, 42, 42)},
+	{at("1Sam", 21, 2, 16), at("1Sam", 21, 1, 15)},
+	{at("1Sam", 24, 1, 1), at("1Sam", 23, 29, 29)},
+	{at("1Sam", 24, 2, 23), at("1Sam", 24, 1, 22)},
+	{at("2Sam", 19, 1, 1), at("2Sam", 18, 33, 33)},
+	{at("2Sam", 19, 2, 44), at("2Sam", 19, 1, 43)},
+	{at("1Kgs", 5, 1, 14), at("1Kgs", 4, 21, 34)},
+	{at("1Kgs", 5, 15, 32), at("1Kgs", 5, 1, 18)},
+	{at("1Kgs", 22, 43, 44), at("1Kgs", 22, 43, 43)},
+	{at("1Kgs", 22, 45, 54), at("1Kgs", 22, 44, 53)},
+	{at("2Kgs", 12, 1, 1), at("2Kgs", 11, 21, 21)},
+	{at("2Kgs", 12, 2, 22), at("2Kgs", 12, 1, 21)},
+	{at("1Chr", 5, 27, 41), at("1Chr", 6, 1, 15)},
+	{at("1Chr", 6, 1, 66), at("1Chr", 6, 16, 81)},
+	{at("1Chr", 12, 4, 5), at("1Chr", 12, 4, 4)},
+	{at("1Chr", 12, 6, 41), at("1Chr", 12, 5, 40)},
+	{at("2Chr", 1, 18, 18), at("2Chr", 2, 1, 1)},
+	{at("2Chr", 2, 1, 17), at("2Chr", 2, 2, 18)},
+	{at("2Chr", 13, 23, 23), at("2Chr", 14, 1, 1)},
+	{at("2Chr", 14, 1, 14), at("2Chr", 14, 2, 15)},
+	{at("Neh", 3, 33, 38), at("Neh", 4, 1, 6)},
+	{at("Neh", 4, 1, 17), at("Neh", 4, 7, 23)},
+	{span{}, at("Neh", 7, 68, 68)},
+	{at("Neh", 7, 68, 72), at("Neh", 7, 69, 73)},
+	{at("Neh", 10, 1, 1), at("Neh", 9, 38, 38)},
+	{at("Neh", 10, 2, 40), at("Neh", 10, 1, 39)},
+	{at("Job", 40, 25, 32), at("Job", 41, 1, 8)},
+	{at("Job", 41, 1, 26), at("Job", 41, 9, 34)},
+	{at("Eccl", 4, 17, 17), at("Eccl", 5, 1, 1)},
+	{at("Eccl", 5, 1, 19), at("Eccl", 5, 2, 20)},
+	{at("Song", 7, 1, 1), at("Song", 6, 13, 13)},
+	{at("Song", 7, 2, 14), at("Song", 7, 1, 13)},
+	{at("Isa", 8, 23, 23), at("Isa", 9, 1, 1)},
+	{at("Isa", 9, 1, 20), at("Isa", 9, 2, 21)},
+	{at("Isa", 63, 19, 19), at("Isa", 63, 19, 19)},
+	{at("Isa", 63, 19, 19), at("Isa", 64, 1, 1)},
+	{at("Isa", 64, 1, 11), at("Isa", 64, 2, 12)},
+	{at("Jer", 8, 23, 23), at("Jer", 9, 1, 1)},
+	{at("Jer", 9, 1, 25), at("Jer", 9, 2, 26)},
+	{at("Ezek", 21, 1, 5), at("Ezek", 20, 45, 49)},
+	{at("Ezek", 21, 6, 37), at("Ezek", 21, 1, 32)},
+	{at("Hos", 2, 1, 2), at("Hos", 1, 10, 11)},
+	{at("Hos", 2, 3, 25), at("Hos", 2, 1, 23)},
+	{at("Hos", 12, 1, 1), at("Hos", 11, 12, 12)},
+	{at("Hos", 12, 2, 15), at("Hos", 12, 1, 14)},
+	{at("Hos", 14, 1, 1), at("Hos", 13, 16, 16)},
+	{at("Hos", 14, 2, 10), at("Hos", 14, 1, 9)},
+	{at("Jonah", 2, 1, 1), at("Jonah", 1, 17, 17)},
+	{at("Jonah", 2, 2, 11), at("Jonah", 2, 1, 10)},
+	{at("Mic", 4, 14, 14), at("Mic", 5, 1, 1)},
+	{at("Mic", 5, 1, 14), at("Mic", 5, 2, 15)},
+	{at("Nah", 2, 1, 1), at("Nah", 1, 15, 15)},
+	{at("Nah", 2, 2, 14), at("Nah", 2, 1, 13)},
+	{at("Zech", 2, 1, 4), at("Zech", 1, 18, 21)},
+	{at("Zech", 2, 5, 17), at("Zech", 2, 1, 13)},
+	// Malachi 4 is the end of chapter 3.
+	{at("Mal", 3, 19, 24), at("Mal", 4, 1, 6)},
+}
+
+// rules holds how each scheme departs from the kjva scheme. Books a scheme
+// has that the kjva scheme does not, such as 3 Maccabees or the Odes, have
+// no tables yet; Tobit, Judith and Sirach keep the kjva numbering in every
+// scheme that has them, as do the Hebrew chapters other than those below.
+var rules = map[string][]rule{
+	Protestant: nil,
+	KJVA:       nil,
+	NRSV: {
+		// The NRSV ends 3 John at verse 15 and Revelation 12 at verse 18,
+		// where the kjva scheme runs them into 3 John 14 and Revelation 13.
+		{at("3John", 1, 14, 15), at("3John", 1, 14, 14)},
+		{at("Rev", 12, 18, 18), at("Rev", 13, 1, 1)},
+		{at("Rev", 13, 1, 1), at("Rev", 13, 1, 1)},
+	},
+	Leningrad: slices.Concat(hebrewPsalms(), hebrewJoel, hebrewDaniel, hebrewChapters),
+	Catholic:  slices.Concat(greekPsalms(), greekDaniel, vulgateDaniel, vulgateEsther),
+	Orthodox: slices.Concat(greekPsalms(), hebrewJoel, greekDaniel, vulgateEsther, []rule{
+		// Psalm 151 is in the Septuagint alone.
+		{at("Ps", 151, 1, 7), span{}},
+		// Malachi 4 is the end of chapter 3, with its call to remember the
+		// law of Moses put last.
+		{at("Mal", 3, 19, 21), at("Mal", 4, 1, 3)},
+		{at("Mal", 3, 22, 23), at("Mal", 4, 5, 6)},
+		{at("Mal", 3, 24, 24), at("Mal", 4, 4, 4)},
+		// The Epistle of Jeremiah stands on its own.
+		{at("EpJer", 1, 1, 73), at("Bar", 6, 1, 73)},
+	}),
+}
+
+// init builds every scheme's tables from the kjva tables and the scheme's
+// rules, and panics if the rules contradict each other.
+func init() {
+	kjva := schemes[KJVA]
+	kjva.verses = map[string][]verseRange{}
+	for book, lasts := range kjvaVerses {
+		for ch, last := range lasts {
+			first := 1
+			if f, ok := kjvaFirst[fmt.Sprintf("%s.%d", book, ch+1)]; ok {
+				first = f
+			}
+			if last == 0 {
+				first = 0
+			}
+			kjva.verses[book] = append(kjva.verses[book], verseRange{first, last})
+		}
+	}
+	for _, name := range Names() {
+		if name != KJVA {
+			if err := schemes[name].build(rules[name]); err != nil {
+				panic(fmt.Sprintf("versification: %s: %v", name, err))
+			}
+		}
+	}
+}
+
+// build indexes a scheme's rules and derives its tables from the verses the
+// kjva verses map to.
+func (s *Scheme) build(rules []rule) error {
+	kjva := schemes[KJVA]
+	s.toKJVA = map[Ref][]Ref{}
+	s.fromKJVA = map[Ref][]Ref{}
+	for _, r := range rules {
+		from, to := r.scheme.refs(), r.kjva.refs()
+		if len(from) == 0 {
+			for _, h := range to {
+				s.fromKJVA[h] = []Ref{}
+			}
+			continue
+		}
+		if !s.Has(r.scheme.book) {
+			return fmt.Errorf("rule for %s, which the scheme does not have", r.scheme.book)
+		}
+		for _, h := range to {
+			if !kjva.Contains(h) && !(h.Book == "Ps" && h.Verse == 0) {
+				return fmt.Errorf("rule maps to %s, which is not a kjva verse", h)
+			}
+		}
+		switch {
+		case len(from) == len(to):
+			for i := range from {
+				s.link(from[i], to[i])
+			}
+		case len(to) == 0:
+			for _, f := range from {
+				s.toKJVA[f] = []Ref{}
+			}
+		case len(from) == 1 || len(to) == 1:
+			for _, f := range from {
+				for _, h := range to {
+					s.link(f, h)
+				}
+			}
+		default:
+			return fmt.Errorf("rule relates %d verses from %s to %d", len(from), from[0], len(to))
+		}
+	}
+
+	verses := map[string]map[int][]int{}
+	add := func(r Ref) {
+		if verses[r.Book] == nil {
+			verses[r.Book] = map[int][]int{}
+		}
+		verses[r.Book][r.Chapter] = append(verses[r.Book][r.Chapter], r.Verse)
+	}
+	for r := range s.toKJVA {
+		add(r)
+	}
+	for book, lasts := range kjvaVerses {
+		for ch := range lasts {
+			first, last := kjva.Verses(book, ch+1)
+			for v := first; v <= last && last > 0; v++ {
+				h := Ref{Book: book, Chapter: ch + 1, Verse: v}
+				if _, ok := s.fromKJVA[h]; ok || !s.Has(book) {
+					continue
+				}
+				if _, ok := s.toKJVA[h]; ok {
+					return fmt.Errorf("%s keeps its reference but a rule also maps it", h)
+				}
+				add(h)
+			}
+		}
+	}
+
+	s.verses = map[string][]verseRange{}
+	for book, chapters := range verses {
+		ranges := make([]verseRange, slices.Max(mapKeys(chapters)))
+		for ch, vs := range chapters {
+			slices.Sort(vs)
+			if vs[len(vs)-1]-vs[0]+1 != len(vs) {
+				return fmt.Errorf("%s %d has gaps", book, ch)
+			}
+			ranges[ch-1] = verseRange{vs[0], vs[len(vs)-1]}
+		}
+		s.verses[book] = ranges
+	}
+	return nil
+}
+
+// link records that scheme verse f holds the text of kjva verse h.
+func (s *Scheme) link(f, h Ref) {
+	s.toKJVA[f] = append(s.toKJVA[f], h)
+	s.fromKJVA[h] = append(s.fromKJVA[h], f)
+}
+
+func mapKeys(m map[int][]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,177 @@
+// Package versification describes the versification schemes bibles.json
+// declares: which OSIS books each contains, in what order, how many chapters
+// and verses each chapter has, and which verses of one scheme hold the text
+// of which verses of another.
+//
+// The schemes follow the SWORD canons of the same names. orthodox also
+// holds the Septuagint books that the LXX carries beyond the Orthodox
+// canon, Odes and the Psalms of Solomon among them, and leningrad is the
+// Hebrew Bible in the order of the Leningrad Codex.
+//
+// Every scheme maps to the others through kjva, whose tables are the
+// package's own. The other schemes' tables follow from kjva's and the rules
+// by which each departs from it: the Hebrew and Greek numbering of the
+// Psalms, Malachi 4 as the end of chapter 3, Joel in four chapters, and the
+// Greek additions to Daniel and Esther.
+package versification
+
+import "slices"
+
+// Testament is a testament as bibles_auxiliary files record it.
+type Testament string
+
+// Testaments.
+const (
+	OT Testament = "OT"
+	NT Testament = "NT"
+	// AP is the Apocrypha or deuterocanon.
+	AP Testament = "AP"
+)
+
+// Scheme names as bibles.json declares them.
+const (
+	Protestant = "protestant"
+	KJVA       = "kjva"
+	Catholic   = "catholic"
+	NRSV       = "nrsv"
+	Orthodox   = "orthodox"
+	Leningrad  = "leningrad"
+)
+
+// Book is an OSIS book.
+type Book struct {
+	ID        string
+	Testament Testament
+}
+
+// Scheme is a versification scheme.
+type Scheme struct {
+	Name string
+	// Books are the OSIS IDs of the scheme's books in canonical order.
+	Books []string
+
+	verses   map[string][]verseRange
+	toKJVA   map[Ref][]Ref
+	fromKJVA map[Ref][]Ref
+}
+
+// Has reports whether the scheme contains the book.
+func (s *Scheme) Has(book string) bool {
+	return slices.Contains(s.Books, book)
+}
+
+// Holds reports whether the scheme has the text of the book, as a book of
+// its own or within another, as kjva holds the Epistle of Jeremiah as
+// Baruch 6. Bibles may file such text as a book of its own.
+func (s *Scheme) Holds(book string) bool {
+	if s.Has(book) {
+		return true
+	}
+	for _, name := range Names() {
+		other := schemes[name]
+		for ch := 1; ch <= other.Chapters(book); ch++ {
+			if first, last := other.Verses(book, ch); last > 0 {
+				return len(Map(other, s, Ref{Book: book, Chapter: ch, Verse: first})) > 0
+			}
+		}
+	}
+	return false
+}
+
+var (
+	oldTestament = []string{
+		"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "Judg", "Ruth", "1Sam", "2Sam",
+		"1Kgs", "2Kgs", "1Chr", "2Chr", "Ezra", "Neh", "Esth", "Job", "Ps", "Prov",
+		"Eccl", "Song", "Isa", "Jer", "Lam", "Ezek", "Dan", "Hos", "Joel", "Amos",
+		"Obad", "Jonah", "Mic", "Nah", "Hab", "Zeph", "Hag", "Zech", "Mal",
+	}
+	newTestament = []string{
+		"Matt", "Mark", "Luke", "John", "Acts", "Rom", "1Cor", "2Cor", "Gal", "Eph",
+		"Phil", "Col", "1Thess", "2Thess", "1Tim", "2Tim", "Titus", "Phlm", "Heb", "Jas",
+		"1Pet", "2Pet", "1John", "2John", "3John", "Jude", "Rev",
+	}
+	apocrypha = []string{
+		"Tob", "Jdt", "AddEsth", "EsthGr", "Wis", "Sir", "Bar", "EpJer", "PrAzar", "Sus",
+		"Bel", "1Macc", "2Macc", "3Macc", "4Macc", "1Esd", "2Esd", "PrMan", "AddPs", "Odes",
+		"PssSol", "EpLao",
+	}
+	// The Septuagint's second text of Joshua, Judges and Tobit, and
+	// Theodotion's Daniel, Susanna and Bel, as SWORD names them.
+	lxxOldTestament = []string{"JoshA", "JudgB", "DanTh"}
+	lxxApocrypha    = []string{"TobS", "SusTh", "BelTh"}
+)
+
+// books holds every known OSIS book by ID.
+var books = func() map[string]Book {
+	m := map[string]Book{}
+	for _, group := range []struct {
+		ids       []string
+		testament Testament
+	}{{oldTestament, OT}, {lxxOldTestament, OT}, {newTestament, NT}, {apocrypha, AP}, {lxxApocrypha, AP}} {
+		for _, id := range group.ids {
+			m[id] = Book{ID: id, Testament: group.testament}
+		}
+	}
+	return m
+}()
+
+// schemes holds every scheme by name.
+var schemes = map[string]*Scheme{
+	Protestant: {Name: Protestant, Books: slices.Concat(oldTestament, newTestament)},
+	NRSV:       {Name: NRSV, Books: slices.Concat(oldTestament, newTestament)},
+	KJVA: {Name: KJVA, Books: slices.Concat(oldTestament, []string{
+		"1Esd", "2Esd", "Tob", "Jdt", "AddEsth", "Wis", "Sir", "Bar", "PrAzar", "Sus",
+		"Bel", "PrMan", "1Macc", "2Macc",
+	}, newTestament)},
+	Catholic: {Name: Catholic, Books: slices.Concat([]string{
+		"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "Judg", "Ruth", "1Sam", "2Sam",
+		"1Kgs", "2Kgs", "1Chr", "2Chr", "Ezra", "Neh", "Tob", "Jdt", "Esth", "1Macc",
+		"2Macc", "Job", "Ps", "Prov", "Eccl", "Song", "Wis", "Sir", "Isa", "Jer",
+		"Lam", "Bar", "Ezek", "Dan", "Hos", "Joel", "Amos", "Obad", "Jonah", "Mic",
+		"Nah", "Hab", "Zeph", "Hag", "Zech", "Mal",
+	}, newTestament)},
+	Orthodox: {Name: Orthodox, Books: slices.Concat([]string{
+		"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "JoshA", "Judg", "JudgB", "Ruth",
+		"1Sam", "2Sam", "1Kgs", "2Kgs", "1Chr", "2Chr", "PrMan", "1Esd", "Ezra", "Neh",
+		"Tob", "TobS", "Jdt", "Esth", "1Macc", "2Macc", "3Macc", "Ps", "Odes", "Job",
+		"Prov", "Eccl", "Song", "Wis", "Sir", "PssSol", "Hos", "Amos", "Mic", "Joel",
+		"Obad", "Jonah", "Nah", "Hab", "Zeph", "Hag", "Zech", "Mal", "Isa", "Jer",
+		"Bar", "Lam", "EpJer", "Ezek", "Sus", "SusTh", "Dan", "DanTh", "Bel", "BelTh",
+		"4Macc",
+	}, newTestament)},
+	Leningrad: {Name: Leningrad, Books: []string{
+		"Gen", "Exod", "Lev", "Num", "Deut", "Josh", "Judg", "1Sam", "2Sam", "1Kgs",
+		"2Kgs", "Isa", "Jer", "Ezek", "Hos", "Joel", "Amos", "Obad", "Jonah", "Mic",
+		"Nah", "Hab", "Zeph", "Hag", "Zech", "Mal", "1Chr", "2Chr", "Ps", "Job",
+		"Prov", "Ruth", "Song", "Eccl", "Lam", "Esth", "Dan", "Ezra", "Neh",
+	}},
+}
+
+// Lookup returns the scheme with the given name.
+func Lookup(name string) (*Scheme, bool) {
+	s, ok := schemes[name]
+	return s, ok
+}
+
+// Names returns the names of every scheme, sorted.
+func Names() []string {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LookupBook returns the OSIS book with the given ID.
+func LookupBook(id string) (Book, bool) {
+	b, ok := books[id]
+	return b, ok
+}
+
+// FitsTestament reports whether a bibles_auxiliary testament suits the book.
+// Deuterocanonical books may be filed under the Old Testament as well as the
+// Apocrypha, as Catholic and Orthodox Bibles do.
+func (b Book) FitsTestament(t Testament) bool {
+	return t == b.Testament || (b.Testament == AP && t == OT)
+}
@@ -0,0 +1,396 @@
+package versification
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestSchemes verifies every scheme lists known books once and has the
+// expected number of books.
+func TestSchemes(t *testing.T) {
+	want := map[string]int{
+		Protestant: 66, NRSV: 66, KJVA: 80, Catholic: 73, Orthodox: 88, Leningrad: 39,
+	}
+	if got := Names(); !slices.Equal(got, []string{Catholic, KJVA, Leningrad, NRSV, Orthodox, Protestant}) {
+		t.Errorf("Names() = %v", got)
+	}
+	for name, count := range want {
+		s, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("no %s scheme", name)
+		}
+		if len(s.Books) != count {
+			t.Errorf("%s has %d books, want %d", name, len(s.Books), count)
+		}
+		seen := map[string]bool{}
+		for _, id := range s.Books {
+			if _, ok := LookupBook(id); !ok {
+				t.Errorf("%s lists unknown book %s", name, id)
+			}
+			if seen[id] {
+				t.Errorf("%s lists %s twice", name, id)
+			}
+			seen[id] = true
+		}
+	}
+	if _, ok := Lookup("kjv"); ok {
+		t.Error("kjv is not a scheme bibles.json declares")
+	}
+}
+
+// TestMembership spot-checks books in and out of schemes.
+func TestMembership(t *testing.T) {
+	tests := []struct {
+		scheme, book string
+		want         bool
+	}{
+		{Protestant, "Gen", true},
+		{Protestant, "Tob", false},
+		{Catholic, "Tob", true},
+		{Catholic, "PrMan", false},
+		{KJVA, "PrMan", true},
+		{Orthodox, "3Macc", true},
+		{Leningrad, "Matt", false},
+		{Leningrad, "Ps", true},
+		{Orthodox, "JudgB", true},
+		{KJVA, "EpJer", false},
+	}
+	for _, tt := range tests {
+		s, _ := Lookup(tt.scheme)
+		if got := s.Has(tt.book); got != tt.want {
+			t.Errorf("%s.Has(%s) = %v, want %v", tt.scheme, tt.book, got, tt.want)
+		}
+	}
+}
+
+// TestHolds verifies schemes hold books they number as part of another.
+func TestHolds(t *testing.T) {
+	tests := []struct {
+		scheme, book string
+		want         bool
+	}{
+		{KJVA, "Gen", true},
+		{KJVA, "EpJer", true},
+		{Catholic, "EpJer", true},
+		{Catholic, "PrAzar", true},
+		{Protestant, "EpJer", false},
+		{Leningrad, "Tob", false},
+		{KJVA, "Odes", false},
+		{Catholic, "EpLao", false},
+	}
+	for _, tt := range tests {
+		s, _ := Lookup(tt.scheme)
+		if got := s.Holds(tt.book); got != tt.want {
+			t.Errorf("%s.Holds(%s) = %v, want %v", tt.scheme, tt.book, got, tt.want)
+		}
+	}
+}
+
+// TestUnnumbered verifies which verses of a text a scheme does not number.
+func TestUnnumbered(t *testing.T) {
+	tests := []struct {
+		scheme string
+		refs   string
+		want   int
+	}{
+		{KJVA, "Mal.4.1 Mal.4.6", -1},
+		{Leningrad, "Mal.3.24 Mal.4.1", 1},
+		{Leningrad, "Ps.3.9", -1},
+		{Protestant, "Ps.3.8", -1},
+		{Protestant, "Ps.3.9", 0},
+		{KJVA, "Gen.1.1 EpJer.1.73", -1},
+		{Protestant, "EpJer.1.1", 0},
+		{KJVA, "Rev.12.17 Rev.12.18", 1},
+		{NRSV, "Rev.12.18", -1},
+		{KJVA, "", -1},
+	}
+	for _, tt := range tests {
+		s, _ := Lookup(tt.scheme)
+		var refs []Ref
+		for _, id := range strings.Fields(tt.refs) {
+			r, err := ParseRef(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs = append(refs, r)
+		}
+		if got := s.Unnumbered(refs); got != tt.want {
+			t.Errorf("%s.Unnumbered(%s) = %d, want %d", tt.scheme, tt.refs, got, tt.want)
+		}
+	}
+}
+
+// TestFitsTestament verifies deuterocanonical books may be filed under OT.
+func TestFitsTestament(t *testing.T) {
+	tests := []struct {
+		book      string
+		testament Testament
+		want      bool
+	}{
+		{"Gen", OT, true},
+		{"Gen", NT, false},
+		{"Gen", AP, false},
+		{"John", NT, true},
+		{"Tob", AP, true},
+		{"Tob", OT, true},
+		{"Tob", NT, false},
+	}
+	for _, tt := range tests {
+		b, _ := LookupBook(tt.book)
+		if got := b.FitsTestament(tt.testament); got != tt.want {
+			t.Errorf("%s fits %s = %v, want %v", tt.book, tt.testament, got, tt.want)
+		}
+	}
+}
+
+// TestTables spot-checks chapter and verse counts, including those the
+// schemes' rules derive from the kjva tables.
+func TestTables(t *testing.T) {
+	tests := []struct {
+		scheme, book string
+		chapter      int
+		first, last  int
+		chapters     int
+	}{
+		{KJVA, "Gen", 1, 1, 31, 50},
+		{KJVA, "Ps", 51, 1, 19, 150},
+		{KJVA, "AddEsth", 9, 0, 0, 16},
+		{KJVA, "AddEsth", 10, 4, 13, 16},
+		{KJVA, "Bar", 6, 1, 73, 6},
+		{KJVA, "Rev", 12, 1, 17, 22},
+		{KJVA, "Rev", 22, 1, 21, 22},
+		{Protestant, "Tob", 1, 0, 0, 0},
+		{Protestant, "Mal", 4, 1, 6, 4},
+		{NRSV, "3John", 1, 1, 15, 1},
+		{NRSV, "Rev", 12, 1, 18, 22},
+		{Leningrad, "Ps", 3, 1, 9, 150},
+		{Leningrad, "Ps", 51, 1, 21, 150},
+		{Leningrad, "Mal", 3, 1, 24, 3},
+		{Leningrad, "Joel", 2, 1, 27, 4},
+		{Leningrad, "Joel", 3, 1, 5, 4},
+		{Leningrad, "Dan", 3, 1, 33, 12},
+		{Leningrad, "Dan", 5, 1, 30, 12},
+		{Leningrad, "Esth", 10, 1, 3, 10},
+		{Leningrad, "Gen", 31, 1, 54, 50},
+		{Leningrad, "Gen", 32, 1, 33, 50},
+		{Leningrad, "Isa", 8, 1, 23, 66},
+		{Leningrad, "Isa", 9, 1, 20, 66},
+		{Leningrad, "1Chr", 5, 1, 41, 29},
+		{Leningrad, "Neh", 7, 1, 72, 13},
+		{Leningrad, "Job", 40, 1, 32, 42},
+		{Catholic, "Ps", 9, 1, 39, 150},
+		{Catholic, "Ps", 50, 1, 21, 150},
+		{Catholic, "Ps", 113, 1, 26, 150},
+		{Catholic, "Ps", 115, 1, 10, 150},
+		{Catholic, "Ps", 147, 1, 9, 150},
+		{Catholic, "Dan", 3, 1, 100, 14},
+		{Catholic, "Dan", 13, 1, 65, 14},
+		{Catholic, "Esth", 15, 1, 19, 16},
+		{Catholic, "Bar", 6, 1, 73, 6},
+		{Catholic, "Mal", 4, 1, 6, 4},
+		{Orthodox, "Ps", 151, 1, 7, 151},
+		{Orthodox, "Mal", 3, 1, 24, 3},
+		{Orthodox, "Dan", 12, 1, 13, 12},
+		{Orthodox, "EpJer", 1, 1, 73, 1},
+		{Orthodox, "Bar", 6, 0, 0, 5},
+		{Orthodox, "3Macc", 1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		s, _ := Lookup(tt.scheme)
+		if got := s.Chapters(tt.book); got != tt.chapters {
+			t.Errorf("%s %s has %d chapters, want %d", tt.scheme, tt.book, got, tt.chapters)
+		}
+		if first, last := s.Verses(tt.book, tt.chapter); first != tt.first || last != tt.last {
+			t.Errorf("%s %s %d has verses %d-%d, want %d-%d", tt.scheme, tt.book, tt.chapter, first, last, tt.first, tt.last)
+		}
+	}
+}
+
+// TestVerseCounts verifies each scheme's verse totals per testament. The
+// Protestant canon has 23,145 Old Testament and 7,957 New Testament verses,
+// and NRSV adds 3 John 1:15 and Revelation 12:18. The other schemes' totals
+// follow from kjva's tables and their rules, so a changed table shows here.
+func TestVerseCounts(t *testing.T) {
+	tests := []struct {
+		scheme     string
+		ot, nt, ap int
+		total      int
+	}{
+		{Protestant, 23145, 7957, 0, 31102},
+		{NRSV, 23145, 7959, 0, 31104},
+		{KJVA, 23145, 7957, 5780, 36882},
+		{Catholic, 23493, 7957, 4093, 35543},
+		{Orthodox, 23393, 7957, 4662, 36012},
+		{Leningrad, 23212, 0, 0, 23212},
+	}
+	for _, tt := range tests {
+		s, _ := Lookup(tt.scheme)
+		got := map[Testament]int{}
+		for _, id := range s.Books {
+			b, _ := LookupBook(id)
+			for c := 1; c <= s.Chapters(id); c++ {
+				if first, last := s.Verses(id, c); last > 0 {
+					got[b.Testament] += last - first + 1
+				}
+			}
+		}
+		if got[OT] != tt.ot || got[NT] != tt.nt || got[AP] != tt.ap {
+			t.Errorf("%s has %d OT, %d NT and %d AP verses, want %d, %d and %d",
+				tt.scheme, got[OT], got[NT], got[AP], tt.ot, tt.nt, tt.ap)
+		}
+		if total := got[OT] + got[NT] + got[AP]; total != tt.total {
+			t.Errorf("%s has %d verses, want %d", tt.scheme, total, tt.total)
+		}
+	}
+}
+
+// TestMap verifies verses line up across the phenomena the schemes differ
+// on.
+func TestMap(t *testing.T) {
+	tests := []struct {
+		from, to, ref string
+		want          string
+	}{
+		{Protestant, Protestant, "John.3.16", "John.3.16"},
+		{Protestant, Catholic, "John.3.16", "John.3.16"},
+		{Protestant, Catholic, "Ps.23.1", "Ps.22.1"},
+		{Protestant, Catholic, "Ps.10.1", "Ps.9.22"},
+		{Protestant, Catholic, "Ps.116.10", "Ps.115.1"},
+		{Protestant, Catholic, "Ps.147.12", "Ps.147.1"},
+		{Protestant, Leningrad, "Ps.51.1", "Ps.51.3"},
+		{Protestant, Leningrad, "Ps.3.8", "Ps.3.9"},
+		{Leningrad, Protestant, "Ps.51.1", ""},
+		{Leningrad, Catholic, "Ps.51.1", "Ps.50.1 Ps.50.2"},
+		{Catholic, Leningrad, "Ps.113.9", "Ps.115.1"},
+		{Orthodox, Leningrad, "Ps.9.22", "Ps.10.1"},
+		{Orthodox, Protestant, "Ps.151.1", ""},
+		{Protestant, Leningrad, "Mal.4.1", "Mal.3.19"},
+		{Leningrad, Protestant, "Mal.3.24", "Mal.4.6"},
+		{Orthodox, Protestant, "Mal.3.24", "Mal.4.4"},
+		{Orthodox, Leningrad, "Mal.3.22", "Mal.3.23"},
+		{Protestant, Leningrad, "Joel.2.28", "Joel.3.1"},
+		{Leningrad, Protestant, "Joel.4.21", "Joel.3.21"},
+		{Protestant, Leningrad, "Gen.32.1", "Gen.32.2"},
+		{Leningrad, Protestant, "Gen.32.1", "Gen.31.55"},
+		{Protestant, Leningrad, "Isa.9.1", "Isa.8.23"},
+		{Protestant, Leningrad, "Isa.9.2", "Isa.9.1"},
+		{Protestant, Leningrad, "Isa.64.1", "Isa.63.19"},
+		{Leningrad, Protestant, "Isa.63.19", "Isa.63.19 Isa.64.1"},
+		{Leningrad, Protestant, "1Sam.21.1", "1Sam.20.42"},
+		{Protestant, Leningrad, "1Sam.20.42", "1Sam.20.42 1Sam.21.1"},
+		{Protestant, Leningrad, "Neh.7.68", ""},
+		{Protestant, Leningrad, "Neh.7.73", "Neh.7.72"},
+		{Protestant, Leningrad, "Jonah.1.17", "Jonah.2.1"},
+		{Catholic, Leningrad, "Gen.32.1", "Gen.32.2"},
+		{Protestant, Leningrad, "Dan.4.1", "Dan.3.31"},
+		{Protestant, Leningrad, "Dan.5.31", "Dan.6.1"},
+		{Protestant, Catholic, "Dan.3.24", "Dan.3.91"},
+		{Catholic, Leningrad, "Dan.3.98", "Dan.3.31"},
+		{Catholic, KJVA, "Dan.3.24", "PrAzar.1.1"},
+		{KJVA, Catholic, "PrAzar.1.68", "Dan.3.90"},
+		{Catholic, KJVA, "Dan.3.90", "PrAzar.1.67 PrAzar.1.68"},
+		{Catholic, Protestant, "Dan.3.24", ""},
+		{Catholic, Orthodox, "Dan.13.1", "Sus.1.1"},
+		{Catholic, Orthodox, "Dan.13.65", "Bel.1.1"},
+		{Orthodox, Catholic, "Bel.1.42", "Dan.14.41"},
+		{Catholic, KJVA, "Dan.14.42", ""},
+		{KJVA, Catholic, "AddEsth.10.4", "Esth.10.4"},
+		{KJVA, Orthodox, "AddEsth.15.1", "Esth.15.4"},
+		{Catholic, KJVA, "Esth.15.3", ""},
+		{KJVA, Leningrad, "AddEsth.11.1", ""},
+		{KJVA, Orthodox, "Bar.6.1", "EpJer.1.1"},
+		{Orthodox, Catholic, "EpJer.1.73", "Bar.6.73"},
+		{KJVA, NRSV, "3John.1.14", "3John.1.14 3John.1.15"},
+		{NRSV, Protestant, "3John.1.15", "3John.1.14"},
+		{NRSV, KJVA, "Rev.12.18", "Rev.13.1"},
+		{KJVA, Protestant, "Tob.1.1", ""},
+		{Catholic, Orthodox, "Tob.1.1", "Tob.1.1"},
+		{Protestant, Catholic, "Gen.1.32", ""},
+		{Leningrad, Protestant, "Mal.4.1", ""},
+	}
+	for _, tt := range tests {
+		from, _ := Lookup(tt.from)
+		to, _ := Lookup(tt.to)
+		r, err := ParseRef(tt.ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, m := range Map(from, to, r) {
+			got = append(got, m.String())
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("Map(%s, %s, %s) = %v, want %s", tt.from, tt.to, tt.ref, got, tt.want)
+		}
+	}
+}
+
+// TestRoundTrip verifies every verse of every scheme maps to the kjva
+// verses that map back to it.
+func TestRoundTrip(t *testing.T) {
+	for _, name := range Names() {
+		s, _ := Lookup(name)
+		verses := 0
+		for _, book := range s.Books {
+			for ch := 1; ch <= s.Chapters(book); ch++ {
+				first, last := s.Verses(book, ch)
+				for v := first; v <= last && last > 0; v++ {
+					r := Ref{Book: book, Chapter: ch, Verse: v}
+					verses++
+					for _, h := range s.toHub(r) {
+						if !slices.Contains(s.fromHub(h), r) {
+							t.Errorf("%s %s maps to kjva %s, which maps to %v", name, r, h, s.fromHub(h))
+						}
+					}
+				}
+			}
+		}
+		if verses == 0 {
+			t.Errorf("%s has no verses", name)
+		}
+	}
+}
+
+// TestParseRef verifies OSIS verse IDs round-trip and malformed ones fail.
+func TestParseRef(t *testing.T) {
+	for _, s := range []string{"Gen.1.1", "1John.5.7", "Ps.51.0"} {
+		r, err := ParseRef(s)
+		if err != nil || r.String() != s {
+			t.Errorf("ParseRef(%q) = %v, %v", s, r, err)
+		}
+	}
+	for _, s := range []string{"", "Gen", "Gen.1", "Gen.0.1", "Gen.1.x", ".1.1", "Gen.1.1.1", "Gen.1.-1"} {
+		if _, err := ParseRef(s); err == nil {
+			t.Errorf("ParseRef(%q) succeeded", s)
+		}
+	}
+}
+
+// TestAlignments verifies the vendored alignments are current.
+func TestAlignments(t *testing.T) {
+	dir := filepath.Join("..", "..", "static", "versification")
+	all := Alignments()
+	if len(all) != 30 {
+		t.Errorf("%d alignments, want 30", len(all))
+	}
+	for _, a := range all {
+		want, err := a.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, err := os.ReadFile(AlignmentPath(dir, a.From, a.To))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Errorf("%s is out of date; run go run ./cmd/versification-map", AlignmentPath(dir, a.From, a.To))
+		}
+	}
+	if a := Align(schemes[Protestant], schemes[KJVA]); len(a.Verses) != 0 {
+		t.Errorf("protestant to kjva moves %d verses", len(a.Verses))
+	}
+}
@@ -0,0 +1,70 @@
+// Command selectorgen generates the typed selector constants in
+// helpers/selectors from the site's templates and scripts, and checks that
+// every selector the tests use still exists.
+//
+// Run from the tests directory:
+//
+//	go run ./cmd/selectorgen          # regenerate helpers/selectors
+//	go run ./cmd/selectorgen -check   # fail if stale or a selector is gone
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"michael-tests/selectorgen"
+)
+
+func main() {
+	root := flag.String("root", "..", "site root containing layouts/ and assets/")
+	out := flag.String("out", "helpers/selectors/selectors_gen.go", "generated file")
+	pkg := flag.String("pkg", "selectors", "package name of the generated file")
+	check := flag.Bool("check", false, "verify instead of writing: the generated file is current and every test selector exists")
+	dirs := flag.String("tests", "helpers,regression", "comma-separated directories whose selectors -check verifies")
+	flag.Parse()
+
+	if err := run(*root, *out, *pkg, *check, strings.Split(*dirs, ",")); err != nil {
+		fmt.Fprintln(os.Stderr, "selectorgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(root, out, pkg string, check bool, dirs []string) error {
+	reg, err := selectorgen.Scan(root)
+	if err != nil {
+		return err
+	}
+	src, err := selectorgen.Generate(reg, pkg)
+	if err != nil {
+		return err
+	}
+	if !check {
+		if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(out, src, 0o644)
+	}
+
+	current, err := os.ReadFile(out)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, src) {
+		return fmt.Errorf("%s is out of date with the templates; run go generate ./helpers/selectors", out)
+	}
+	problems, err := selectorgen.Check(reg, dirs...)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintln(os.Stderr, p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d selectors name IDs or classes that no longer exist", len(problems))
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package fixtures generates small, deterministic Bible data trees for the
+// regression tests. The output mirrors data/example: a bibles.json index and
+// one bibles_auxiliary/{id}.json file per Bible, conforming to the schemas in
+// static/schemas, and the bibles_content manifests the site builds pages from.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/quality"
+)
+
+// Bible is one entry of the bibles.json "bibles" array.
+type Bible struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description,omitempty"`
+	Abbrev        string   `json:"abbrev"`
+	Language      string   `json:"language,omitempty"`
+	License       string   `json:"license,omitempty"`
+	LicenseText   string   `json:"licenseText,omitempty"`
+	Versification string   `json:"versification,omitempty"`
+	Features      []string `json:"features"`
+	Tags          []string `json:"tags"`
+	Weight        int      `json:"weight"`
+}
+
+// Meta is the bibles.json "meta" object.
+type Meta struct {
+	Granularity string `json:"granularity"`
+	Generated   string `json:"generated"`
+	Version     string `json:"version"`
+}
+
+// Index is the top-level bibles.json document.
+type Index struct {
+	Bibles []Bible `json:"bibles"`
+	Meta   Meta    `json:"meta"`
+}
+
+// Verse is a single numbered verse.
+type Verse struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
+}
+
+// Chapter is a numbered list of verses.
+type Chapter struct {
+	Number int     `json:"number"`
+	Verses []Verse `json:"verses"`
+}
+
+// Book is a book of a Bible identified by its OSIS ID.
+type Book struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Testament string    `json:"testament"`
+	Chapters  []Chapter `json:"chapters"`
+}
+
+// ExcludedBook records a book the source module declares but has no content for.
+type ExcludedBook struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Testament string `json:"testament"`
+	Reason    string `json:"reason"`
+}
+
+// Auxiliary is a bibles_auxiliary/{id}.json document.
+type Auxiliary struct {
+	Content       string         `json:"content,omitempty"`
+	Books         []Book         `json:"books"`
+	ExcludedBooks []ExcludedBook `json:"excludedBooks,omitempty"`
+}
+
+// Set is a complete data tree: the index plus the auxiliary file for every Bible.
+type Set struct {
+	Index     Index
+	Auxiliary map[string]Auxiliary
+	// Roles names the Bibles the regression tests use, so they run against
+	// the fixtures and data/example alike.
+	Roles Roles
+}
+
+// Roles are the IDs of the Bibles in a set that serve each test purpose.
+// A role the set has no Bible for is empty.
+type Roles struct {
+	// Strongs carries Strong's markup on its words.
+	Strongs string
+	// Plain is untagged English.
+	Plain string
+	// Catholic follows the catholic versification and has deuterocanonical books.
+	Catholic string
+	// Hebrew is the right-to-left Hebrew Old Testament.
+	Hebrew string
+	// Greek follows the orthodox versification.
+	Greek string
+}
+
+// resolveRoles fills in Roles from the Bibles the index lists with text,
+// taking the fixture Bible for each role and otherwise its data/example
+// counterparts in order. The site builds no pages for a Bible without an
+// auxiliary file, as a checkout before make vendor-restore has.
+func (s *Set) resolveRoles() {
+	pick := func(ids ...string) string {
+		for _, id := range ids {
+			_, listed := s.Bible(id)
+			if _, ok := s.Auxiliary[id]; listed && ok {
+				return id
+			}
+		}
+		return ""
+	}
+	s.Roles = Roles{
+		Strongs:  pick(StrongsBible, "kjva", "asv"),
+		Plain:    pick(PlainBible, "asv", "web", "tyndale"),
+		Catholic: pick(CatholicBible, "drc", "vulgate"),
+		Hebrew:   pick(HebrewBible, "osmhb"),
+		Greek:    pick(GreekBible, "lxx"),
+	}
+}
+
+// Write writes the set to dir as bibles.json and bibles_auxiliary/{id}.json,
+// then classifies it into bibles_content/{id}.json as cmd/content-manifest
+// does. Output is byte-for-byte identical for identical sets.
+func (s *Set) Write(dir string) error {
+	auxDir := filepath.Join(dir, "bibles_auxiliary")
+	if err := os.MkdirAll(auxDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", auxDir, err)
+	}
+	if err := writeJSON(filepath.Join(dir, "bibles.json"), s.Index); err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(s.Auxiliary))
+	for id := range s.Auxiliary {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := writeJSON(filepath.Join(auxDir, id+".json"), s.Auxiliary[id]); err != nil {
+			return err
+		}
+	}
+	_, err := quality.WriteManifests(dir)
+	return err
+}
+
+// WriteTemp writes the set to a new temporary directory and returns its path.
+func (s *Set) WriteTemp() (string, error) {
+	dir, err := os.MkdirTemp("", "michael-fixtures-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := s.Write(dir); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// Load reads a data tree written by Write, or data/example, back into a set.
+// Bibles without an auxiliary file are kept in the index and left out of
+// Auxiliary, as Hugo sees them.
+func Load(dir string) (*Set, error) {
+	s := &Set{Auxiliary: map[string]Auxiliary{}}
+	if err := decodeFile(filepath.Join(dir, "bibles.json"), &s.Index); err != nil {
+		return nil, err
+	}
+	for _, b := range s.Index.Bibles {
+		var aux Auxiliary
+		err := decodeFile(filepath.Join(dir, "bibles_auxiliary", b.ID+".json"), &aux)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.Auxiliary[b.ID] = aux
+	}
+	s.resolveRoles()
+	return s, nil
+}
+
+// Bible returns the index entry with the given ID.
+func (s *Set) Bible(id string) (Bible, bool) {
+	for _, b := range s.Index.Bibles {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Bible{}, false
+}
+
+// decodeFile decodes the JSON file at path into v.
+func decodeFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeJSON encodes v with two-space indentation and unescaped markup, matching data/example.
+func writeJSON(path string, v interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
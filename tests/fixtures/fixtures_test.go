@@ -0,0 +1,296 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/quality"
+)
+
+// TestDefaultIsDeterministic verifies two writes of the default set are byte-identical.
+func TestDefaultIsDeterministic(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	if err := Default().Write(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := Default().Write(second); err != nil {
+		t.Fatal(err)
+	}
+
+	err := filepath.Walk(first, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(first, path)
+		a, _ := os.ReadFile(path)
+		b, err := os.ReadFile(filepath.Join(second, rel))
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s differs between runs", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestLoadRoundTrip verifies Load reads back what Write wrote, and keeps
+// Bibles without an auxiliary file out of Auxiliary.
+func TestLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	set := Default()
+	if err := set.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+	missing := set.Index.Bibles[0].ID
+	if err := os.Remove(filepath.Join(dir, "bibles_auxiliary", missing+".json")); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Index, set.Index) {
+		t.Error("loaded index differs from the written one")
+	}
+	if _, ok := loaded.Auxiliary[missing]; ok {
+		t.Errorf("%s has no auxiliary file but was loaded", missing)
+	}
+	for id, aux := range set.Auxiliary {
+		if id != missing && !reflect.DeepEqual(loaded.Auxiliary[id], aux) {
+			t.Errorf("loaded %s differs from the written one", id)
+		}
+	}
+
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("expected an error loading a directory without bibles.json")
+	}
+}
+
+// TestRoles verifies every role resolves to a fixture Bible in the default
+// set, and that data/example roles only name Bibles with text.
+func TestRoles(t *testing.T) {
+	want := Roles{Strongs: StrongsBible, Plain: PlainBible, Catholic: CatholicBible, Hebrew: HebrewBible, Greek: GreekBible}
+	if got := Default().Roles; got != want {
+		t.Errorf("Default roles = %+v, want %+v", got, want)
+	}
+
+	partial := &Set{
+		Index:     Index{Bibles: []Bible{{ID: "asv"}, {ID: "kjva"}, {ID: "tyndale"}}},
+		Auxiliary: map[string]Auxiliary{"tyndale": {}},
+	}
+	partial.resolveRoles()
+	if want := (Roles{Plain: "tyndale"}); partial.Roles != want {
+		t.Errorf("roles without asv and kjva text = %+v, want %+v", partial.Roles, want)
+	}
+
+	example, err := Load(filepath.Join("..", "..", "data", "example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if example.Roles.Plain == "" {
+		t.Error("data/example has no plain Bible")
+	}
+	for _, id := range []string{example.Roles.Strongs, example.Roles.Plain, example.Roles.Catholic, example.Roles.Hebrew, example.Roles.Greek} {
+		if _, ok := example.Auxiliary[id]; id != "" && !ok {
+			t.Errorf("data/example role %s has no auxiliary file", id)
+		}
+	}
+}
+
+// TestDefaultManifests verifies Write classifies the planted placeholder
+// chapter and book as placeholders, the short book as real, and every other
+// book, Hebrew and Greek included, as real.
+func TestDefaultManifests(t *testing.T) {
+	dir := t.TempDir()
+	set := Default()
+	if err := set.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, b := range set.Index.Bibles {
+		m, err := quality.LoadManifest(dir, b.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, book := range m.Books {
+			want := quality.Real
+			if b.ID == PlainBible && book.ID == "Obad" {
+				want = quality.Placeholder
+			}
+			if book.Status != want {
+				t.Errorf("%s %s is %s, want %s", b.ID, book.ID, book.Status, want)
+			}
+			for _, ch := range book.Chapters {
+				placeholder := b.ID == PlainBible && (book.ID == "Obad" || book.ID == "Matt" && ch.Number == 28)
+				if ch.Status.HasText() == placeholder {
+					t.Errorf("%s %s %d is %s", b.ID, book.ID, ch.Number, ch.Status)
+				}
+			}
+		}
+	}
+}
+
+// TestDefaultMatchesSchemas validates the written tree against static/schemas.
+func TestDefaultMatchesSchemas(t *testing.T) {
+	dir := t.TempDir()
+	set := Default()
+	if err := set.Write(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	validateFile(t, "bibles.schema.json", filepath.Join(dir, "bibles.json"))
+	for _, b := range set.Index.Bibles {
+		validateFile(t, "bibles-auxiliary.schema.json", filepath.Join(dir, "bibles_auxiliary", b.ID+".json"))
+	}
+}
+
+// TestDefaultCoversEdgeCases verifies every edge case the fixtures promise is present.
+func TestDefaultCoversEdgeCases(t *testing.T) {
+	set := Default()
+	found := map[string]bool{}
+	schemes := map[string]bool{}
+
+	for _, b := range set.Index.Bibles {
+		schemes[b.Versification] = true
+		if b.Language == "he" {
+			found["rtl"] = true
+		}
+		aux := set.Auxiliary[b.ID]
+		if len(aux.ExcludedBooks) > 0 {
+			found["excludedBooks"] = true
+		}
+		for _, book := range aux.Books {
+			if book.Testament == "AP" {
+				found["ap"] = true
+			}
+			for _, ch := range book.Chapters {
+				for _, v := range ch.Verses {
+					switch {
+					case v.Text == "":
+						found["empty"] = true
+					case strings.Contains(v.Text, `lemma="strong:`):
+						found["strongs"] = true
+					case strings.HasSuffix(v.Text, ":"):
+						found["placeholder"] = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, want := range []string{"rtl", "excludedBooks", "ap", "empty", "strongs", "placeholder"} {
+		if !found[want] {
+			t.Errorf("fixture set has no %s case", want)
+		}
+	}
+	if len(schemes) < 3 {
+		t.Errorf("expected at least 3 versification schemes, got %d", len(schemes))
+	}
+}
+
+// validateFile checks a JSON document against a schema from static/schemas.
+func validateFile(t *testing.T, schemaName, path string) {
+	t.Helper()
+	var schema, doc map[string]interface{}
+	readJSON(t, filepath.Join("..", "..", "static", "schemas", schemaName), &schema)
+	readJSON(t, path, &doc)
+	for _, msg := range validate(schema, doc, "") {
+		t.Errorf("%s: %s", filepath.Base(path), msg)
+	}
+}
+
+// readJSON decodes a file into v, failing the test on error.
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+}
+
+// validate applies the draft-07 keywords used by static/schemas: type,
+// required, properties, items, enum, pattern and minimum.
+func validate(schema map[string]interface{}, value interface{}, path string) []string {
+	var errs []string
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	switch want, _ := schema["type"].(string); want {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object")
+			return errs
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, r := range required {
+			if _, ok := obj[r.(string)]; !ok {
+				fail("missing required %q", r)
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for name, sub := range props {
+			if v, ok := obj[name]; ok {
+				errs = append(errs, validate(sub.(map[string]interface{}), v, path+"/"+name)...)
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			fail("expected array")
+			return errs
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, v := range arr {
+				errs = append(errs, validate(items, v, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("expected string")
+			return errs
+		}
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			fail("%q does not match %s", s, pattern)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			fail("expected integer")
+			return errs
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			fail("%v is below minimum %v", n, min)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		matched := false
+		for _, e := range enum {
+			if e == value {
+				matched = true
+			}
+		}
+		if !matched {
+			fail("%v is not one of %v", value, enum)
+		}
+	}
+	return errs
+}
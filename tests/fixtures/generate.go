@@ -0,0 +1,325 @@
+package fixtures
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// Fixture Bible IDs. Each Bible exists to exercise particular edge cases.
+const (
+	// StrongsBible is English with OSIS <w lemma="strong:..."> markup on every word.
+	StrongsBible = "fx-strongs"
+	// PlainBible is untagged English with empty and placeholder verses, a
+	// placeholder-only chapter and book, and a book of short real verses.
+	PlainBible = "fx-plain"
+	// CatholicBible includes AP-testament books and declares excludedBooks.
+	CatholicBible = "fx-catholic"
+	// HebrewBible is right-to-left Hebrew with Strong's tagging and no New Testament.
+	HebrewBible = "fx-hebrew"
+	// GreekBible is Greek with morphology, an AP book and the orthodox versification.
+	GreekBible = "fx-greek"
+)
+
+// Generated is the fixed timestamp written to meta.generated.
+const Generated = "2026-01-01T00:00:00Z"
+
+// bookSpec describes a book's identity and chapter count.
+type bookSpec struct {
+	id        string
+	name      string
+	testament string
+	chapters  int
+}
+
+var (
+	genesis   = bookSpec{"Gen", "Genesis", "OT", 50}
+	psalms    = bookSpec{"Ps", "Psalms", "OT", 150}
+	obadiah   = bookSpec{"Obad", "Obadiah", "OT", 1}
+	tobit     = bookSpec{"Tob", "Tobit", "AP", 14}
+	wisdom    = bookSpec{"Wis", "Wisdom", "AP", 19}
+	matthew   = bookSpec{"Matt", "Matthew", "NT", 28}
+	john      = bookSpec{"John", "John", "NT", 21}
+	philemon  = bookSpec{"Phlm", "Philemon", "NT", 1}
+	prayerMan = bookSpec{"PrMan", "Prayer of Manasseh", "AP", 1}
+	esdras1   = bookSpec{"1Esd", "1 Esdras", "AP", 9}
+)
+
+// fixedVerseCounts pins the chapters tests address directly; every other
+// chapter gets a small hash-derived verse count.
+var fixedVerseCounts = map[string]int{
+	"Gen.1":  31,
+	"Ps.23":  6,
+	"Matt.1": 25,
+	"John.1": 51,
+	"John.3": 36,
+}
+
+// leningradSuperscriptions are Psalms whose title is verse 1 in the Hebrew
+// numbering, shifting every following verse by one.
+var leningradSuperscriptions = map[int]bool{3: true, 4: true, 5: true, 6: true, 7: true}
+
+// textKind selects how verse text is generated.
+type textKind int
+
+const (
+	textPlain textKind = iota
+	textStrongs
+	textHebrew
+	textGreek
+)
+
+// bibleSpec is the recipe for one fixture Bible.
+type bibleSpec struct {
+	bible    Bible
+	books    []bookSpec
+	excluded []bookSpec
+	kind     textKind
+	names    map[string]string
+	// literal replaces individual verses, keyed by "Book.chapter.verse"
+	literal map[string]string
+	// placeholderChapters and placeholderBooks contain only "Book c:v:" verses
+	placeholderChapters map[string]bool
+	placeholderBooks    map[string]bool
+	// shortBooks contain real verses that are all under 50 characters
+	shortBooks map[string]bool
+}
+
+// specs returns the recipes for every fixture Bible in weight order.
+func specs() []bibleSpec {
+	return []bibleSpec{
+		{
+			bible: Bible{
+				ID: StrongsBible, Title: "Fixture Strong's English", Abbrev: "FXS",
+				Language: "en", Versification: "protestant",
+				Features: []string{"Strong's Numbers"}, Tags: []string{"en", "Strong's Numbers"},
+			},
+			books: []bookSpec{genesis, psalms, matthew, john},
+			kind:  textStrongs,
+		},
+		{
+			bible: Bible{
+				ID: PlainBible, Title: "Fixture Plain English", Abbrev: "FXP",
+				Language: "en", Versification: "nrsv",
+				Features: []string{}, Tags: []string{"en"},
+			},
+			books: []bookSpec{genesis, psalms, obadiah, matthew, john, philemon},
+			kind:  textPlain,
+			literal: map[string]string{
+				"Gen.2.3": "",
+				"Gen.2.4": "Gen 2:4:",
+			},
+			placeholderChapters: map[string]bool{"Matt.28": true},
+			placeholderBooks:    map[string]bool{"Obad": true},
+			shortBooks:          map[string]bool{"Phlm": true},
+		},
+		{
+			bible: Bible{
+				ID: CatholicBible, Title: "Fixture Catholic English", Abbrev: "FXC",
+				Language: "en", Versification: "catholic",
+				Features: []string{"Footnotes"}, Tags: []string{"en", "Footnotes"},
+			},
+			books:    []bookSpec{genesis, tobit, wisdom, psalms, matthew, john},
+			excluded: []bookSpec{prayerMan, esdras1},
+			kind:     textPlain,
+		},
+		{
+			bible: Bible{
+				ID: HebrewBible, Title: "Fixture Hebrew", Abbrev: "FXH",
+				Language: "he", Versification: "leningrad",
+				Features: []string{"Strong's Numbers"}, Tags: []string{"he", "Strong's Numbers"},
+			},
+			books:    []bookSpec{genesis, psalms},
+			excluded: []bookSpec{matthew, john},
+			kind:     textHebrew,
+			names:    map[string]string{"Gen": "בראשית", "Ps": "תהלים"},
+		},
+		{
+			bible: Bible{
+				ID: GreekBible, Title: "Fixture Greek", Abbrev: "FXG",
+				Language: "grc", Versification: "orthodox",
+				Features: []string{"Strong's Numbers", "Morphology"}, Tags: []string{"grc", "Strong's Numbers", "Morphology"},
+			},
+			books: []bookSpec{genesis, psalms, tobit, matthew, john},
+			kind:  textGreek,
+		},
+	}
+}
+
+// Default returns the standard fixture set. Every call returns an identical set.
+func Default() *Set {
+	set := &Set{
+		Index: Index{
+			Meta: Meta{Granularity: "chapter", Generated: Generated, Version: "2.0.0"},
+		},
+		Auxiliary: make(map[string]Auxiliary),
+	}
+	for i, spec := range specs() {
+		bible := spec.bible
+		bible.Description = fmt.Sprintf("Synthetic %s translation generated for regression tests.", bible.Title)
+		bible.License = "CC-PDDC"
+		bible.LicenseText = "Generated test data. Public Domain."
+		bible.Weight = i + 1
+		set.Index.Bibles = append(set.Index.Bibles, bible)
+		set.Auxiliary[bible.ID] = spec.auxiliary()
+	}
+	set.resolveRoles()
+	return set
+}
+
+// auxiliary builds the bibles_auxiliary document for the spec.
+func (spec bibleSpec) auxiliary() Auxiliary {
+	aux := Auxiliary{Content: fmt.Sprintf("The %s translation.", spec.bible.Title)}
+	for _, b := range spec.books {
+		book := Book{ID: b.id, Name: spec.bookName(b), Testament: b.testament}
+		for c := 1; c <= b.chapters; c++ {
+			book.Chapters = append(book.Chapters, spec.chapter(b, c))
+		}
+		aux.Books = append(aux.Books, book)
+	}
+	for _, b := range spec.excluded {
+		aux.ExcludedBooks = append(aux.ExcludedBooks, ExcludedBook{
+			ID: b.id, Name: b.name, Testament: b.testament, Reason: "no content in source module",
+		})
+	}
+	return aux
+}
+
+// bookName returns the localized book name if the spec has one.
+func (spec bibleSpec) bookName(b bookSpec) string {
+	if name, ok := spec.names[b.id]; ok {
+		return name
+	}
+	return b.name
+}
+
+// chapter generates one chapter, applying the spec's edge cases.
+func (spec bibleSpec) chapter(b bookSpec, number int) Chapter {
+	count, ok := fixedVerseCounts[fmt.Sprintf("%s.%d", b.id, number)]
+	if !ok {
+		s := newStream(b.id, number)
+		count = 4 + s.intn(6)
+	}
+	if spec.bible.Versification == "leningrad" && b.id == "Ps" && leningradSuperscriptions[number] {
+		count++
+	}
+
+	placeholder := spec.placeholderBooks[b.id] || spec.placeholderChapters[fmt.Sprintf("%s.%d", b.id, number)]
+	ch := Chapter{Number: number}
+	for v := 1; v <= count; v++ {
+		var text string
+		if literal, ok := spec.literal[fmt.Sprintf("%s.%d.%d", b.id, number, v)]; ok {
+			text = literal
+		} else if placeholder {
+			text = fmt.Sprintf("%s %d:%d:", b.id, number, v)
+		} else {
+			text = spec.verseText(b, number, v)
+		}
+		ch.Verses = append(ch.Verses, Verse{Number: v, Text: text})
+	}
+	return ch
+}
+
+// verseText generates deterministic verse text in the spec's style.
+func (spec bibleSpec) verseText(b bookSpec, chapter, verse int) string {
+	s := newStream(spec.bible.ID, b.id, chapter, verse)
+	if spec.shortBooks[b.id] {
+		return shortVerses[s.intn(len(shortVerses))]
+	}
+
+	switch spec.kind {
+	case textStrongs:
+		prefix, limit := "H", 8674
+		if b.testament == "NT" {
+			prefix, limit = "G", 5624
+		}
+		return sentence(&s, englishWords, 60, func(w string) string {
+			return fmt.Sprintf(`<w lemma="strong:%s%04d">%s</w>`, prefix, 1+s.intn(limit), w)
+		}) + "."
+	case textHebrew:
+		return sentence(&s, hebrewWords, 40, func(w string) string {
+			return fmt.Sprintf(`<w lemma="strong:H%04d">%s</w>`, 1+s.intn(8674), w)
+		}) + `<seg type="x-sof-pasuq">׃</seg>`
+	case textGreek:
+		return sentence(&s, greekWords, 60, func(w string) string {
+			return fmt.Sprintf(`<w lemma="strong:G%04d" morph="robinson:%s">%s</w>`,
+				1+s.intn(5624), greekMorph[s.intn(len(greekMorph))], w)
+		}) + "."
+	default:
+		return sentence(&s, englishWords, 60, nil) + "."
+	}
+}
+
+// sentence joins random words until the plain text reaches minLen characters.
+// wrap, if set, decorates each word after it is chosen.
+func sentence(s *stream, words []string, minLen int, wrap func(string) string) string {
+	var plain, out []string
+	for length := 0; length < minLen; {
+		w := words[s.intn(len(words))]
+		if len(plain) == 0 && w[0] >= 'a' && w[0] <= 'z' {
+			w = strings.ToUpper(w[:1]) + w[1:]
+		}
+		plain = append(plain, w)
+		length += len(w) + 1
+		if wrap != nil {
+			w = wrap(w)
+		}
+		out = append(out, w)
+	}
+	return strings.Join(out, " ")
+}
+
+// stream is a xorshift generator seeded from a hash, so output never depends
+// on math/rand or map iteration order.
+type stream uint64
+
+// newStream seeds a stream from the given parts.
+func newStream(parts ...interface{}) stream {
+	h := fnv.New64a()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%v|", p)
+	}
+	if v := h.Sum64(); v != 0 {
+		return stream(v)
+	}
+	return 1
+}
+
+// intn returns a value in [0, n).
+func (s *stream) intn(n int) int {
+	x := uint64(*s)
+	x ^= x << 13
+	x ^= x >> 7
+	x ^= x << 17
+	*s = stream(x)
+	return int(x % uint64(n))
+}
+
+var englishWords = []string{
+	"and", "the", "light", "water", "earth", "heaven", "spirit", "word",
+	"people", "house", "mountain", "river", "bread", "shepherd", "city",
+	"covenant", "seed", "morning", "evening", "voice", "hand", "field",
+	"blessed", "gathered", "spoke", "went", "came", "gave", "saw", "called",
+	"unto", "upon", "before", "after", "among", "with", "from", "over",
+}
+
+var hebrewWords = []string{
+	"בְּרֵאשִׁ֖ית", "בָּרָ֣א", "אֱלֹהִ֑ים", "אֵ֥ת", "הַשָּׁמַ֖יִם", "וְאֵ֥ת", "הָאָֽרֶץ",
+	"וְהָאָ֗רֶץ", "הָיְתָ֥ה", "ת֙הוּ֙", "וָבֹ֔הוּ", "וְח֖שֶׁךְ", "עַל", "פְּנֵ֣י",
+	"תְה֑וֹם", "וְר֣וּחַ", "מְרַחֶ֖פֶת", "הַמָּֽיִם", "יְהוָ֥ה", "רֹ֝עִ֗י",
+}
+
+var greekWords = []string{
+	"ἐν", "ἀρχῇ", "ἐποίησεν", "ὁ", "θεὸς", "τὸν", "οὐρανὸν", "καὶ", "τὴν",
+	"γῆν", "ἦν", "ὁ", "λόγος", "πρὸς", "φῶς", "ὕδατος", "πνεῦμα", "ἡμέρα",
+	"ποιμήν", "ἄνθρωπος",
+}
+
+var greekMorph = []string{"N-NSM", "N-ASF", "V-AAI-3S", "T-NSM", "CONJ", "PREP"}
+
+var shortVerses = []string{
+	"Grace be with you.",
+	"Amen.",
+	"Greet the brethren.",
+	"Peace be to you all.",
+}
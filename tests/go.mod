@@ -2,11 +2,14 @@ module michael-tests
 
 go 1.26.0
 
-require github.com/JuniperBible/magellan v0.0.0
+require (
+	github.com/JuniperBible/Public.Website.MichaelCore v0.0.0
+	github.com/JuniperBible/magellan v0.0.0
+	github.com/chromedp/cdproto v0.0.0-20240202021202-6d0b6a386732
+	github.com/chromedp/chromedp v0.9.5
+)
 
 require (
-	github.com/chromedp/cdproto v0.0.0-20240202021202-6d0b6a386732 // indirect
-	github.com/chromedp/chromedp v0.9.5 // indirect
 	github.com/chromedp/sysutil v1.0.0 // indirect
 	github.com/gobwas/httphead v0.1.0 // indirect
 	github.com/gobwas/pool v0.2.1 // indirect
@@ -17,3 +20,5 @@ require (
 )
 
 replace github.com/JuniperBible/magellan => ../tools/magellan
+
+replace github.com/JuniperBible/Public.Website.MichaelCore => ../
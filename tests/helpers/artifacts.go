@@ -0,0 +1,339 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Failure Artifacts
+// =============================================================================
+
+// artifactCaptureTimeout bounds each capture, so a hung page cannot stall
+// the test's cleanup.
+const artifactCaptureTimeout = 10 * time.Second
+
+// failureRecorder collects a browser's console output and network traffic
+// during a test, for writing out if the test fails.
+type failureRecorder struct {
+	mu       sync.Mutex
+	console  []string
+	requests map[network.RequestID]*harEntry
+	order    []network.RequestID
+}
+
+// browserSeq numbers the browsers of each test, so a test that opens several
+// gets a set of artifacts for each.
+var browserSeq sync.Map
+
+// RecordFailureArtifacts records b's console and network activity for the
+// rest of the test. If the test fails, it writes to ArtifactDir:
+//
+//   - failure.png, a full-page screenshot
+//   - failure.html, the serialized DOM
+//   - console.log, console messages, JavaScript exceptions and browser log
+//     entries such as CSP violations
+//   - network.har, the page's requests in HAR 1.2 format
+//
+// and logs the directory. Browsers from NewDeviceBrowser are recorded
+// already; call this for browsers created some other way.
+func RecordFailureArtifacts(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	seq, loaded := browserSeq.LoadOrStore(t.Name(), new(atomic.Int32))
+	if !loaded {
+		t.Cleanup(func() { browserSeq.Delete(t.Name()) })
+	}
+	prefix := ""
+	if n := seq.(*atomic.Int32).Add(1); n > 1 {
+		prefix = fmt.Sprintf("browser-%d-", n)
+	}
+
+	ctx, cancel := context.WithCancel(b.Context())
+	rec := &failureRecorder{requests: map[network.RequestID]*harEntry{}}
+	chromedp.ListenTarget(ctx, rec.handle)
+	if err := RunCDP(b, runtime.Enable(), log.Enable(), network.Enable()); err != nil {
+		t.Logf("Failed to enable failure recording: %v", err)
+	}
+
+	t.Cleanup(func() {
+		defer cancel()
+		if t.Failed() {
+			rec.write(t, b, prefix)
+		}
+	})
+}
+
+// handle records one CDP event.
+func (r *failureRecorder) handle(ev interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch e := ev.(type) {
+	case *runtime.EventConsoleAPICalled:
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = remoteObjectText(arg)
+		}
+		r.console = append(r.console, fmt.Sprintf("%s [console.%s] %s",
+			logTime(e.Timestamp), e.Type, strings.Join(args, " ")))
+	case *runtime.EventExceptionThrown:
+		d := e.ExceptionDetails
+		text := d.Text
+		if d.Exception != nil && d.Exception.Description != "" {
+			text = d.Exception.Description
+		}
+		r.console = append(r.console, fmt.Sprintf("%s [exception] %s (%s:%d:%d)",
+			logTime(e.Timestamp), text, d.URL, d.LineNumber+1, d.ColumnNumber+1))
+	case *log.EventEntryAdded:
+		entry := e.Entry
+		where := ""
+		if entry.URL != "" {
+			where = fmt.Sprintf(" (%s:%d)", entry.URL, entry.LineNumber+1)
+		}
+		r.console = append(r.console, fmt.Sprintf("%s [%s.%s] %s%s",
+			logTime(entry.Timestamp), entry.Source, entry.Level, entry.Text, where))
+	case *network.EventRequestWillBeSent:
+		entry := &harEntry{
+			Request: harRequest{
+				Method:      e.Request.Method,
+				URL:         e.Request.URL,
+				HTTPVersion: "HTTP/1.1",
+				Headers:     harHeaders(e.Request.Headers),
+				QueryString: []harHeader{},
+				Cookies:     []harHeader{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Response: harResponse{
+				Headers:     []harHeader{},
+				Cookies:     []harHeader{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+			Timings: harTimings{Send: 0, Wait: -1, Receive: 0},
+		}
+		if e.WallTime != nil {
+			entry.StartedDateTime = e.WallTime.Time().Format(time.RFC3339Nano)
+		}
+		if e.Timestamp != nil {
+			entry.started = e.Timestamp.Time()
+		}
+		// A redirect reuses the request ID, so the entry ends up describing
+		// the last hop
+		if _, seen := r.requests[e.RequestID]; !seen {
+			r.order = append(r.order, e.RequestID)
+		}
+		r.requests[e.RequestID] = entry
+	case *network.EventResponseReceived:
+		entry, ok := r.requests[e.RequestID]
+		if !ok {
+			return
+		}
+		res := e.Response
+		entry.Response.Status = res.Status
+		entry.Response.StatusText = res.StatusText
+		entry.Response.HTTPVersion = strings.ToUpper(res.Protocol)
+		entry.Response.Headers = harHeaders(res.Headers)
+		entry.Response.Content.MimeType = res.MimeType
+		entry.FromServiceWorker = res.FromServiceWorker
+		entry.FromDiskCache = res.FromDiskCache
+		if e.Timestamp != nil && !entry.started.IsZero() {
+			entry.Timings.Wait = msSince(entry.started, e.Timestamp.Time())
+		}
+	case *network.EventLoadingFinished:
+		entry, ok := r.requests[e.RequestID]
+		if !ok {
+			return
+		}
+		entry.Response.BodySize = int64(e.EncodedDataLength)
+		entry.Response.Content.Size = int64(e.EncodedDataLength)
+		if e.Timestamp != nil && !entry.started.IsZero() {
+			entry.Time = msSince(entry.started, e.Timestamp.Time())
+			entry.Timings.Receive = max(0, entry.Time-max(0, entry.Timings.Wait))
+		}
+	case *network.EventLoadingFailed:
+		entry, ok := r.requests[e.RequestID]
+		if !ok {
+			return
+		}
+		entry.Error = e.ErrorText
+		if e.Canceled {
+			entry.Error += " (canceled)"
+		}
+		if e.Timestamp != nil && !entry.started.IsZero() {
+			entry.Time = msSince(entry.started, e.Timestamp.Time())
+		}
+	}
+}
+
+// write saves the artifacts of a failed test.
+func (r *failureRecorder) write(t *testing.T, b *e2e.Browser, prefix string) {
+	t.Helper()
+	dir := ArtifactDir(t)
+
+	ctx, cancel := context.WithTimeout(b.Context(), artifactCaptureTimeout)
+	defer cancel()
+	var screenshot []byte
+	if err := chromedp.Run(ctx, chromedp.FullScreenshot(&screenshot, 100)); err != nil {
+		t.Logf("Failed to capture failure screenshot: %v", err)
+	} else {
+		writeArtifact(t, filepath.Join(dir, prefix+"failure.png"), screenshot)
+	}
+
+	ctx, cancel = context.WithTimeout(b.Context(), artifactCaptureTimeout)
+	defer cancel()
+	var dom string
+	if err := chromedp.Run(ctx, chromedp.OuterHTML("html", &dom, chromedp.ByQuery)); err != nil {
+		t.Logf("Failed to capture failure DOM: %v", err)
+	} else {
+		writeArtifact(t, filepath.Join(dir, prefix+"failure.html"), []byte("<!DOCTYPE html>\n"+dom))
+	}
+
+	r.mu.Lock()
+	console := strings.Join(r.console, "\n")
+	har := harLog{Log: harBody{
+		Version: "1.2",
+		Creator: harCreator{Name: "michael-tests", Version: "1"},
+		Entries: make([]harEntry, 0, len(r.order)),
+	}}
+	for _, id := range r.order {
+		har.Log.Entries = append(har.Log.Entries, *r.requests[id])
+	}
+	r.mu.Unlock()
+
+	if console != "" {
+		console += "\n"
+	}
+	writeArtifact(t, filepath.Join(dir, prefix+"console.log"), []byte(console))
+	if data, err := json.MarshalIndent(har, "", "  "); err == nil {
+		writeArtifact(t, filepath.Join(dir, prefix+"network.har"), data)
+	}
+	t.Logf("Failure artifacts in %s", dir)
+}
+
+// remoteObjectText renders a console argument the way DevTools prints it.
+func remoteObjectText(o *runtime.RemoteObject) string {
+	if len(o.Value) > 0 {
+		var s string
+		if err := json.Unmarshal(o.Value, &s); err == nil {
+			return s
+		}
+		return string(o.Value)
+	}
+	if o.Description != "" {
+		return o.Description
+	}
+	return string(o.Type)
+}
+
+// logTime formats a CDP timestamp for console.log, or "-" when missing.
+func logTime(ts *runtime.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	return ts.Time().Format("15:04:05.000")
+}
+
+// msSince returns the milliseconds from start to end.
+func msSince(start, end time.Time) float64 {
+	return float64(end.Sub(start)) / float64(time.Millisecond)
+}
+
+// =============================================================================
+// HAR
+// =============================================================================
+
+// The HAR types cover the fields DevTools and HAR viewers need to list
+// requests; bodies and cookies are not recorded.
+
+type harLog struct {
+	Log harBody `json:"log"`
+}
+
+type harBody struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// Custom fields are prefixed with an underscore, per the HAR spec.
+	FromServiceWorker bool   `json:"_fromServiceWorker,omitempty"`
+	FromDiskCache     bool   `json:"_fromDiskCache,omitempty"`
+	Error             string `json:"_error,omitempty"`
+
+	started time.Time
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	QueryString []harHeader `json:"queryString"`
+	Cookies     []harHeader `json:"cookies"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int64       `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	Cookies     []harHeader `json:"cookies"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harHeaders converts CDP headers to sorted HAR name/value pairs.
+func harHeaders(headers network.Headers) []harHeader {
+	out := make([]harHeader, 0, len(headers))
+	for name, value := range headers {
+		out = append(out, harHeader{Name: name, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
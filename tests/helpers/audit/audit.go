@@ -0,0 +1,260 @@
+// Package audit runs a WCAG 2.2 AA rule set against a page loaded in a test
+// browser. Control names come from Chrome's accessibility tree over CDP; the
+// remaining rules read computed styles and the DOM in the page. Violations
+// are hard test failures, advisories are logged, and every run writes a JSON
+// report.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/chromedp"
+
+	"michael-tests/helpers"
+)
+
+// Rule identifiers used in reports and in Options.Skip.
+const (
+	RuleContrast    = "color-contrast"
+	RuleControlName = "control-name"
+	RuleLandmarks   = "landmarks"
+	RuleTargetSize  = "target-size"
+	RuleDuplicateID = "duplicate-id"
+	RuleARIARefs    = "aria-refs"
+	RuleARIAAttr    = "aria-valid-attr"
+)
+
+// Rules lists every rule in the order they are reported.
+var Rules = []string{
+	RuleContrast, RuleControlName, RuleLandmarks, RuleTargetSize,
+	RuleDuplicateID, RuleARIARefs, RuleARIAAttr,
+}
+
+// namedRoles are the interactive roles that must expose an accessible name.
+var namedRoles = map[string]bool{
+	"button": true, "checkbox": true, "combobox": true, "link": true,
+	"listbox": true, "menuitem": true, "radio": true, "searchbox": true,
+	"slider": true, "spinbutton": true, "switch": true, "tab": true,
+	"textbox": true,
+}
+
+// Violation is a single rule failure on one element.
+type Violation struct {
+	Rule     string `json:"rule"`
+	Selector string `json:"selector"`
+	Message  string `json:"message"`
+}
+
+// Report is the machine-readable result of auditing one page.
+type Report struct {
+	Page       string      `json:"page"`
+	URL        string      `json:"url"`
+	Standard   string      `json:"standard"`
+	Rules      []string    `json:"rules"`
+	Violations []Violation `json:"violations"`
+	// Incomplete lists elements a rule could not decide, such as text over a
+	// background image. They are reported but do not fail the test.
+	Incomplete []Violation `json:"incomplete"`
+	// Advisory lists targets that meet the AA minimum but are smaller than
+	// the AAA size of helpers.MinTouchTarget. They do not fail the test.
+	Advisory []Violation `json:"advisory"`
+}
+
+// Options adjusts the audit for one page.
+type Options struct {
+	// Skip disables rules by identifier.
+	Skip []string
+	// MinTargetSize is the minimum width and height of a pointer target in CSS
+	// pixels. Defaults to helpers.MinPointerTarget.
+	MinTargetSize float64
+}
+
+// domResult is the JSON shape returned by pageScript.
+type domResult struct {
+	URL        string      `json:"url"`
+	Violations []Violation `json:"violations"`
+	Incomplete []Violation `json:"incomplete"`
+	Advisory   []Violation `json:"advisory"`
+}
+
+// Run audits the page currently loaded in b.
+func Run(b *e2e.Browser, page string, opts Options) (*Report, error) {
+	skip := make(map[string]bool, len(opts.Skip))
+	for _, r := range opts.Skip {
+		skip[r] = true
+	}
+	minTarget := opts.MinTargetSize
+	if minTarget == 0 {
+		minTarget = helpers.MinPointerTarget
+	}
+
+	report := &Report{
+		Page:       page,
+		Standard:   "WCAG 2.2 AA",
+		Violations: []Violation{},
+		Incomplete: []Violation{},
+		Advisory:   []Violation{},
+	}
+	for _, r := range Rules {
+		if !skip[r] {
+			report.Rules = append(report.Rules, r)
+		}
+	}
+
+	raw, err := b.Evaluate(fmt.Sprintf(pageScript, minTarget, float64(helpers.MinTouchTarget)))
+	if err != nil {
+		return nil, fmt.Errorf("page audit script failed: %w", err)
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("page audit script returned %T", raw)
+	}
+	var res domResult
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return nil, fmt.Errorf("failed to decode page audit: %w", err)
+	}
+	report.URL = res.URL
+	report.Incomplete = append(report.Incomplete, res.Incomplete...)
+	if !skip[RuleTargetSize] {
+		report.Advisory = append(report.Advisory, res.Advisory...)
+	}
+	for _, v := range res.Violations {
+		if !skip[v.Rule] {
+			report.Violations = append(report.Violations, v)
+		}
+	}
+
+	if !skip[RuleControlName] {
+		named, err := controlNameViolations(b)
+		if err != nil {
+			return nil, err
+		}
+		report.Violations = append(report.Violations, named...)
+	}
+
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		return ruleIndex(report.Violations[i].Rule) < ruleIndex(report.Violations[j].Rule)
+	})
+	return report, nil
+}
+
+// Check audits the page, writes the report to the test's artifact directory as
+// a11y-<page>.json, fails the test once per violation and logs each advisory.
+func Check(t *testing.T, b *e2e.Browser, page string, opts Options) *Report {
+	t.Helper()
+	report, err := Run(b, page, opts)
+	if err != nil {
+		t.Fatalf("Accessibility audit of %s failed to run: %v", page, err)
+	}
+
+	path := filepath.Join(helpers.ArtifactDir(t), "a11y-"+page+".json")
+	if err := report.WriteFile(path); err != nil {
+		t.Logf("Failed to write accessibility report: %v", err)
+	}
+
+	for _, v := range report.Violations {
+		t.Errorf("[%s] %s: %s", v.Rule, v.Selector, v.Message)
+	}
+	for _, v := range report.Advisory {
+		t.Logf("advisory [%s] %s: %s", v.Rule, v.Selector, v.Message)
+	}
+	if len(report.Violations) > 0 {
+		t.Logf("Accessibility report: %s", path)
+	}
+	return report
+}
+
+// WriteFile writes the report as indented JSON.
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// controlNameViolations walks the full accessibility tree and reports
+// interactive nodes whose computed accessible name is empty.
+func controlNameViolations(b *e2e.Browser) ([]Violation, error) {
+	var violations []Violation
+	err := helpers.RunCDP(b, chromedp.ActionFunc(func(ctx context.Context) error {
+		nodes, err := accessibility.GetFullAXTree().Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, n := range nodes {
+			role := axString(n.Role)
+			if n.Ignored || !namedRoles[role] || strings.TrimSpace(axString(n.Name)) != "" {
+				continue
+			}
+			violations = append(violations, Violation{
+				Rule:     RuleControlName,
+				Selector: describeBackendNode(ctx, n),
+				Message:  fmt.Sprintf("%s has no accessible name", role),
+			})
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accessibility tree: %w", err)
+	}
+	return violations, nil
+}
+
+// describeBackendNode renders a DOM node as tag#id.class for reports.
+func describeBackendNode(ctx context.Context, n *accessibility.Node) string {
+	if n.BackendDOMNodeID == 0 {
+		return fmt.Sprintf("ax-node-%s", n.NodeID)
+	}
+	node, err := dom.DescribeNode().WithBackendNodeID(n.BackendDOMNodeID).Do(ctx)
+	if err != nil {
+		return fmt.Sprintf("backend-node-%d", n.BackendDOMNodeID)
+	}
+	sel := strings.ToLower(node.NodeName)
+	for i := 0; i+1 < len(node.Attributes); i += 2 {
+		name, value := node.Attributes[i], node.Attributes[i+1]
+		switch name {
+		case "id":
+			sel += "#" + value
+		case "class":
+			for _, c := range strings.Fields(value) {
+				sel += "." + c
+			}
+		case "value", "name":
+			sel += fmt.Sprintf("[%s=%q]", name, value)
+		}
+	}
+	return sel
+}
+
+// axString decodes a string-valued accessibility property.
+func axString(v *accessibility.Value) string {
+	if v == nil || len(v.Value) == 0 {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal([]byte(v.Value), &s); err != nil {
+		return ""
+	}
+	return s
+}
+
+// ruleIndex orders violations by their rule's position in Rules.
+func ruleIndex(rule string) int {
+	for i, r := range Rules {
+		if r == rule {
+			return i
+		}
+	}
+	return len(Rules)
+}
@@ -0,0 +1,234 @@
+package audit
+
+// pageScript evaluates the DOM and computed-style rules in the page and returns
+// a JSON-encoded domResult. The format verbs are the minimum and the advisory
+// target size.
+const pageScript = `
+(() => {
+	const MIN_TARGET = %g;
+	const ADVISORY_TARGET = %g;
+	const violations = [];
+	const incomplete = [];
+	const advisory = [];
+	const add = (list, rule, el, message) => list.push({ rule, selector: describe(el), message });
+
+	// describe renders a short, stable selector for an element.
+	function describe(el) {
+		if (!el || el.nodeType !== 1) return 'document';
+		const parts = [];
+		let node = el;
+		while (node && node.nodeType === 1 && parts.length < 4) {
+			let part = node.tagName.toLowerCase();
+			if (node.id) {
+				parts.unshift(part + '#' + CSS.escape(node.id));
+				break;
+			}
+			if (node.classList.length) {
+				part += '.' + Array.from(node.classList).map(c => CSS.escape(c)).join('.');
+			}
+			const parent = node.parentElement;
+			if (parent) {
+				const same = Array.from(parent.children).filter(c => c.tagName === node.tagName);
+				if (same.length > 1) part += ':nth-of-type(' + (same.indexOf(node) + 1) + ')';
+			}
+			parts.unshift(part);
+			node = parent;
+		}
+		return parts.join(' > ');
+	}
+
+	function isVisible(el) {
+		if (el.checkVisibility && !el.checkVisibility({ opacityProperty: true, visibilityProperty: true })) {
+			return false;
+		}
+		const rect = el.getBoundingClientRect();
+		return rect.width > 1 && rect.height > 1;
+	}
+
+	// ---------------------------------------------------------------------
+	// color-contrast (1.4.3)
+	// ---------------------------------------------------------------------
+
+	const ctx = document.createElement('canvas').getContext('2d');
+
+	// parseColor normalizes any CSS color through the canvas; null if not sRGB.
+	function parseColor(value) {
+		ctx.fillStyle = '#000';
+		ctx.fillStyle = value;
+		const v = String(ctx.fillStyle);
+		if (v[0] === '#') {
+			return [parseInt(v.slice(1, 3), 16), parseInt(v.slice(3, 5), 16), parseInt(v.slice(5, 7), 16), 1];
+		}
+		if (!v.startsWith('rgb')) return null;
+		const m = v.match(/[\d.]+/g).map(Number);
+		return [m[0], m[1], m[2], m.length > 3 ? m[3] : 1];
+	}
+
+	function blend(fg, bg) {
+		const a = fg[3];
+		return [0, 1, 2].map(i => fg[i] * a + bg[i] * (1 - a)).concat(1);
+	}
+
+	// backgroundOf composites every ancestor background onto white; null when
+	// an image or gradient makes the background unknowable.
+	function backgroundOf(el) {
+		const layers = [];
+		for (let node = el; node && node.nodeType === 1; node = node.parentElement) {
+			const style = getComputedStyle(node);
+			if (style.backgroundImage !== 'none') return null;
+			const color = parseColor(style.backgroundColor);
+			if (!color) return null;
+			if (color[3] > 0) layers.push(color);
+			if (color[3] === 1) break;
+		}
+		return layers.reverse().reduce((acc, layer) => blend(layer, acc), [255, 255, 255, 1]);
+	}
+
+	function luminance(c) {
+		const [r, g, b] = c.slice(0, 3).map(v => {
+			v /= 255;
+			return v <= 0.03928 ? v / 12.92 : Math.pow((v + 0.055) / 1.055, 2.4);
+		});
+		return 0.2126 * r + 0.7152 * g + 0.0722 * b;
+	}
+
+	function contrast(a, b) {
+		const [hi, lo] = [luminance(a), luminance(b)].sort((x, y) => y - x);
+		return (hi + 0.05) / (lo + 0.05);
+	}
+
+	const textElements = new Set();
+	const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT);
+	while (walker.nextNode()) {
+		const el = walker.currentNode.parentElement;
+		if (!el || !walker.currentNode.nodeValue.trim()) continue;
+		if (el.closest('script, style, noscript, template, select, option')) continue;
+		textElements.add(el);
+	}
+
+	for (const el of textElements) {
+		if (!isVisible(el) || el.closest(':disabled')) continue;
+		const style = getComputedStyle(el);
+		const bg = backgroundOf(el);
+		const fg = parseColor(style.color);
+		if (!bg || !fg) {
+			add(incomplete, 'color-contrast', el, 'background could not be determined');
+			continue;
+		}
+		const size = parseFloat(style.fontSize);
+		const large = size >= 24 || (size >= 18.66 && parseInt(style.fontWeight, 10) >= 700);
+		const required = large ? 3 : 4.5;
+		const ratio = contrast(blend(fg, bg), bg);
+		if (ratio < required) {
+			add(violations, 'color-contrast', el,
+				'contrast ' + ratio.toFixed(2) + ':1 is below ' + required + ':1 (' + style.color + ' on rgb(' + bg.slice(0, 3).map(Math.round).join(', ') + '))');
+		}
+	}
+
+	// ---------------------------------------------------------------------
+	// landmarks (1.3.1, 2.4.1)
+	// ---------------------------------------------------------------------
+
+	const sectioning = 'article, aside, main, nav, section';
+	const landmarkCount = (native, role) => Array.from(document.querySelectorAll(native + ', [role="' + role + '"]'))
+		.filter(el => el.getAttribute('role') === role || !el.parentElement.closest(sectioning)).length;
+
+	const mains = document.querySelectorAll('main, [role="main"]').length;
+	if (mains !== 1) add(violations, 'landmarks', document.body, 'page has ' + mains + ' main landmarks, expected exactly 1');
+	const banners = landmarkCount('header', 'banner');
+	if (banners > 1) add(violations, 'landmarks', document.body, 'page has ' + banners + ' banner landmarks, expected at most 1');
+	const footers = landmarkCount('footer', 'contentinfo');
+	if (footers > 1) add(violations, 'landmarks', document.body, 'page has ' + footers + ' contentinfo landmarks, expected at most 1');
+
+	const landmark = 'main, [role="main"], header, [role="banner"], footer, [role="contentinfo"], nav, [role="navigation"], ' +
+		'aside, [role="complementary"], [role="search"], [role="region"][aria-label], [role="region"][aria-labelledby], ' +
+		'section[aria-label], section[aria-labelledby], form[aria-label], dialog, [role="dialog"], [role="alertdialog"]';
+	for (const el of textElements) {
+		if (!isVisible(el) || el.closest(landmark)) continue;
+		// Skip links are expected to sit before the first landmark
+		if (el.closest('a[href^="#"]')) continue;
+		add(violations, 'landmarks', el, 'content is not contained in a landmark');
+	}
+
+	// ---------------------------------------------------------------------
+	// target-size (2.5.8, with 2.5.5's larger size as advice)
+	// ---------------------------------------------------------------------
+
+	const targets = 'a[href], button, input:not([type="hidden"]), select, textarea, summary, ' +
+		'[role="button"], [role="link"], [role="checkbox"], [role="tab"], [role="menuitem"], [tabindex]:not([tabindex="-1"])';
+	const bigEnough = (rect, size) => rect.width >= size && rect.height >= size;
+	for (const el of document.querySelectorAll(targets)) {
+		if (!isVisible(el) || el.disabled) continue;
+		if (bigEnough(el.getBoundingClientRect(), ADVISORY_TARGET)) continue;
+
+		// Links inside a sentence are exempt
+		const style = getComputedStyle(el);
+		if (style.display === 'inline' && el.parentElement &&
+			el.parentElement.textContent.trim().length > el.textContent.trim().length) continue;
+
+		// A label that activates the control is part of its target
+		const labels = Array.from(el.labels || []);
+		const wrapping = el.closest('label');
+		if (wrapping) labels.push(wrapping);
+		const labelled = size => labels.some(l => isVisible(l) && bigEnough(l.getBoundingClientRect(), size));
+		if (labelled(ADVISORY_TARGET)) continue;
+
+		const rect = el.getBoundingClientRect();
+		const measured = 'target is ' + Math.round(rect.width) + 'x' + Math.round(rect.height);
+		if (!bigEnough(rect, MIN_TARGET) && !labelled(MIN_TARGET)) {
+			add(violations, 'target-size', el, measured + ', minimum ' + MIN_TARGET + 'x' + MIN_TARGET);
+		} else {
+			add(advisory, 'target-size', el, measured + ', advised ' + ADVISORY_TARGET + 'x' + ADVISORY_TARGET);
+		}
+	}
+
+	// ---------------------------------------------------------------------
+	// duplicate-id (4.1.1)
+	// ---------------------------------------------------------------------
+
+	const ids = new Map();
+	for (const el of document.querySelectorAll('[id]')) {
+		ids.set(el.id, (ids.get(el.id) || 0) + 1);
+	}
+	for (const [id, count] of ids) {
+		if (count > 1) {
+			violations.push({ rule: 'duplicate-id', selector: '[id="' + id + '"]', message: 'id is used by ' + count + ' elements' });
+		}
+	}
+
+	// ---------------------------------------------------------------------
+	// aria-refs and aria-valid-attr (4.1.2)
+	// ---------------------------------------------------------------------
+
+	const refAttrs = ['aria-labelledby', 'aria-describedby', 'aria-controls', 'aria-owns',
+		'aria-flowto', 'aria-details', 'aria-errormessage', 'aria-activedescendant'];
+	const validAttrs = new Set(refAttrs.concat([
+		'aria-atomic', 'aria-autocomplete', 'aria-braillelabel', 'aria-brailleroledescription', 'aria-busy',
+		'aria-checked', 'aria-colcount', 'aria-colindex', 'aria-colindextext', 'aria-colspan', 'aria-current',
+		'aria-description', 'aria-disabled', 'aria-dropeffect', 'aria-expanded', 'aria-grabbed', 'aria-haspopup',
+		'aria-hidden', 'aria-invalid', 'aria-keyshortcuts', 'aria-label', 'aria-level', 'aria-live', 'aria-modal',
+		'aria-multiline', 'aria-multiselectable', 'aria-orientation', 'aria-placeholder', 'aria-posinset',
+		'aria-pressed', 'aria-readonly', 'aria-relevant', 'aria-required', 'aria-roledescription', 'aria-rowcount',
+		'aria-rowindex', 'aria-rowindextext', 'aria-rowspan', 'aria-selected', 'aria-setsize', 'aria-sort',
+		'aria-valuemax', 'aria-valuemin', 'aria-valuenow', 'aria-valuetext',
+	]));
+
+	for (const el of document.querySelectorAll('*')) {
+		for (const attr of el.attributes) {
+			if (!attr.name.startsWith('aria-')) continue;
+			if (!validAttrs.has(attr.name)) {
+				add(violations, 'aria-valid-attr', el, attr.name + ' is not a valid ARIA attribute');
+				continue;
+			}
+			if (!refAttrs.includes(attr.name)) continue;
+			for (const ref of attr.value.trim().split(/\s+/).filter(Boolean)) {
+				if (!document.getElementById(ref)) {
+					add(violations, 'aria-refs', el, attr.name + ' references missing id "' + ref + '"');
+				}
+			}
+		}
+	}
+
+	return JSON.stringify({ url: location.href, violations, incomplete, advisory });
+})()
+`
@@ -0,0 +1,324 @@
+package helpers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/cachestorage"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Cache Storage
+// =============================================================================
+
+// Cache name prefixes used by sw.js. Each is followed by the build's
+// CACHE_VERSION, so a prefix matches the caches of any build.
+const (
+	ShellCachePrefix    = "michael-shell-v"
+	ChaptersCachePrefix = "michael-chapters-v"
+	MetadataCachePrefix = "michael-metadata-v"
+)
+
+// cacheEntriesPageSize is how many entries are requested from Chrome at once.
+const cacheEntriesPageSize = 200
+
+// CacheEntry is one request/response pair held in Cache Storage.
+type CacheEntry struct {
+	// Cache is the name of the cache holding the entry.
+	Cache string
+	// URL is the request path and query, relative to the site's origin.
+	URL         string
+	Status      int
+	ContentType string
+}
+
+// CacheStorage reads and edits the site's Cache Storage over CDP, without
+// going through the page or the service worker.
+type CacheStorage struct {
+	t      *testing.T
+	b      *e2e.Browser
+	origin string
+}
+
+// OpenCacheStorage returns the Cache Storage of the site's origin.
+func OpenCacheStorage(t *testing.T, b *e2e.Browser) *CacheStorage {
+	t.Helper()
+	origin, err := siteOrigin()
+	if err != nil {
+		t.Fatalf("Failed to determine site origin: %v", err)
+	}
+	return &CacheStorage{t: t, b: b, origin: origin}
+}
+
+// caches returns the caches whose names start with prefix.
+func (c *CacheStorage) caches(prefix string) []*cachestorage.Cache {
+	c.t.Helper()
+	var all []*cachestorage.Cache
+	err := RunCDP(c.b, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		all, err = cachestorage.RequestCacheNames().WithSecurityOrigin(c.origin).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		c.t.Fatalf("Failed to list caches for %s: %v", c.origin, err)
+	}
+	var matched []*cachestorage.Cache
+	for _, cache := range all {
+		if strings.HasPrefix(cache.CacheName, prefix) {
+			matched = append(matched, cache)
+		}
+	}
+	return matched
+}
+
+// Names returns the names of the caches that start with prefix; an empty
+// prefix returns every cache.
+func (c *CacheStorage) Names(prefix string) []string {
+	c.t.Helper()
+	var names []string
+	for _, cache := range c.caches(prefix) {
+		names = append(names, cache.CacheName)
+	}
+	return names
+}
+
+// Entries returns every entry of the caches that start with prefix.
+func (c *CacheStorage) Entries(prefix string) []CacheEntry {
+	c.t.Helper()
+	var entries []CacheEntry
+	for _, cache := range c.caches(prefix) {
+		for skip := int64(0); ; {
+			var page []*cachestorage.DataEntry
+			var total float64
+			err := RunCDP(c.b, chromedp.ActionFunc(func(ctx context.Context) error {
+				var err error
+				page, total, err = cachestorage.RequestEntries(cache.CacheID).
+					WithSkipCount(skip).WithPageSize(cacheEntriesPageSize).Do(ctx)
+				return err
+			}))
+			if err != nil {
+				c.t.Fatalf("Failed to read entries of cache %s: %v", cache.CacheName, err)
+			}
+			for _, e := range page {
+				entries = append(entries, CacheEntry{
+					Cache:       cache.CacheName,
+					URL:         strings.TrimPrefix(e.RequestURL, c.origin),
+					Status:      int(e.ResponseStatus),
+					ContentType: headerValue(e.ResponseHeaders, "Content-Type"),
+				})
+			}
+			skip += int64(len(page))
+			if len(page) == 0 || float64(skip) >= total {
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// URLs returns the request URLs held by the caches that start with prefix,
+// relative to the site's origin.
+func (c *CacheStorage) URLs(prefix string) []string {
+	c.t.Helper()
+	var urls []string
+	for _, e := range c.Entries(prefix) {
+		urls = append(urls, e.URL)
+	}
+	return urls
+}
+
+// Has reports whether any cache starting with prefix holds resource, a path
+// such as "/offline.html".
+func (c *CacheStorage) Has(prefix, resource string) bool {
+	c.t.Helper()
+	for _, url := range c.URLs(prefix) {
+		if url == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// Read returns the cached response body for resource from the first cache
+// starting with prefix that holds it, failing the test if none does.
+func (c *CacheStorage) Read(prefix, resource string) []byte {
+	c.t.Helper()
+	for _, cache := range c.caches(prefix) {
+		var res *cachestorage.CachedResponse
+		err := RunCDP(c.b, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			res, err = cachestorage.RequestCachedResponse(cache.CacheID, c.origin+resource, []*cachestorage.Header{}).Do(ctx)
+			return err
+		}))
+		if err != nil || res == nil {
+			continue
+		}
+		body, err := base64.StdEncoding.DecodeString(res.Body)
+		if err != nil {
+			c.t.Fatalf("Failed to decode cached %s: %v", resource, err)
+		}
+		return body
+	}
+	c.t.Fatalf("No cache starting with %s holds %s", prefix, resource)
+	return nil
+}
+
+// seedScript stores a response in a cache from the page, since CDP can read
+// and delete entries but not add them.
+const seedScript = `
+(async () => {
+	const cache = await caches.open(%s);
+	await cache.put(%s, new Response(%s, { headers: { 'Content-Type': %s } }));
+	return true;
+})()
+`
+
+// Seed stores body as the response for resource in the cache with the exact
+// name given, creating the cache if needed. Use ServiceWorkerManifest for the
+// names the service worker reads from.
+func (c *CacheStorage) Seed(cache, resource, contentType string, body []byte) {
+	c.t.Helper()
+	script := fmt.Sprintf(seedScript, jsString(cache), jsString(resource), jsString(string(body)), jsString(contentType))
+	if _, err := c.b.Evaluate(script); err != nil {
+		c.t.Fatalf("Failed to seed %s into cache %s: %v", resource, cache, err)
+	}
+}
+
+// Delete removes resource from every cache starting with prefix.
+func (c *CacheStorage) Delete(prefix, resource string) {
+	c.t.Helper()
+	for _, cache := range c.caches(prefix) {
+		if err := RunCDP(c.b, cachestorage.DeleteEntry(cache.CacheID, c.origin+resource)); err != nil {
+			c.t.Fatalf("Failed to delete %s from cache %s: %v", resource, cache.CacheName, err)
+		}
+	}
+}
+
+// DeleteCaches removes every cache starting with prefix.
+func (c *CacheStorage) DeleteCaches(prefix string) {
+	c.t.Helper()
+	for _, cache := range c.caches(prefix) {
+		if err := RunCDP(c.b, cachestorage.DeleteCache(cache.CacheID)); err != nil {
+			c.t.Fatalf("Failed to delete cache %s: %v", cache.CacheName, err)
+		}
+	}
+}
+
+// headerValue returns the first value of the named header.
+func headerValue(headers []*cachestorage.Header, name string) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// =============================================================================
+// Service Worker Manifest
+// =============================================================================
+
+// ServiceWorkerManifest is what the built sw.js pre-caches on install.
+type ServiceWorkerManifest struct {
+	// Version is the build's CACHE_VERSION.
+	Version string
+	// ShellAssets are the SHELL_ASSETS paths, including the fingerprinted CSS.
+	ShellAssets []string
+	// DefaultChapters are the DEFAULT_CHAPTERS paths.
+	DefaultChapters []string
+}
+
+// ShellCache returns the name of the build's shell cache.
+func (m ServiceWorkerManifest) ShellCache() string { return ShellCachePrefix + m.Version }
+
+// ChaptersCache returns the name of the build's chapters cache.
+func (m ServiceWorkerManifest) ChaptersCache() string { return ChaptersCachePrefix + m.Version }
+
+// MetadataCache returns the name of the build's metadata cache.
+func (m ServiceWorkerManifest) MetadataCache() string { return MetadataCachePrefix + m.Version }
+
+var (
+	swStringConst = regexp.MustCompile("\\b([A-Z][A-Z0-9_]*)\\s*=\\s*(?:'([^']*)'|\"([^\"]*)\")")
+	swComment     = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+	swArrayItem   = regexp.MustCompile("'([^']*)'|\"([^\"]*)\"|`([^`]*)`|\\b([A-Z][A-Z0-9_]*)\\b")
+)
+
+// LoadServiceWorkerManifest fetches the site's sw.js and reads its cache
+// version and pre-cache lists.
+func LoadServiceWorkerManifest(t *testing.T) ServiceWorkerManifest {
+	t.Helper()
+	resp, err := http.Get(BaseURL + "/sw.js")
+	if err != nil {
+		t.Fatalf("Failed to fetch sw.js: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("sw.js returned %s", resp.Status)
+	}
+	src, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read sw.js: %v", err)
+	}
+	m, err := ParseServiceWorkerManifest(string(src))
+	if err != nil {
+		t.Fatalf("Failed to parse sw.js: %v", err)
+	}
+	return m
+}
+
+// ParseServiceWorkerManifest reads the cache version and pre-cache lists from
+// service worker source, minified or not. List items may be string literals
+// or the names of string constants, such as OFFLINE_URL.
+func ParseServiceWorkerManifest(src string) (ServiceWorkerManifest, error) {
+	consts := map[string]string{}
+	for _, m := range swStringConst.FindAllStringSubmatch(src, -1) {
+		if _, seen := consts[m[1]]; !seen {
+			consts[m[1]] = m[2] + m[3]
+		}
+	}
+
+	m := ServiceWorkerManifest{Version: consts["CACHE_VERSION"]}
+	if m.Version == "" {
+		return m, errors.New("no CACHE_VERSION")
+	}
+	var err error
+	if m.ShellAssets, err = swList(src, "SHELL_ASSETS", consts); err != nil {
+		return m, err
+	}
+	if m.DefaultChapters, err = swList(src, "DEFAULT_CHAPTERS", consts); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// swList returns the items of the array constant name.
+func swList(src, name string, consts map[string]string) ([]string, error) {
+	decl := regexp.MustCompile(`\b` + name + `\s*=\s*\[([^\]]*)\]`).FindStringSubmatch(src)
+	if decl == nil {
+		return nil, fmt.Errorf("no %s", name)
+	}
+	var items []string
+	for _, item := range swArrayItem.FindAllStringSubmatch(swComment.ReplaceAllString(decl[1], ""), -1) {
+		switch {
+		case item[4] == "":
+			items = append(items, item[1]+item[2]+item[3])
+		case consts[item[4]] != "":
+			items = append(items, consts[item[4]])
+		default:
+			return nil, fmt.Errorf("%s refers to unknown constant %s", name, item[4])
+		}
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s is empty", name)
+	}
+	return items, nil
+}
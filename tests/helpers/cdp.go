@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Chrome DevTools Protocol Access
+// =============================================================================
+
+// RunCDP runs chromedp actions against the page target driven by b. magellan
+// drives Chrome through chromedp, and its Context is the target context, so
+// raw CDP calls share the page, cookies and storage with the e2e helpers.
+func RunCDP(b *e2e.Browser, actions ...chromedp.Action) error {
+	return chromedp.Run(b.Context(), actions...)
+}
+
+// GrantClipboard lets the page write to the clipboard without a user prompt,
+// which headless Chrome otherwise denies.
+func GrantClipboard(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	err := RunCDP(b, chromedp.ActionFunc(func(ctx context.Context) error {
+		// Permissions belong to the browser, not the page target
+		exec := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+		return browser.GrantPermissions([]browser.PermissionType{
+			browser.PermissionTypeClipboardReadWrite,
+			browser.PermissionTypeClipboardSanitizedWrite,
+		}).Do(exec)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to grant clipboard permission: %v", err)
+	}
+}
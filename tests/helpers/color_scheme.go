@@ -0,0 +1,319 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"michael-tests/helpers/selectors"
+)
+
+// =============================================================================
+// User Preference Emulation
+// =============================================================================
+
+// ColorMode is a combination of color preferences a page can be viewed in,
+// as media feature values.
+type ColorMode struct {
+	Name     string
+	Features map[string]string
+}
+
+// ColorModes are the color preferences every contrast check runs under.
+var ColorModes = []ColorMode{
+	{"light", map[string]string{"prefers-color-scheme": "light"}},
+	{"dark", map[string]string{"prefers-color-scheme": "dark"}},
+	{"more-contrast", map[string]string{"prefers-contrast": "more"}},
+	{"forced-colors", map[string]string{"forced-colors": "active", "prefers-color-scheme": "dark"}},
+}
+
+// EmulateMediaFeatures makes the page match the given media features, such as
+// prefers-color-scheme: dark or prefers-reduced-motion: reduce, until the
+// test ends. Emulate before navigating so scripts read them on load.
+func EmulateMediaFeatures(t *testing.T, b *e2e.Browser, features map[string]string) {
+	t.Helper()
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var list []*emulation.MediaFeature
+	for _, name := range names {
+		list = append(list, &emulation.MediaFeature{Name: name, Value: features[name]})
+	}
+	if err := RunCDP(b, emulation.SetEmulatedMedia().WithFeatures(list)); err != nil {
+		t.Fatalf("Failed to emulate %v: %v", features, err)
+	}
+	t.Cleanup(func() { RunCDP(b, emulation.SetEmulatedMedia()) })
+}
+
+// motionScript lists the elements that still animate or transition, and
+// smooth scrolling, as a JSON array.
+const motionScript = `
+(() => {
+	const seconds = (list) => Math.max(...list.split(',').map((d) => parseFloat(d) * (d.trim().endsWith('ms') ? 0.001 : 1)));
+	const describe = (el) => el.tagName.toLowerCase() + (el.id ? '#' + el.id : '') +
+		(el.classList.length ? '.' + Array.from(el.classList).join('.') : '');
+	const moving = [];
+	for (const el of document.querySelectorAll('*')) {
+		const style = getComputedStyle(el);
+		if (style.animationName !== 'none' && seconds(style.animationDuration) > 0.001) {
+			moving.push(describe(el) + ' animates ' + style.animationName + ' for ' + style.animationDuration);
+		}
+		if (seconds(style.transitionDuration) > 0.001) {
+			moving.push(describe(el) + ' transitions ' + style.transitionProperty + ' over ' + style.transitionDuration);
+		}
+	}
+	if (getComputedStyle(document.documentElement).scrollBehavior === 'smooth') {
+		moving.push('html scrolls smoothly');
+	}
+	return JSON.stringify(moving);
+})()
+`
+
+// MotionOffenders lists what still moves on the current page: animations and
+// transitions longer than a millisecond, and smooth scrolling.
+func MotionOffenders(t *testing.T, b *e2e.Browser) []string {
+	t.Helper()
+	raw, err := b.Evaluate(motionScript)
+	if err != nil {
+		t.Fatalf("Failed to inspect animations: %v", err)
+	}
+	s, _ := raw.(string)
+	var moving []string
+	if err := json.Unmarshal([]byte(s), &moving); err != nil {
+		t.Fatalf("Failed to decode animations: %v", err)
+	}
+	return moving
+}
+
+// =============================================================================
+// Contrast
+// =============================================================================
+
+// contrastScript measures the text contrast of up to a limited number of
+// visible elements matching a selector and returns them as JSON. Backgrounds
+// are composited from the element up to the canvas; background images are
+// ignored.
+const contrastScript = `
+(() => {
+	const canvas = document.createElement('canvas');
+	canvas.width = canvas.height = 1;
+	const ctx = canvas.getContext('2d', { willReadFrequently: true });
+	const rgba = (css) => {
+		ctx.clearRect(0, 0, 1, 1);
+		ctx.fillStyle = '#000';
+		ctx.fillStyle = css;
+		ctx.fillRect(0, 0, 1, 1);
+		const [r, g, b, a] = ctx.getImageData(0, 0, 1, 1).data;
+		return [r, g, b, a / 255];
+	};
+	const over = (top, bottom) => {
+		const a = top[3];
+		return [0, 1, 2].map((i) => top[i] * a + bottom[i] * (1 - a)).concat(1);
+	};
+	const background = (el) => {
+		const layers = [];
+		for (let node = el; node; node = node.parentElement) {
+			const c = rgba(getComputedStyle(node).backgroundColor);
+			if (c[3] > 0) layers.push(c);
+			if (c[3] >= 1) break;
+		}
+		let color = layers.length && layers[layers.length - 1][3] >= 1 ? [] : rgba('Canvas');
+		if (color.length && color[3] < 1) color = [255, 255, 255, 1];
+		for (let i = layers.length - 1; i >= 0; i--) {
+			color = color.length ? over(layers[i], color) : layers[i];
+		}
+		return color;
+	};
+	const luminance = (c) => {
+		const [r, g, b] = c.slice(0, 3).map((v) => {
+			v /= 255;
+			return v <= 0.03928 ? v / 12.92 : Math.pow((v + 0.055) / 1.055, 2.4);
+		});
+		return 0.2126 * r + 0.7152 * g + 0.0722 * b;
+	};
+	const hex = (c) => '#' + c.slice(0, 3).map((v) => Math.round(v).toString(16).padStart(2, '0')).join('');
+	const describe = (el) => el.tagName.toLowerCase() + (el.id ? '#' + el.id : '') +
+		(el.classList.length ? '.' + Array.from(el.classList).join('.') : '');
+
+	const samples = [];
+	for (const el of document.querySelectorAll(%q)) {
+		if (samples.length >= %d) break;
+		const text = el.textContent.trim();
+		if (!text || !el.checkVisibility({ opacityProperty: true, visibilityProperty: true })) continue;
+		const style = getComputedStyle(el);
+		const bg = background(el);
+		const fg = over(rgba(style.color), bg);
+		const [hi, lo] = [luminance(fg), luminance(bg)].sort((a, b) => b - a);
+		const size = parseFloat(style.fontSize);
+		const large = size >= 24 || (size >= 18.66 && parseInt(style.fontWeight, 10) >= 700);
+		samples.push({
+			element: describe(el),
+			text: text.slice(0, 40),
+			color: hex(fg),
+			background: hex(bg),
+			ratio: Math.round((hi + 0.05) / (lo + 0.05) * 100) / 100,
+			required: large ? 3 : 4.5
+		});
+	}
+	return JSON.stringify(samples);
+})()
+`
+
+// ContrastSample is the contrast of one element's text against the colors
+// behind it.
+type ContrastSample struct {
+	Element    string  `json:"element"`
+	Text       string  `json:"text"`
+	Color      string  `json:"color"`
+	Background string  `json:"background"`
+	Ratio      float64 `json:"ratio"`
+	// Required is the WCAG AA minimum: 3 for large text and 4.5 otherwise.
+	Required float64 `json:"required"`
+}
+
+// MeasureContrast returns the text contrast of up to limit visible elements
+// matching selector, in document order.
+func MeasureContrast(t *testing.T, b *e2e.Browser, selector string, limit int) []ContrastSample {
+	t.Helper()
+	raw, err := b.Evaluate(fmt.Sprintf(contrastScript, selector, limit))
+	if err != nil {
+		t.Fatalf("Failed to measure contrast of %s: %v", selector, err)
+	}
+	s, _ := raw.(string)
+	var samples []ContrastSample
+	if err := json.Unmarshal([]byte(s), &samples); err != nil {
+		t.Fatalf("Failed to decode contrast of %s: %v", selector, err)
+	}
+	return samples
+}
+
+// CheckContrast fails the test for every color pair in samples below its
+// required ratio, reporting each pair once with how many elements use it.
+func CheckContrast(t *testing.T, name string, samples []ContrastSample) {
+	t.Helper()
+	type pair struct{ color, background string }
+	counts := map[pair]int{}
+	var failing []ContrastSample
+	for _, s := range samples {
+		if s.Ratio >= s.Required {
+			continue
+		}
+		p := pair{s.Color, s.Background}
+		if counts[p] == 0 {
+			failing = append(failing, s)
+		}
+		counts[p]++
+	}
+	for _, s := range failing {
+		t.Errorf("%s: %s on %s is %.2f:1, below %.1f:1, on %d elements such as %s %q",
+			name, s.Color, s.Background, s.Ratio, s.Required, counts[pair{s.Color, s.Background}], s.Element, s.Text)
+	}
+}
+
+// =============================================================================
+// Theme Toggle
+// =============================================================================
+
+// themeStorageKey is the localStorage key theme-init.js and theme-toggle.js
+// keep the chosen theme under.
+const themeStorageKey = "theme"
+
+// themeToggleScript clicks the button theme-toggle.js binds to and reports
+// whether there was one.
+const themeToggleScript = `
+(() => {
+	const btn = document.querySelector(%q);
+	if (btn) btn.click();
+	return !!btn;
+})()
+`
+
+// firstPaintObserver records the data-theme attribute as the first frame is
+// rendered, which is what shows if the theme is applied late.
+const firstPaintObserver = `
+requestAnimationFrame(() => {
+	window.__michaelFirstPaintTheme = document.documentElement.getAttribute('data-theme') || '';
+});
+`
+
+// CurrentTheme returns the page's data-theme attribute.
+func CurrentTheme(t *testing.T, b *e2e.Browser) string {
+	t.Helper()
+	raw, err := b.Evaluate(`document.documentElement.getAttribute('data-theme') || ''`)
+	if err != nil {
+		t.Fatalf("Failed to read the theme: %v", err)
+	}
+	s, _ := raw.(string)
+	return s
+}
+
+// ToggleTheme clicks the theme toggle and returns the new theme, failing
+// unless the page switched themes and stored the choice.
+func ToggleTheme(t *testing.T, b *e2e.Browser) string {
+	t.Helper()
+	before := CurrentTheme(t, b)
+	raw, err := b.Evaluate(fmt.Sprintf(themeToggleScript, selectors.IDThemeToggle))
+	if err != nil {
+		t.Fatalf("Failed to click the theme toggle: %v", err)
+	}
+	if found, _ := raw.(bool); !found {
+		t.Fatal("No theme toggle on the page")
+	}
+	after := CurrentTheme(t, b)
+	if after == before {
+		t.Fatalf("Theme toggle left the theme %q", before)
+	}
+	raw, err = b.Evaluate(fmt.Sprintf(`localStorage.getItem(%q) || ''`, themeStorageKey))
+	if err != nil {
+		t.Fatalf("Failed to read the stored theme: %v", err)
+	}
+	if stored, _ := raw.(string); stored != after {
+		t.Errorf("Theme toggle stored %q, expected %q", stored, after)
+	}
+	return after
+}
+
+// FirstPaintTheme records the theme each new document paints first.
+type FirstPaintTheme struct {
+	t *testing.T
+	b *e2e.Browser
+}
+
+// RecordFirstPaintTheme starts recording the theme of the first frame of
+// every page b loads from now on.
+func RecordFirstPaintTheme(t *testing.T, b *e2e.Browser) *FirstPaintTheme {
+	t.Helper()
+	var id page.ScriptIdentifier
+	err := RunCDP(b, chromedp.ActionFunc(func(ctx context.Context) (err error) {
+		id, err = page.AddScriptToEvaluateOnNewDocument(firstPaintObserver).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("Failed to watch the first paint: %v", err)
+	}
+	t.Cleanup(func() { RunCDP(b, page.RemoveScriptToEvaluateOnNewDocument(id)) })
+	return &FirstPaintTheme{t: t, b: b}
+}
+
+// Theme returns the data-theme the current page had when it first painted.
+func (r *FirstPaintTheme) Theme() string {
+	r.t.Helper()
+	raw, err := r.b.Evaluate(`window.__michaelFirstPaintTheme`)
+	if err != nil {
+		r.t.Fatalf("Failed to read the first paint theme: %v", err)
+	}
+	s, ok := raw.(string)
+	if !ok {
+		r.t.Fatal("The page has not painted, or RecordFirstPaintTheme was not called before navigating")
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,330 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers/selectors"
+)
+
+// =============================================================================
+// Compare Page Objects
+// =============================================================================
+
+// Compare page selectors. The compare page renders both modes; SSS controls
+// carry an sss- prefix. The verse grid and color picker IDs are rendered from
+// a prefix, so they are spelled out here and checked by selectorgen.
+const (
+	compareURL            = "/bible/compare/"
+	compareTranslation    = selectors.ClassTranslationCheckbox
+	compareBookSelect     = selectors.IDBookSelect
+	compareChapterSelect  = selectors.IDChapterSelect
+	compareNormalMode     = selectors.IDNormalMode
+	compareContent        = selectors.IDParallelContent
+	compareVerse          = selectors.ClassParallelVerse
+	compareVerseButton    = "#verse-buttons " + selectors.ClassVerseBtn
+	compareHighlight      = selectors.IDHighlightToggle
+	compareColorButton    = "#highlight-color-btn"
+	compareColorPicker    = "#highlight-color-picker"
+	compareColorOption    = compareColorPicker + " " + selectors.ClassColorOption
+	compareSSSButton      = selectors.IDSssModeBtn
+	sssMode               = selectors.IDSssMode
+	sssBackButton         = selectors.IDSssBackBtn
+	sssBibleLeft          = selectors.IDSssBibleLeft
+	sssBibleRight         = selectors.IDSssBibleRight
+	sssBookSelect         = selectors.IDSssBookSelect
+	sssChapterSelect      = selectors.IDSssChapterSelect
+	sssLeftPane           = selectors.IDSssLeftPane
+	sssRightPane          = selectors.IDSssRightPane
+	selectedVerseBtnClass = "is-active"
+)
+
+// LoadedChapter is the detail of a michael:chapter-loaded event.
+type LoadedChapter struct {
+	// Mode is "compare" for the verse-by-verse view or "sss" for side by side.
+	Mode    string   `json:"mode"`
+	Bibles  []string `json:"bibles"`
+	Book    string   `json:"book"`
+	Chapter int      `json:"chapter"`
+}
+
+// ComparePage drives the compare page in its normal verse-by-verse mode.
+type ComparePage struct {
+	t *testing.T
+	b *e2e.Browser
+}
+
+// OpenComparePage navigates to the compare page and waits for its controls.
+func OpenComparePage(t *testing.T, b *e2e.Browser) *ComparePage {
+	t.Helper()
+	NavigateToCompare(t, b)
+	return &ComparePage{t: t, b: b}
+}
+
+// SelectTranslations checks each translation, leaving the others as they are.
+func (p *ComparePage) SelectTranslations(ids ...string) {
+	p.t.Helper()
+	for _, id := range ids {
+		CheckCheckbox(p.t, p.b, translationSelector(id))
+	}
+}
+
+// OpenChapter selects a book and chapter and waits for the chapter to render.
+// At least one translation must already be selected.
+func (p *ComparePage) OpenChapter(book string, chapter int) LoadedChapter {
+	p.t.Helper()
+	return openChapter(p.t, p.b, compareBookSelect, compareChapterSelect, book, chapter)
+}
+
+// SelectBook selects a book without waiting for a chapter to load, for pages
+// with no translation selected.
+func (p *ComparePage) SelectBook(book string) {
+	p.t.Helper()
+	SelectOption(p.t, p.b, compareBookSelect, book)
+	if err := p.b.WaitForEnabled(compareChapterSelect); err != nil {
+		p.t.Fatalf("Chapter select did not enable: %v", err)
+	}
+}
+
+// SelectVerse clicks a verse in the verse grid and waits for the comparison
+// to re-render with only that verse.
+func (p *ComparePage) SelectVerse(verse int) LoadedChapter {
+	p.t.Helper()
+	btn := p.VerseButton(verse)
+	if err := p.b.WaitFor(verseButtonSelector(verse)); err != nil {
+		p.t.Fatalf("Verse %d is not in the verse grid: %v", verse, err)
+	}
+	loaded := ListenForChapterLoaded(p.t, p.b)
+	if err := btn.Click(); err != nil {
+		p.t.Fatalf("Failed to click verse %d: %v", verse, err)
+	}
+	var ch LoadedChapter
+	loaded.WaitInto(&ch)
+	return ch
+}
+
+// IsVerseSelected reports whether the verse grid marks the verse as selected.
+func (p *ComparePage) IsVerseSelected(verse int) bool {
+	class, _ := p.VerseButton(verse).Attribute("class")
+	return hasClass(class, selectedVerseBtnClass)
+}
+
+// BookCount returns the number of books offered.
+func (p *ComparePage) BookCount() int {
+	p.t.Helper()
+	return optionCount(p.t, p.b, compareBookSelect)
+}
+
+// ChapterCount returns the number of chapters offered for the selected book.
+func (p *ComparePage) ChapterCount() int {
+	p.t.Helper()
+	return optionCount(p.t, p.b, compareChapterSelect)
+}
+
+// VerseCount returns the number of verses rendered in the comparison.
+func (p *ComparePage) VerseCount() int {
+	p.t.Helper()
+	return countElements(p.t, p.b, compareContent+" "+compareVerse)
+}
+
+// EnterSSS switches to side-by-side mode.
+func (p *ComparePage) EnterSSS() *SSSMode {
+	p.t.Helper()
+	WaitAndClick(p.t, p.b, compareSSSButton)
+	if err := p.b.WaitForVisible(sssMode); err != nil {
+		p.t.Fatalf("SSS mode did not open: %v", err)
+	}
+	return p.SSS()
+}
+
+// SSS returns the side-by-side mode without switching to it, for tests that
+// activate it some other way.
+func (p *ComparePage) SSS() *SSSMode { return &SSSMode{t: p.t, b: p.b} }
+
+// TranslationCheckbox returns the checkbox for a translation, or the first
+// checkbox when id is empty.
+func (p *ComparePage) TranslationCheckbox(id string) *e2e.Element {
+	if id == "" {
+		return p.b.Find(compareTranslation)
+	}
+	return p.b.Find(translationSelector(id))
+}
+
+// Content returns the comparison container, which also holds the page's
+// message when no translation could be loaded.
+func (p *ComparePage) Content() *e2e.Element { return p.b.Find(compareContent) }
+
+// BookSelect returns the book dropdown.
+func (p *ComparePage) BookSelect() *e2e.Element { return p.b.Find(compareBookSelect) }
+
+// ChapterSelect returns the chapter dropdown.
+func (p *ComparePage) ChapterSelect() *e2e.Element { return p.b.Find(compareChapterSelect) }
+
+// VerseButton returns the verse grid button for a verse.
+func (p *ComparePage) VerseButton(verse int) *e2e.Element {
+	return p.b.Find(verseButtonSelector(verse))
+}
+
+// NormalMode returns the normal-mode control panel.
+func (p *ComparePage) NormalMode() *e2e.Element { return p.b.Find(compareNormalMode) }
+
+// SSSButton returns the button that switches to side-by-side mode.
+func (p *ComparePage) SSSButton() *e2e.Element { return p.b.Find(compareSSSButton) }
+
+// HighlightToggle returns the "Compare Differences" checkbox.
+func (p *ComparePage) HighlightToggle() *e2e.Element { return p.b.Find(compareHighlight) }
+
+// ColorButton returns the highlight color picker button.
+func (p *ComparePage) ColorButton() *e2e.Element { return p.b.Find(compareColorButton) }
+
+// ColorPicker returns the highlight color dropdown.
+func (p *ComparePage) ColorPicker() *e2e.Element { return p.b.Find(compareColorPicker) }
+
+// ColorOption returns the first highlight color option.
+func (p *ComparePage) ColorOption() *e2e.Element { return p.b.Find(compareColorOption) }
+
+// =============================================================================
+// SSS Mode
+// =============================================================================
+
+// SSSMode drives the compare page's side-by-side mode.
+type SSSMode struct {
+	t *testing.T
+	b *e2e.Browser
+}
+
+// SelectBibles chooses the left and right Bibles.
+func (m *SSSMode) SelectBibles(left, right string) {
+	m.t.Helper()
+	SelectOption(m.t, m.b, sssBibleLeft, left)
+	SelectOption(m.t, m.b, sssBibleRight, right)
+}
+
+// OpenChapter selects a book and chapter and waits for both panes to render.
+// Both Bibles must already be selected.
+func (m *SSSMode) OpenChapter(book string, chapter int) LoadedChapter {
+	m.t.Helper()
+	return openChapter(m.t, m.b, sssBookSelect, sssChapterSelect, book, chapter)
+}
+
+// BibleCounts returns the number of Bibles offered on each side.
+func (m *SSSMode) BibleCounts() (left, right int) {
+	m.t.Helper()
+	return optionCount(m.t, m.b, sssBibleLeft), optionCount(m.t, m.b, sssBibleRight)
+}
+
+// BookCount returns the number of books offered.
+func (m *SSSMode) BookCount() int {
+	m.t.Helper()
+	return optionCount(m.t, m.b, sssBookSelect)
+}
+
+// PaneVerseCounts returns the number of verses rendered in each pane.
+func (m *SSSMode) PaneVerseCounts() (left, right int) {
+	m.t.Helper()
+	return countElements(m.t, m.b, sssLeftPane+" "+compareVerse),
+		countElements(m.t, m.b, sssRightPane+" "+compareVerse)
+}
+
+// Exit returns to the normal compare mode.
+func (m *SSSMode) Exit() *ComparePage {
+	m.t.Helper()
+	WaitAndClick(m.t, m.b, sssBackButton)
+	if err := m.b.WaitForVisible(compareNormalMode); err != nil {
+		m.t.Fatalf("Normal mode did not return: %v", err)
+	}
+	return &ComparePage{t: m.t, b: m.b}
+}
+
+// Root returns the SSS mode container.
+func (m *SSSMode) Root() *e2e.Element { return m.b.Find(sssMode) }
+
+// BackButton returns the button that exits SSS mode.
+func (m *SSSMode) BackButton() *e2e.Element { return m.b.Find(sssBackButton) }
+
+// LeftBibleSelect returns the left Bible dropdown.
+func (m *SSSMode) LeftBibleSelect() *e2e.Element { return m.b.Find(sssBibleLeft) }
+
+// RightBibleSelect returns the right Bible dropdown.
+func (m *SSSMode) RightBibleSelect() *e2e.Element { return m.b.Find(sssBibleRight) }
+
+// BookSelect returns the SSS book dropdown.
+func (m *SSSMode) BookSelect() *e2e.Element { return m.b.Find(sssBookSelect) }
+
+// =============================================================================
+// Shared
+// =============================================================================
+
+// openChapter selects a book, which loads its first chapter, then the
+// chapter if it is not already showing, waiting for each render.
+func openChapter(t *testing.T, b *e2e.Browser, bookSel, chapterSel, book string, chapter int) LoadedChapter {
+	t.Helper()
+	ch := LoadedChapter{Book: book, Chapter: 1}
+	if current, _ := b.Find(bookSel).Value(); current != book {
+		loaded := ListenForChapterLoaded(t, b)
+		SelectOption(t, b, bookSel, book)
+		loaded.WaitInto(&ch)
+		if ch.Book != book {
+			t.Fatalf("Loaded book %s, expected %s", ch.Book, book)
+		}
+	} else if current, _ := b.Find(chapterSel).Value(); current != "" {
+		ch.Chapter, _ = strconv.Atoi(current)
+	}
+	if ch.Chapter == chapter {
+		return ch
+	}
+
+	if err := b.WaitForEnabled(chapterSel); err != nil {
+		t.Fatalf("Chapter select %s did not enable: %v", chapterSel, err)
+	}
+	loaded := ListenForChapterLoaded(t, b)
+	SelectOption(t, b, chapterSel, strconv.Itoa(chapter))
+	loaded.WaitInto(&ch)
+	if ch.Book != book || ch.Chapter != chapter {
+		t.Fatalf("Loaded %s %d, expected %s %d", ch.Book, ch.Chapter, book, chapter)
+	}
+	return ch
+}
+
+// translationSelector returns the compare checkbox selector for a translation.
+func translationSelector(id string) string {
+	return fmt.Sprintf("%s[value='%s']", compareTranslation, id)
+}
+
+// verseButtonSelector returns the verse grid selector for a verse.
+func verseButtonSelector(verse int) string {
+	return fmt.Sprintf("%s[data-verse='%d']", compareVerseButton, verse)
+}
+
+// countElements returns how many elements match selector.
+func countElements(t *testing.T, b *e2e.Browser, selector string) int {
+	t.Helper()
+	elems, err := b.FindAll(selector)
+	if err != nil {
+		t.Fatalf("Failed to find %s: %v", selector, err)
+	}
+	return elems.Count()
+}
+
+// optionCount returns the number of options in a select, not counting its
+// placeholder.
+func optionCount(t *testing.T, b *e2e.Browser, selector string) int {
+	t.Helper()
+	if err := b.WaitFor(selector); err != nil {
+		t.Fatalf("Select %s not found: %v", selector, err)
+	}
+	return countElements(t, b, selector+" option") - 1
+}
+
+// hasClass reports whether a class attribute contains name.
+func hasClass(classAttr, name string) bool {
+	for _, c := range strings.Fields(classAttr) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
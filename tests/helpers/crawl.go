@@ -0,0 +1,447 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io/fs"
+	"math/rand/v2"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/Public.Website.MichaelCore/pkg/quality"
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/fixtures"
+	"michael-tests/helpers/selectors"
+)
+
+// =============================================================================
+// Source Data
+// =============================================================================
+
+// sourceDataDir is the data tree the test server was built from. Empty means
+// data/example, which hugo.toml mounts by default.
+var sourceDataDir string
+
+var (
+	sourceOnce      sync.Once
+	sourceSet       *fixtures.Set
+	sourceManifests map[string]*quality.Manifest
+	sourceErr       error
+)
+
+// loadSource reads the source data and its content manifests once per test
+// binary.
+func loadSource(t *testing.T) {
+	t.Helper()
+	sourceOnce.Do(func() {
+		dir := sourceDataDir
+		if dir == "" {
+			root, err := findSiteRoot()
+			if err != nil {
+				sourceErr = err
+				return
+			}
+			dir = filepath.Join(root, "data", "example")
+		}
+		if sourceSet, sourceErr = fixtures.Load(dir); sourceErr != nil {
+			return
+		}
+		sourceManifests = map[string]*quality.Manifest{}
+		for id := range sourceSet.Auxiliary {
+			m, err := quality.LoadManifest(dir, id)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				sourceErr = err
+				return
+			}
+			sourceManifests[id] = m
+		}
+	})
+	if sourceErr != nil {
+		t.Fatalf("Failed to load source data: %v", sourceErr)
+	}
+}
+
+// SourceData returns the Bible data the site under test was built from:
+// the fixtures when MICHAEL_TEST_DATA=fixtures, otherwise data/example.
+func SourceData(t *testing.T) *fixtures.Set {
+	t.Helper()
+	loadSource(t)
+	return sourceSet
+}
+
+// StrongsBible returns the ID of the source Bible with Strong's markup.
+func StrongsBible(t *testing.T) string {
+	t.Helper()
+	return sourceRole(t, "Strong's", SourceData(t).Roles.Strongs)
+}
+
+// PlainBible returns the ID of the source Bible with untagged English text.
+func PlainBible(t *testing.T) string {
+	t.Helper()
+	return sourceRole(t, "plain English", SourceData(t).Roles.Plain)
+}
+
+// CatholicBible returns the ID of the source Bible in the catholic
+// versification.
+func CatholicBible(t *testing.T) string {
+	t.Helper()
+	return sourceRole(t, "Catholic", SourceData(t).Roles.Catholic)
+}
+
+// HebrewBible returns the ID of the source Hebrew Bible.
+func HebrewBible(t *testing.T) string {
+	t.Helper()
+	return sourceRole(t, "Hebrew", SourceData(t).Roles.Hebrew)
+}
+
+// GreekBible returns the ID of the source Bible in the orthodox
+// versification.
+func GreekBible(t *testing.T) string {
+	t.Helper()
+	return sourceRole(t, "Greek", SourceData(t).Roles.Greek)
+}
+
+// sourceRole returns the Bible filling a role, failing the test when the
+// source data has none.
+func sourceRole(t *testing.T, role, id string) string {
+	t.Helper()
+	if id == "" {
+		t.Fatalf("The source data has no %s Bible", role)
+	}
+	return id
+}
+
+// SourceManifests returns the content manifests of the source data by Bible
+// ID. Bibles without one are missing, and the site builds all their
+// chapters.
+func SourceManifests(t *testing.T) map[string]*quality.Manifest {
+	t.Helper()
+	loadSource(t)
+	return sourceManifests
+}
+
+// =============================================================================
+// Generated Routes
+// =============================================================================
+
+// ChapterRoute is a chapter page and the source verses it is built from.
+type ChapterRoute struct {
+	Bible string
+	// Book is the OSIS book ID as the source spells it.
+	Book    string
+	Chapter int
+	Verses  []fixtures.Verse
+	// Status is the chapter's content manifest status, "" without one.
+	Status quality.Status
+}
+
+// URL returns the chapter page's URL on the test server.
+func (r ChapterRoute) URL() string {
+	return fmt.Sprintf("%s/bible/%s/%s/%d/", BaseURL, r.Bible, strings.ToLower(r.Book), r.Chapter)
+}
+
+func (r ChapterRoute) String() string {
+	return fmt.Sprintf("%s %s %d", r.Bible, r.Book, r.Chapter)
+}
+
+// BookRoute is a book page and the chapters it links to.
+type BookRoute struct {
+	Bible    string
+	Book     string
+	Chapters []int
+}
+
+// URL returns the book page's URL on the test server.
+func (r BookRoute) URL() string {
+	return fmt.Sprintf("%s/bible/%s/%s/", BaseURL, r.Bible, strings.ToLower(r.Book))
+}
+
+func (r BookRoute) String() string {
+	return r.Bible + " " + r.Book
+}
+
+// SiteRoutes are the book and chapter pages _content.gotmpl generates from a
+// data set, and what it leaves out.
+type SiteRoutes struct {
+	Books    []BookRoute
+	Chapters []ChapterRoute
+	// Skipped are the chapters the content manifests find without text.
+	Skipped []ChapterRoute
+	// Unbuilt are the Bibles in bibles.json with no auxiliary file.
+	Unbuilt []string
+}
+
+// PlanRoutes works out the pages _content.gotmpl generates from set: the
+// chapters with text according to the manifests, or every chapter of a
+// Bible without one. A book is generated when any of its chapters is.
+func PlanRoutes(set *fixtures.Set, manifests map[string]*quality.Manifest) *SiteRoutes {
+	routes := &SiteRoutes{}
+	for _, bible := range set.Index.Bibles {
+		aux, ok := set.Auxiliary[bible.ID]
+		if !ok {
+			routes.Unbuilt = append(routes.Unbuilt, bible.ID)
+			continue
+		}
+		manifest := manifests[bible.ID]
+		for _, book := range aux.Books {
+			route := BookRoute{Bible: bible.ID, Book: book.ID}
+			var entry *quality.Book
+			if manifest != nil {
+				entry, _ = manifest.Book(book.ID)
+			}
+			for i, ch := range book.Chapters {
+				chapter := ChapterRoute{Bible: bible.ID, Book: book.ID, Chapter: ch.Number, Verses: ch.Verses}
+				if entry != nil && i < len(entry.Chapters) {
+					chapter.Status = entry.Chapters[i].Status
+				}
+				if manifest != nil && (entry == nil || !entry.Status.HasText() || !chapter.Status.HasText()) {
+					routes.Skipped = append(routes.Skipped, chapter)
+					continue
+				}
+				route.Chapters = append(route.Chapters, ch.Number)
+				routes.Chapters = append(routes.Chapters, chapter)
+			}
+			if len(route.Chapters) > 0 {
+				routes.Books = append(routes.Books, route)
+			}
+		}
+	}
+	return routes
+}
+
+// ReportSkipped logs the chapters and Bibles the site leaves out and lists
+// them in skipped.txt in the test's artifact directory.
+func (r *SiteRoutes) ReportSkipped(t *testing.T) {
+	t.Helper()
+	var sb strings.Builder
+	for _, id := range r.Unbuilt {
+		fmt.Fprintf(&sb, "%s: no bibles_auxiliary/%s.json\n", id, id)
+	}
+	for _, ch := range r.Skipped {
+		fmt.Fprintf(&sb, "%s: %s, %d verses\n", ch, ch.Status, len(ch.Verses))
+	}
+	writeArtifact(t, filepath.Join(ArtifactDir(t), "skipped.txt"), []byte(sb.String()))
+	t.Logf("Skipped %d chapters and %d Bibles without auxiliary data; see skipped.txt", len(r.Skipped), len(r.Unbuilt))
+}
+
+// =============================================================================
+// Sampling
+// =============================================================================
+
+var (
+	crawlSample = flag.Int("crawl", 100, "number of generated chapters TestChapterCrawl visits, picked at random; 0 visits every chapter")
+	crawlSeed   = flag.Uint64("crawl-seed", 0, "seed for the -crawl sample; 0 picks one from the clock")
+)
+
+// SampleChapters returns the -crawl sample of chapters, in their original
+// order, and logs the seed that reproduces it.
+func SampleChapters(t *testing.T, chapters []ChapterRoute) []ChapterRoute {
+	t.Helper()
+	n := *crawlSample
+	if n <= 0 || n >= len(chapters) {
+		t.Logf("Visiting all %d chapters", len(chapters))
+		return chapters
+	}
+	seed := *crawlSeed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	t.Logf("Visiting %d of %d chapters; rerun with -crawl-seed=%d to repeat the sample", n, len(chapters), seed)
+	return sample(chapters, n, seed)
+}
+
+// sample picks n items at random with the given seed, keeping their order.
+func sample[T any](items []T, n int, seed uint64) []T {
+	picked := rand.New(rand.NewPCG(seed, seed)).Perm(len(items))[:n]
+	slices.Sort(picked)
+	out := make([]T, n)
+	for i, j := range picked {
+		out[i] = items[j]
+	}
+	return out
+}
+
+// =============================================================================
+// Page Checks
+// =============================================================================
+
+// fetchVersesScript fetches a page as served, before any script runs, and
+// returns its HTTP status and the number and text of each verse as JSON.
+const fetchVersesScript = `
+(async () => {
+	const res = await fetch(%q, { cache: 'no-store' });
+	if (!res.ok) return JSON.stringify({ status: res.status });
+	const doc = new DOMParser().parseFromString(await res.text(), 'text/html');
+	const verses = Array.from(doc.querySelectorAll(%q), (el) => {
+		const copy = el.cloneNode(true);
+		const sup = copy.querySelector(':scope > sup');
+		const number = sup ? sup.textContent.trim() : '';
+		if (sup) sup.remove();
+		return { verse: el.getAttribute('data-verse'), number, text: copy.textContent };
+	});
+	return JSON.stringify({ status: res.status, verses });
+})()
+`
+
+// fetchLinksScript fetches a page as served and returns its HTTP status and
+// the text of each link matching a selector as JSON.
+const fetchLinksScript = `
+(async () => {
+	const res = await fetch(%q, { cache: 'no-store' });
+	if (!res.ok) return JSON.stringify({ status: res.status });
+	const doc = new DOMParser().parseFromString(await res.text(), 'text/html');
+	const links = Array.from(doc.querySelectorAll(%q), (a) => a.textContent.trim());
+	return JSON.stringify({ status: res.status, links });
+})()
+`
+
+// servedVerse is a verse as a chapter page serves it.
+type servedVerse struct {
+	Verse  string `json:"verse"`
+	Number string `json:"number"`
+	Text   string `json:"text"`
+}
+
+// servedPage is the status and content of a fetched page.
+type servedPage struct {
+	Status int           `json:"status"`
+	Verses []servedVerse `json:"verses"`
+	Links  []string      `json:"links"`
+}
+
+// maxChapterProblems caps the problems reported per chapter, so a chapter
+// that is off by one verse does not report every verse after it.
+const maxChapterProblems = 5
+
+// fetchServed evaluates a fetch script for url in b and decodes the result.
+func fetchServed(b *e2e.Browser, script, url, selector string) (*servedPage, error) {
+	raw, err := b.Evaluate(fmt.Sprintf(script, url, selector))
+	if err != nil {
+		return nil, err
+	}
+	s, _ := raw.(string)
+	var page servedPage
+	if err := json.Unmarshal([]byte(s), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// CheckChapter fetches a chapter page from b's origin and returns how its
+// verses differ from the source: count, numbering and text, compared after
+// NormalizeVerse. It returns nil when the page matches.
+func CheckChapter(t *testing.T, b *e2e.Browser, route ChapterRoute) []string {
+	t.Helper()
+	page, err := fetchServed(b, fetchVersesScript, route.URL(), singleVerse)
+	if err != nil {
+		t.Fatalf("Failed to fetch %s: %v", route, err)
+	}
+	if page.Status != 200 {
+		return []string{fmt.Sprintf("%s: HTTP %d from %s", route, page.Status, route.URL())}
+	}
+
+	var problems []string
+	if len(page.Verses) != len(route.Verses) {
+		problems = append(problems, fmt.Sprintf("%s: %d verses, source has %d", route, len(page.Verses), len(route.Verses)))
+	}
+	for i := 0; i < min(len(page.Verses), len(route.Verses)); i++ {
+		got, want := page.Verses[i], route.Verses[i]
+		number := strconv.Itoa(want.Number)
+		switch {
+		case got.Verse != number || got.Number != number:
+			problems = append(problems, fmt.Sprintf("%s: verse %d is numbered %q (data-verse %q), source has %s",
+				route, i+1, got.Number, got.Verse, number))
+		case NormalizeVerse(got.Text) != NormalizeVerse(SourceVerseText(want.Text)):
+			problems = append(problems, fmt.Sprintf("%s:%s text differs: %s", route, number,
+				textDifference(NormalizeVerse(got.Text), NormalizeVerse(SourceVerseText(want.Text)))))
+		}
+	}
+	if len(problems) > maxChapterProblems {
+		more := len(problems) - maxChapterProblems
+		problems = append(problems[:maxChapterProblems], fmt.Sprintf("%s: and %d more", route, more))
+	}
+	return problems
+}
+
+// CheckBook fetches a book page from b's origin and returns how its chapter
+// links differ from the chapters the source generates.
+func CheckBook(t *testing.T, b *e2e.Browser, route BookRoute) []string {
+	t.Helper()
+	page, err := fetchServed(b, fetchLinksScript, route.URL(), selectors.ClassChapterLink)
+	if err != nil {
+		t.Fatalf("Failed to fetch %s: %v", route, err)
+	}
+	if page.Status != 200 {
+		return []string{fmt.Sprintf("%s: HTTP %d from %s", route, page.Status, route.URL())}
+	}
+	want := make([]string, len(route.Chapters))
+	for i, n := range route.Chapters {
+		want[i] = strconv.Itoa(n)
+	}
+	if !slices.Equal(page.Links, want) {
+		return []string{fmt.Sprintf("%s: links chapters %v, source generates %v", route, page.Links, want)}
+	}
+	return nil
+}
+
+// ExpectNotGenerated asserts that a skipped chapter has no page.
+func ExpectNotGenerated(t *testing.T, b *e2e.Browser, route ChapterRoute) {
+	t.Helper()
+	page, err := fetchServed(b, fetchVersesScript, route.URL(), singleVerse)
+	if err != nil {
+		t.Fatalf("Failed to fetch %s: %v", route, err)
+	}
+	if page.Status != 404 {
+		t.Errorf("%s fails the content heuristic but %s returned HTTP %d", route, route.URL(), page.Status)
+	}
+}
+
+// =============================================================================
+// Text Normalization
+// =============================================================================
+
+// markupTag matches an HTML or OSIS tag in source verse text.
+var markupTag = regexp.MustCompile(`<[^>]*>`)
+
+// typography folds the punctuation Hugo's typographer substitutes, and the
+// same characters typed directly, to ASCII.
+var typography = strings.NewReplacer(
+	"‘", "'", "’", "'", "“", `"`, "”", `"`,
+	"…", "...", "–", "--", "—", "---",
+)
+
+// SourceVerseText returns the text a browser shows for source verse markup:
+// tags removed and entities decoded.
+func SourceVerseText(text string) string {
+	return html.UnescapeString(markupTag.ReplaceAllString(text, ""))
+}
+
+// NormalizeVerse folds typographic punctuation and collapses whitespace,
+// including no-break spaces, so rendered text compares equal to its source.
+func NormalizeVerse(text string) string {
+	return strings.Join(strings.Fields(typography.Replace(text)), " ")
+}
+
+// textDifference describes where got and want first differ, with a little
+// context either side.
+func textDifference(got, want string) string {
+	g, w := []rune(got), []rune(want)
+	i := 0
+	for i < len(g) && i < len(w) && g[i] == w[i] {
+		i++
+	}
+	from := max(0, i-20)
+	return fmt.Sprintf("page has %q, source has %q", string(g[from:min(len(g), i+20)]), string(w[from:min(len(w), i+20)]))
+}
@@ -0,0 +1,160 @@
+package helpers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/emulation"
+)
+
+// =============================================================================
+// Device Profiles
+// =============================================================================
+
+// Device is a browser profile a test can run under.
+type Device struct {
+	// Name identifies the device in subtest names, skips and expectations.
+	Name          string
+	Width, Height int
+	// Scale is the device pixel ratio. Zero leaves Chrome's default of 1.
+	Scale float64
+	// Touch enables touch events.
+	Touch bool
+	// Mobile emulates a mobile browser, which honours the viewport meta tag.
+	Mobile bool
+}
+
+// Device profiles. IPhoneSE is the profile NewMobileBrowser has always used,
+// and Narrow is the 320 CSS pixel width WCAG 1.4.10 requires content to
+// reflow into without horizontal scrolling.
+var (
+	Desktop         = Device{Name: "desktop", Width: 1920, Height: 1080}
+	IPhoneSE        = Device{Name: "iphone-se", Width: 375, Height: 667, Touch: true}
+	TabletPortrait  = Device{Name: "tablet-portrait", Width: 768, Height: 1024, Touch: true, Mobile: true}
+	TabletLandscape = Device{Name: "tablet-landscape", Width: 1024, Height: 768, Touch: true, Mobile: true}
+	HighDPR         = Device{Name: "high-dpr", Width: 1440, Height: 900, Scale: 2}
+	Narrow          = Device{Name: "narrow-320", Width: 320, Height: 640}
+)
+
+// Devices is the full device matrix, in the order subtests run.
+var Devices = []Device{Desktop, IPhoneSE, TabletPortrait, TabletLandscape, HighDPR, Narrow}
+
+// NewDeviceBrowser returns a browser emulating d for the rest of the test,
+// from the shared pool when RunTests set one up. If the test fails, the
+// browser's state is saved as described at RecordFailureArtifacts.
+func NewDeviceBrowser(t *testing.T, d Device) *e2e.Browser {
+	t.Helper()
+	var browser *e2e.Browser
+	if sharedPool != nil {
+		browser = sharedPool.Acquire(t, d)
+	} else {
+		var err error
+		if browser, err = launchBrowser(d); err != nil {
+			t.Fatalf("Failed to create %s browser: %v", d.Name, err)
+		}
+		t.Cleanup(func() { browser.Close() })
+	}
+	// Registered last so they run first, before the browser is reset or closed
+	RecordFailureArtifacts(t, browser)
+	CollectJSCoverage(t, browser)
+	return browser
+}
+
+// launchBrowser starts a headless Chrome emulating d.
+func launchBrowser(d Device) (*e2e.Browser, error) {
+	browser, err := e2e.NewBrowser(e2e.BrowserOptions{
+		Headless: true,
+		Viewport: e2e.Viewport{Width: d.Width, Height: d.Height},
+		Timeout:  30 * time.Second,
+		Touch:    d.Touch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if d.Scale != 0 || d.Mobile {
+		scale := d.Scale
+		if scale == 0 {
+			scale = 1
+		}
+		override := emulation.SetDeviceMetricsOverride(int64(d.Width), int64(d.Height), scale, d.Mobile)
+		if err := RunCDP(browser, override); err != nil {
+			browser.Close()
+			return nil, fmt.Errorf("emulate %s: %w", d.Name, err)
+		}
+	}
+	return browser, nil
+}
+
+// =============================================================================
+// Device Matrix
+// =============================================================================
+
+// DeviceMatrix runs a test body once per device, each as a subtest named
+// after the device. E is whatever the body needs to know about how a device
+// should behave; use struct{} when every device behaves the same.
+//
+//	helpers.DeviceMatrix[float64]{
+//		Skip:    map[string]string{"narrow-320": "covered by the reflow tests"},
+//		Default: 24,
+//		Expect:  map[string]float64{"iphone-se": 44},
+//	}.Run(t, func(t *testing.T, b *e2e.Browser, d helpers.Device, minSize float64) { ... })
+type DeviceMatrix[E any] struct {
+	// Devices to run on. Nil runs on Devices.
+	Devices []Device
+	// Skip maps device names to why the test does not apply to them.
+	Skip map[string]string
+	// Expect maps device names to their expectation.
+	Expect map[string]E
+	// Default is the expectation for devices missing from Expect.
+	Default E
+}
+
+// Run runs body on each device with a fresh browser. Skip and Expect entries
+// that name no device in the matrix fail the test, so typos don't silently
+// drop coverage.
+func (m DeviceMatrix[E]) Run(t *testing.T, body func(t *testing.T, b *e2e.Browser, d Device, want E)) {
+	t.Helper()
+	devices := m.Devices
+	if devices == nil {
+		devices = Devices
+	}
+
+	known := make(map[string]bool, len(devices))
+	for _, d := range devices {
+		known[d.Name] = true
+	}
+	for name := range m.Skip {
+		if !known[name] {
+			t.Fatalf("Skip names unknown device %q", name)
+		}
+	}
+	for name := range m.Expect {
+		if !known[name] {
+			t.Fatalf("Expect names unknown device %q", name)
+		}
+	}
+
+	for _, d := range devices {
+		t.Run(d.Name, func(t *testing.T) {
+			if reason, ok := m.Skip[d.Name]; ok {
+				t.Skip(reason)
+			}
+			want, ok := m.Expect[d.Name]
+			if !ok {
+				want = m.Default
+			}
+			body(t, NewDeviceBrowser(t, d), d, want)
+		})
+	}
+}
+
+// RunOnDevices runs body on every device of the matrix with no per-device
+// skips or expectations.
+func RunOnDevices(t *testing.T, body func(t *testing.T, b *e2e.Browser, d Device)) {
+	t.Helper()
+	DeviceMatrix[struct{}]{}.Run(t, func(t *testing.T, b *e2e.Browser, d Device, _ struct{}) {
+		body(t, b, d)
+	})
+}
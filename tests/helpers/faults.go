@@ -0,0 +1,277 @@
+package helpers
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// =============================================================================
+// Fault Injection
+// =============================================================================
+
+// Fault describes how the test server misbehaves for requests whose path
+// matches Pattern. Effects combine: a fault with Latency and Status waits,
+// then fails. The service worker and BibleAPI fetch from the site's own
+// origin, so faults are applied inside the in-process server rather than by
+// a separate proxy.
+type Fault struct {
+	// Pattern is a path.Match pattern over the request path, such as
+	// "/bible/asv/gen/1/" or "/bible/asv/*/*/" for every chapter of a Bible.
+	Pattern string
+	// Latency delays the response.
+	Latency time.Duration
+	// BytesPerSecond throttles the response body. Zero is unlimited.
+	BytesPerSecond int
+	// Status replaces the response with an empty error response, such as 503.
+	Status int
+	// Reset drops the connection without a response, so fetch rejects.
+	Reset bool
+	// TruncateAfter closes the connection after this many body bytes while
+	// the Content-Length still promises the whole body. Zero disables it.
+	TruncateAfter int
+	// Times limits the fault to the first Times matching requests, after
+	// which they are served normally. Zero faults every request.
+	Times int
+}
+
+// throttleInterval is how often a throttled response writes a chunk.
+const throttleInterval = 100 * time.Millisecond
+
+// FaultInjector is middleware that applies the active faults to requests
+// and passes everything else through.
+type FaultInjector struct {
+	next http.Handler
+
+	mu    sync.Mutex
+	rules []*faultRule
+}
+
+// faultRule is an active fault and how many requests it has hit.
+type faultRule struct {
+	Fault
+	hits int
+}
+
+// Faults is a set of faults added together; Remove lifts them again.
+type Faults struct {
+	injector *FaultInjector
+	rules    []*faultRule
+}
+
+// NewFaultInjector wraps next with fault injection. It has no effect until
+// faults are added.
+func NewFaultInjector(next http.Handler) *FaultInjector {
+	return &FaultInjector{next: next}
+}
+
+// Add activates faults and returns a handle for removing them. When several
+// faults match a request, the first added one that has not used up its Times
+// applies.
+func (f *FaultInjector) Add(faults ...Fault) (*Faults, error) {
+	set := &Faults{injector: f}
+	for _, fault := range faults {
+		if _, err := path.Match(fault.Pattern, "/"); err != nil {
+			return nil, fmt.Errorf("invalid fault pattern %q: %w", fault.Pattern, err)
+		}
+		set.rules = append(set.rules, &faultRule{Fault: fault})
+	}
+	f.mu.Lock()
+	f.rules = append(f.rules, set.rules...)
+	f.mu.Unlock()
+	return set, nil
+}
+
+// Hits returns how many requests the set's faults have been applied to.
+func (s *Faults) Hits() int {
+	s.injector.mu.Lock()
+	defer s.injector.mu.Unlock()
+	n := 0
+	for _, r := range s.rules {
+		n += r.hits
+	}
+	return n
+}
+
+// Remove deactivates the set's faults. Requests already being faulted finish.
+func (s *Faults) Remove() {
+	f := s.injector
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	kept := f.rules[:0]
+	for _, r := range f.rules {
+		if !containsRule(s.rules, r) {
+			kept = append(kept, r)
+		}
+	}
+	f.rules = kept
+}
+
+// containsRule reports whether rules holds r.
+func containsRule(rules []*faultRule, r *faultRule) bool {
+	for _, candidate := range rules {
+		if candidate == r {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the fault for a request path and counts the hit, or false
+// when the request should be served normally.
+func (f *FaultInjector) match(p string) (Fault, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.rules {
+		if r.Times > 0 && r.hits >= r.Times {
+			continue
+		}
+		if ok, _ := path.Match(r.Pattern, p); ok {
+			r.hits++
+			return r.Fault, true
+		}
+	}
+	return Fault{}, false
+}
+
+// ServeHTTP applies the first matching fault, if any, then serves the request.
+func (f *FaultInjector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fault, ok := f.match(r.URL.Path)
+	if !ok {
+		f.next.ServeHTTP(w, r)
+		return
+	}
+
+	if fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch {
+	case fault.Reset:
+		resetConnection(w)
+	case fault.Status != 0:
+		w.Header().Set("Cache-Control", "no-store")
+		http.Error(w, http.StatusText(fault.Status), fault.Status)
+	case fault.TruncateAfter > 0:
+		f.serveTruncated(w, r, fault.TruncateAfter)
+	case fault.BytesPerSecond > 0:
+		f.next.ServeHTTP(&throttledWriter{ResponseWriter: w, r: r, rate: fault.BytesPerSecond}, r)
+	default:
+		f.next.ServeHTTP(w, r)
+	}
+}
+
+// resetConnection closes the client connection with an RST instead of a
+// response.
+func resetConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
+
+// serveTruncated writes the response headers with the full Content-Length,
+// then only the first n bytes of the body, and closes the connection.
+func (f *FaultInjector) serveTruncated(w http.ResponseWriter, r *http.Request, n int) {
+	rec := httptest.NewRecorder()
+	f.next.ServeHTTP(rec, r)
+	body := rec.Body.Bytes()
+
+	header := w.Header().Clone()
+	for name, values := range rec.Header() {
+		header[name] = values
+	}
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	header.Set("Cache-Control", "no-store")
+	header.Del("Transfer-Encoding")
+	if n < len(body) {
+		body = body[:n]
+	}
+
+	conn, buf, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	defer conn.Close()
+	writeRawResponse(buf.Writer, rec.Code, header, body)
+}
+
+// writeRawResponse writes an HTTP/1.1 response as-is, without checking the
+// body against the headers.
+func writeRawResponse(w *bufio.Writer, status int, header http.Header, body []byte) {
+	fmt.Fprintf(w, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	header.Write(w)
+	w.WriteString("\r\n")
+	w.Write(body)
+	w.Flush()
+}
+
+// throttledWriter writes the body in flushed chunks paced to rate bytes per
+// second, stopping early if the client goes away.
+type throttledWriter struct {
+	http.ResponseWriter
+	r    *http.Request
+	rate int
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	chunk := max(1, int(time.Duration(t.rate)*throttleInterval/time.Second))
+	rc := http.NewResponseController(t.ResponseWriter)
+	written := 0
+	for written < len(p) {
+		end := min(written+chunk, len(p))
+		n, err := t.ResponseWriter.Write(p[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		rc.Flush()
+		select {
+		case <-time.After(throttleInterval):
+		case <-t.r.Context().Done():
+			return written, t.r.Context().Err()
+		}
+	}
+	return written, nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (t *throttledWriter) Unwrap() http.ResponseWriter { return t.ResponseWriter }
+
+// serverFaults is the fault injector of the server RunTests started, or nil
+// when the tests run against an external BASE_URL.
+var serverFaults *FaultInjector
+
+// InjectFaults activates faults on the test server for the rest of the test.
+// It skips the test when running against an external BASE_URL, where the
+// server cannot be made to misbehave.
+func InjectFaults(t *testing.T, faults ...Fault) *Faults {
+	t.Helper()
+	if serverFaults == nil {
+		t.Skip("Fault injection needs the in-process test server; unset BASE_URL")
+	}
+	if isParallel(t) {
+		t.Fatal("Fault injection affects every test using the server; don't mark the test Parallel")
+	}
+	set, err := serverFaults.Add(faults...)
+	if err != nil {
+		t.Fatalf("Failed to inject faults: %v", err)
+	}
+	t.Cleanup(set.Remove)
+	return set
+}
@@ -0,0 +1,359 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/accessibility"
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+)
+
+// =============================================================================
+// Focus Order
+// =============================================================================
+
+// focusLimit bounds how many Tab presses a recording makes, so a page that
+// never lets focus leave still finishes.
+const focusLimit = 1000
+
+// focusStartScript puts a focused, unfocusable-by-Tab sentinel at the start
+// or end of the body, so the next Tab or Shift+Tab begins at that end of the
+// page, and forgets the elements seen by earlier recordings.
+const focusStartScript = `
+(() => {
+	document.getElementById('michael-focus-start')?.remove();
+	const start = document.createElement('span');
+	start.id = 'michael-focus-start';
+	start.tabIndex = -1;
+	document.body[%t ? 'prepend' : 'append'](start);
+	start.focus();
+	window.__michaelFocusSeen = new Map();
+	return document.activeElement === start;
+})()
+`
+
+// focusStopScript describes the focused element as JSON: whether focus left
+// the page, the index it was first seen at, a selector-like path and why it
+// cannot be seen, if it cannot.
+const focusStopScript = `
+(() => {
+	const el = document.activeElement;
+	document.getElementById('michael-focus-start')?.remove();
+	if (!el || el === document.body || el === document.documentElement || el.id === 'michael-focus-start') {
+		return JSON.stringify({ left: true });
+	}
+	const seen = window.__michaelFocusSeen;
+	const first = seen.has(el) ? seen.get(el) : -1;
+	if (first < 0) seen.set(el, seen.size);
+
+	const describe = (el) => {
+		let s = el.tagName.toLowerCase();
+		if (el.id) return s + '#' + el.id;
+		if (el.classList.length) s += '.' + Array.from(el.classList).join('.');
+		const parent = el.parentElement;
+		return parent && parent !== document.body ? describe(parent) + ' > ' + s : s;
+	};
+
+	let invisible = '';
+	const rect = el.getBoundingClientRect();
+	if (!el.checkVisibility({ opacityProperty: true, visibilityProperty: true })) {
+		invisible = 'hidden by CSS';
+	} else if (rect.width <= 1 || rect.height <= 1) {
+		invisible = Math.round(rect.width) + 'x' + Math.round(rect.height) + 'px';
+	} else if (rect.bottom <= 0 || rect.right <= 0 || rect.top >= innerHeight || rect.left >= innerWidth) {
+		invisible = 'outside the viewport';
+	}
+	return JSON.stringify({ left: false, first, selector: describe(el), invisible });
+})()
+`
+
+// FocusStop is an element Tab focused.
+type FocusStop struct {
+	// Selector is a CSS-like path to the element.
+	Selector string
+	// Role and Name are the element's computed accessible role and name.
+	Role string
+	Name string
+	// Invisible says why the focused element could not be seen, if it
+	// could not: hidden by CSS, collapsed to a pixel or outside the viewport.
+	Invisible string
+}
+
+// String formats the stop as it appears in focus order golden files.
+func (s FocusStop) String() string {
+	return fmt.Sprintf("%s %q %s", s.Role, s.Name, s.Selector)
+}
+
+// FocusOrder is the sequence of elements Tab visits on a page.
+type FocusOrder struct {
+	// Forward is the order Tab visits from the top of the page.
+	Forward []FocusStop
+	// Backward is the order Shift+Tab visits from the bottom of the page.
+	Backward []FocusStop
+	// Traps describe where focus stopped moving or cycled before leaving the
+	// page.
+	Traps []string
+}
+
+// RecordFocusOrder tabs through the current page forwards and then backwards
+// until focus leaves it, recording every element focused.
+func RecordFocusOrder(t *testing.T, b *e2e.Browser) FocusOrder {
+	t.Helper()
+	// Focus scrolls the element into view; without smooth scrolling it is
+	// in view by the time it is measured
+	if _, err := b.Evaluate(freezeStyles); err != nil {
+		t.Fatalf("Failed to freeze page styles: %v", err)
+	}
+	var order FocusOrder
+	var trap string
+	order.Forward, trap = recordFocus(t, b, true)
+	if trap != "" {
+		order.Traps = append(order.Traps, "Tab: "+trap)
+	}
+	order.Backward, trap = recordFocus(t, b, false)
+	if trap != "" {
+		order.Traps = append(order.Traps, "Shift+Tab: "+trap)
+	}
+	return order
+}
+
+// recordFocus presses Tab, or Shift+Tab when not forward, from one end of
+// the page until focus leaves it or a trap is found.
+func recordFocus(t *testing.T, b *e2e.Browser, forward bool) ([]FocusStop, string) {
+	t.Helper()
+	end, opts := "top", []chromedp.KeyOption(nil)
+	if !forward {
+		end, opts = "bottom", []chromedp.KeyOption{chromedp.KeyModifiers(input.ModifierShift)}
+	}
+	raw, err := b.Evaluate(fmt.Sprintf(focusStartScript, forward))
+	if err != nil || raw != true {
+		t.Fatalf("Failed to reset focus to the %s of the page: %v", end, err)
+	}
+
+	var stops []FocusStop
+	for range focusLimit {
+		if err := RunCDP(b, chromedp.KeyEvent(kb.Tab, opts...)); err != nil {
+			t.Fatalf("Failed to press Tab: %v", err)
+		}
+		raw, err := b.Evaluate(focusStopScript)
+		if err != nil {
+			t.Fatalf("Failed to read the focused element: %v", err)
+		}
+		s, _ := raw.(string)
+		var stop struct {
+			Left      bool   `json:"left"`
+			First     int    `json:"first"`
+			Selector  string `json:"selector"`
+			Invisible string `json:"invisible"`
+		}
+		if err := json.Unmarshal([]byte(s), &stop); err != nil {
+			t.Fatalf("Failed to decode the focused element: %v", err)
+		}
+		switch {
+		case stop.Left:
+			return stops, ""
+		case stop.First == 0 && len(stops) > 1:
+			// Focus wrapped around without leaving the document
+			return stops, ""
+		case stop.First >= 0 && stop.First == len(stops)-1:
+			return stops, "focus stays on " + stops[stop.First].String()
+		case stop.First >= 0:
+			return stops, fmt.Sprintf("focus cycles from %s back to %s", stops[len(stops)-1], stops[stop.First])
+		}
+		role, name := focusedAXName(t, b)
+		stops = append(stops, FocusStop{Selector: stop.Selector, Role: role, Name: name, Invisible: stop.Invisible})
+	}
+	return stops, fmt.Sprintf("focus did not leave the page after %d presses", focusLimit)
+}
+
+// focusedAXName returns the computed accessible role and name of the focused
+// element.
+func focusedAXName(t *testing.T, b *e2e.Browser) (role, name string) {
+	t.Helper()
+	var nodes []*accessibility.Node
+	err := RunCDP(b, chromedp.ActionFunc(func(ctx context.Context) error {
+		obj, _, err := runtime.Evaluate("document.activeElement").WithObjectGroup("michael-focus").Do(ctx)
+		if err != nil {
+			return err
+		}
+		defer runtime.ReleaseObjectGroup("michael-focus").Do(ctx)
+		nodes, err = accessibility.GetPartialAXTree().WithObjectID(obj.ObjectID).WithFetchRelatives(false).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("Failed to read the accessible name of the focused element: %v", err)
+	}
+	value := func(v *accessibility.Value) string {
+		var s string
+		if v != nil {
+			json.Unmarshal([]byte(v.Value), &s)
+		}
+		return s
+	}
+	if len(nodes) > 0 {
+		return value(nodes[0].Role), value(nodes[0].Name)
+	}
+	return "", ""
+}
+
+// Problems lists the traps, the focused elements that could not be seen and
+// where Shift+Tab does not retrace Tab.
+func (o FocusOrder) Problems() []string {
+	problems := slices.Clone(o.Traps)
+	for i, s := range o.Forward {
+		if s.Invisible != "" {
+			problems = append(problems, fmt.Sprintf("stop %d is focused but invisible (%s): %s", i+1, s.Invisible, s))
+		}
+	}
+	if len(o.Traps) == 0 {
+		backward := slices.Clone(o.Backward)
+		slices.Reverse(backward)
+		if i := firstDifference(focusLines(o.Forward), focusLines(backward)); i >= 0 {
+			problems = append(problems, fmt.Sprintf("Shift+Tab does not retrace Tab at stop %d of %d: Tab reaches %s, Shift+Tab %s",
+				i+1, len(o.Forward), lineAt(focusLines(o.Forward), i), lineAt(focusLines(backward), i)))
+		}
+	}
+	return problems
+}
+
+// focusLines formats stops one per line, as in golden files.
+func focusLines(stops []FocusStop) []string {
+	lines := make([]string, len(stops))
+	for i, s := range stops {
+		lines[i] = s.String()
+	}
+	return lines
+}
+
+// firstDifference returns the first index at which a and b differ, or -1.
+func firstDifference(a, b []string) int {
+	for i := range max(len(a), len(b)) {
+		if i >= len(a) || i >= len(b) || a[i] != b[i] {
+			return i
+		}
+	}
+	return -1
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return "(the end of the page)"
+}
+
+// MatchFocusGolden compares the forward focus order against
+// tests/golden/focus-<name>.txt, one stop per line. A missing golden fails
+// the test. Run with -update to bless it, or re-bless it after an intended
+// change.
+func MatchFocusGolden(t *testing.T, name string, order FocusOrder) {
+	t.Helper()
+	lines := focusLines(order.Forward)
+	actual := []byte(strings.Join(lines, "\n") + "\n")
+
+	goldenPath, err := goldenFile("focus-" + name + ".txt")
+	if err != nil {
+		t.Fatalf("Failed to locate golden directory: %v", err)
+	}
+
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, actual, 0o644); err != nil {
+			t.Fatalf("Failed to update golden focus order %s: %v", goldenPath, err)
+		}
+		t.Logf("Updated golden focus order %s", goldenPath)
+		return
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if os.IsNotExist(err) {
+		writeArtifact(t, filepath.Join(ArtifactDir(t), "focus-"+name+"-actual.txt"), actual)
+		t.Fatalf("No golden focus order %s; run with -update to create it", goldenPath)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read golden focus order: %v", err)
+	}
+	if bytes.Equal(expected, actual) {
+		return
+	}
+
+	want := strings.Split(strings.TrimSuffix(string(expected), "\n"), "\n")
+	i := firstDifference(want, lines)
+	dir := ArtifactDir(t)
+	writeArtifact(t, filepath.Join(dir, "focus-"+name+"-actual.txt"), actual)
+	t.Errorf("Focus order of %s differs from golden at stop %d: expected %s, got %s; actual order in %s",
+		name, i+1, lineAt(want, i), lineAt(lines, i), dir)
+}
+
+// =============================================================================
+// Focus Return
+// =============================================================================
+
+// focusTriggerScript focuses the first element matching the selector and
+// remembers it.
+const focusTriggerScript = `
+(() => {
+	const el = document.querySelector(%q);
+	if (!el) return false;
+	el.focus();
+	window.__michaelFocusTrigger = el;
+	return document.activeElement === el;
+})()
+`
+
+// focusReturnedScript reports whether the remembered trigger has focus, or
+// else what does.
+const focusReturnedScript = `
+(() => {
+	const el = document.activeElement;
+	if (el === window.__michaelFocusTrigger) return '';
+	if (!el || el === document.body) return 'the page';
+	return el.tagName.toLowerCase() + (el.id ? '#' + el.id : '') + (el.className ? '.' + String(el.className).trim().split(/\s+/).join('.') : '');
+})()
+`
+
+// ExpectFocusReturn opens popup by pressing Enter on the first trigger,
+// presses keys inside it, closes it with Escape and asserts that focus went
+// back to the trigger.
+func ExpectFocusReturn(t *testing.T, b *e2e.Browser, trigger, popup string, keys ...string) {
+	t.Helper()
+	if err := b.WaitFor(trigger); err != nil {
+		t.Fatalf("Trigger %s not found: %v", trigger, err)
+	}
+	raw, err := b.Evaluate(fmt.Sprintf(focusTriggerScript, trigger))
+	if err != nil || raw != true {
+		t.Fatalf("Failed to focus %s: %v", trigger, err)
+	}
+	if err := b.Press("Enter"); err != nil {
+		t.Fatalf("Failed to press Enter: %v", err)
+	}
+	if err := b.WaitForVisible(popup); err != nil {
+		t.Fatalf("%s did not open from %s: %v", popup, trigger, err)
+	}
+	for _, key := range append(keys, "Escape") {
+		if err := b.Press(key); err != nil {
+			t.Fatalf("Failed to press %s: %v", key, err)
+		}
+	}
+	if err := b.WaitForHidden(popup); err != nil {
+		t.Fatalf("%s did not close on Escape: %v", popup, err)
+	}
+
+	raw, err = b.Evaluate(focusReturnedScript)
+	if err != nil {
+		t.Fatalf("Failed to read the focused element: %v", err)
+	}
+	if s, _ := raw.(string); s != "" {
+		t.Errorf("Closing %s left focus on %s instead of %s", popup, s, trigger)
+	}
+}
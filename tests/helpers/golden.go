@@ -0,0 +1,260 @@
+package helpers
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Golden-Image Visual Regression
+// =============================================================================
+
+// updateGolden re-blesses golden images instead of comparing against them:
+//
+//	go test ./regression/ -run TestVisual -update
+var updateGolden = flag.Bool("update", false, "overwrite golden images in tests/golden with the current screenshots")
+
+// freezeStyles stops animations, transitions and the text caret so that two
+// captures of the same page are pixel-identical.
+const freezeStyles = `
+	(() => {
+		if (document.getElementById('golden-freeze')) return true;
+		const style = document.createElement('style');
+		style.id = 'golden-freeze';
+		style.textContent = '*, *::before, *::after { animation: none !important; transition: none !important; caret-color: transparent !important; scroll-behavior: auto !important; }';
+		document.head.appendChild(style);
+		window.scrollTo(0, 0);
+		return true;
+	})()
+`
+
+// GoldenOptions tunes how strictly a screenshot must match its golden image.
+type GoldenOptions struct {
+	// Threshold is the per-pixel perceptual color distance (0-1) above which a
+	// pixel counts as different. Defaults to 0.1.
+	Threshold float64
+	// MaxDiffRatio is the fraction of differing pixels tolerated before the
+	// match fails. Defaults to 0.001 (0.1%).
+	MaxDiffRatio float64
+}
+
+// DefaultGoldenOptions absorbs font anti-aliasing noise but catches layout and color changes.
+var DefaultGoldenOptions = GoldenOptions{Threshold: 0.1, MaxDiffRatio: 0.001}
+
+// MatchGolden captures the viewport and compares it against tests/golden/<name>.png.
+// On mismatch it writes the actual and diff images to the test's artifact directory.
+func MatchGolden(t *testing.T, b *e2e.Browser, name string) {
+	t.Helper()
+	MatchGoldenWith(t, b, name, DefaultGoldenOptions)
+}
+
+// MatchGoldenWith is MatchGolden with explicit tolerances.
+func MatchGoldenWith(t *testing.T, b *e2e.Browser, name string, opts GoldenOptions) {
+	t.Helper()
+	actual := CaptureViewport(t, b)
+
+	goldenPath, err := goldenFile(name + ".png")
+	if err != nil {
+		t.Fatalf("Failed to locate golden directory: %v", err)
+	}
+
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, actual, 0o644); err != nil {
+			t.Fatalf("Failed to update golden image %s: %v", goldenPath, err)
+		}
+		t.Logf("Updated golden image %s", goldenPath)
+		return
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if os.IsNotExist(err) {
+		writeArtifact(t, filepath.Join(ArtifactDir(t), name+"-actual.png"), actual)
+		t.Fatalf("No golden image %s; run with -update to create it", goldenPath)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read golden image: %v", err)
+	}
+
+	result, err := ComparePNG(expected, actual, opts)
+	if err != nil {
+		t.Fatalf("Failed to compare %s: %v", name, err)
+	}
+	if result.Ratio <= opts.MaxDiffRatio {
+		return
+	}
+
+	dir := ArtifactDir(t)
+	writeArtifact(t, filepath.Join(dir, name+"-actual.png"), actual)
+	if result.Diff != nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, result.Diff); err == nil {
+			writeArtifact(t, filepath.Join(dir, name+"-diff.png"), buf.Bytes())
+		}
+	}
+	t.Errorf("Screenshot %s differs from golden: %s (%.3f%% > %.3f%%); artifacts in %s",
+		name, result.Reason, result.Ratio*100, opts.MaxDiffRatio*100, dir)
+}
+
+// CaptureViewport freezes animations and returns a PNG of the current viewport.
+func CaptureViewport(t *testing.T, b *e2e.Browser) []byte {
+	t.Helper()
+	if _, err := b.Evaluate(freezeStyles); err != nil {
+		t.Fatalf("Failed to freeze page styles: %v", err)
+	}
+	var buf []byte
+	if err := RunCDP(b, chromedp.CaptureScreenshot(&buf)); err != nil {
+		t.Fatalf("Failed to capture screenshot: %v", err)
+	}
+	return buf
+}
+
+// DiffResult describes how two images differ.
+type DiffResult struct {
+	// Ratio is the fraction of pixels that differ (1 when sizes differ).
+	Ratio float64
+	// Reason summarizes the difference for test output.
+	Reason string
+	// Diff highlights differing pixels in red over a faded copy of the expected image.
+	Diff *image.RGBA
+}
+
+// ComparePNG perceptually compares two PNG images using a YIQ color distance.
+func ComparePNG(expected, actual []byte, opts GoldenOptions) (DiffResult, error) {
+	want, err := png.Decode(bytes.NewReader(expected))
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("decode golden: %w", err)
+	}
+	got, err := png.Decode(bytes.NewReader(actual))
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("decode screenshot: %w", err)
+	}
+
+	wb, gb := want.Bounds(), got.Bounds()
+	if wb.Dx() != gb.Dx() || wb.Dy() != gb.Dy() {
+		return DiffResult{
+			Ratio:  1,
+			Reason: fmt.Sprintf("size %dx%d, golden is %dx%d", gb.Dx(), gb.Dy(), wb.Dx(), wb.Dy()),
+		}, nil
+	}
+
+	// pixelmatch scales the threshold against the largest possible YIQ delta
+	maxDelta := 35215 * opts.Threshold * opts.Threshold
+	diff := image.NewRGBA(image.Rect(0, 0, wb.Dx(), wb.Dy()))
+	differing := 0
+	for y := 0; y < wb.Dy(); y++ {
+		for x := 0; x < wb.Dx(); x++ {
+			a := want.At(wb.Min.X+x, wb.Min.Y+y)
+			c := got.At(gb.Min.X+x, gb.Min.Y+y)
+			if yiqDelta(a, c) > maxDelta {
+				differing++
+				diff.Set(x, y, color.RGBA{R: 255, A: 255})
+				continue
+			}
+			gray := uint8(255 - (255-luma(a))/10)
+			diff.Set(x, y, color.RGBA{R: gray, G: gray, B: gray, A: 255})
+		}
+	}
+
+	ratio := float64(differing) / float64(wb.Dx()*wb.Dy())
+	return DiffResult{
+		Ratio:  ratio,
+		Reason: fmt.Sprintf("%d pixels differ", differing),
+		Diff:   diff,
+	}, nil
+}
+
+// yiqDelta is the squared perceptual distance between two colors, blended onto white.
+func yiqDelta(a, b color.Color) float64 {
+	r1, g1, b1 := blendWhite(a)
+	r2, g2, b2 := blendWhite(b)
+	y := rgb2y(r1, g1, b1) - rgb2y(r2, g2, b2)
+	i := rgb2i(r1, g1, b1) - rgb2i(r2, g2, b2)
+	q := rgb2q(r1, g1, b1) - rgb2q(r2, g2, b2)
+	return 0.5053*y*y + 0.299*i*i + 0.1957*q*q
+}
+
+// blendWhite composites a color onto a white background, returning 0-255 channels.
+func blendWhite(c color.Color) (float64, float64, float64) {
+	r, g, b, a := c.RGBA()
+	alpha := float64(a) / 0xffff
+	blend := func(v uint32) float64 {
+		return 255 + (float64(v)/0xffff*255-255)*alpha
+	}
+	return blend(r), blend(g), blend(b)
+}
+
+func rgb2y(r, g, b float64) float64 { return r*0.29889531 + g*0.58662247 + b*0.11448223 }
+func rgb2i(r, g, b float64) float64 { return r*0.59597799 - g*0.27417610 - b*0.32180189 }
+func rgb2q(r, g, b float64) float64 { return r*0.21147017 - g*0.52261711 + b*0.31114694 }
+
+// luma returns the brightness of a color blended onto white.
+func luma(c color.Color) uint8 {
+	r, g, b := blendWhite(c)
+	return uint8(rgb2y(r, g, b))
+}
+
+// goldenFile returns the path of a golden file such as "compare-desktop.png",
+// creating tests/golden if needed.
+func goldenFile(name string) (string, error) {
+	root, err := findSiteRoot()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, "tests", "golden")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// GoldensCommitted reports whether tests/golden holds any file matching
+// pattern, or -update is blessing them. A suite of golden comparisons stays
+// out of the default run until its first goldens are committed; after that a
+// missing golden fails.
+func GoldensCommitted(t *testing.T, pattern string) bool {
+	t.Helper()
+	if *updateGolden {
+		return true
+	}
+	dir, err := goldenFile("")
+	if err != nil {
+		t.Fatalf("Failed to locate golden directory: %v", err)
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		t.Fatalf("Invalid golden pattern %q: %v", pattern, err)
+	}
+	return len(matches) > 0
+}
+
+// ArtifactDir returns tests/artifacts/<TestName>/, creating it if needed.
+func ArtifactDir(t *testing.T) string {
+	t.Helper()
+	root, err := findSiteRoot()
+	if err != nil {
+		t.Fatalf("Failed to locate artifact directory: %v", err)
+	}
+	dir := filepath.Join(root, "tests", "artifacts", filepath.FromSlash(t.Name()))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("Failed to create artifact directory: %v", err)
+	}
+	return dir
+}
+
+// writeArtifact writes a file under the artifact directory, logging instead of failing.
+func writeArtifact(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Logf("Failed to write artifact %s: %v", path, err)
+	}
+}
@@ -3,75 +3,58 @@ package helpers
 
 import (
 	"fmt"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/JuniperBible/magellan/pkg/e2e"
 )
 
-// BaseURL is the default URL for the Hugo development server
-const BaseURL = "http://localhost:1313"
+// BaseURL is the URL of the site under test. RunTests points it at the
+// in-process test server; it falls back to the Hugo development server.
+var BaseURL = "http://localhost:1313"
 
 // NewTestBrowser creates a new browser instance configured for testing.
 // It automatically registers cleanup to close the browser when the test completes.
 func NewTestBrowser(t *testing.T) *e2e.Browser {
-	browser, err := e2e.NewBrowser(e2e.BrowserOptions{
-		Headless: true,
-		Viewport: e2e.Viewport{Width: 1920, Height: 1080},
-		Timeout:  30 * time.Second,
-	})
-	if err != nil {
-		t.Fatalf("Failed to create browser: %v", err)
-	}
-	t.Cleanup(func() { browser.Close() })
-	return browser
+	return NewDeviceBrowser(t, Desktop)
 }
 
 // NewMobileBrowser creates a browser with mobile viewport and touch emulation.
 func NewMobileBrowser(t *testing.T) *e2e.Browser {
-	browser, err := e2e.NewBrowser(e2e.BrowserOptions{
-		Headless: true,
-		Viewport: e2e.Viewport{Width: 375, Height: 667}, // iPhone SE
-		Timeout:  30 * time.Second,
-		Touch:    true,
-	})
-	if err != nil {
-		t.Fatalf("Failed to create mobile browser: %v", err)
-	}
-	t.Cleanup(func() { browser.Close() })
-	return browser
+	return NewDeviceBrowser(t, IPhoneSE)
 }
 
 // NavigateToCompare navigates to the Bible comparison page.
 func NavigateToCompare(t *testing.T, b *e2e.Browser) {
-	if err := b.Navigate(BaseURL + "/bible/compare/"); err != nil {
+	if err := b.Navigate(BaseURL + compareURL); err != nil {
 		t.Fatalf("Failed to navigate to compare page: %v", err)
 	}
 	// Wait for page to be ready - check for book-select which should have options
-	if err := b.WaitFor("#book-select"); err != nil {
+	if err := b.WaitFor(compareBookSelect); err != nil {
 		t.Fatalf("Compare page did not load: %v", err)
 	}
 }
 
 // NavigateToSearch navigates to the Bible search page.
 func NavigateToSearch(t *testing.T, b *e2e.Browser) {
-	if err := b.Navigate(BaseURL + "/bible/search/"); err != nil {
+	if err := b.Navigate(BaseURL + searchURL); err != nil {
 		t.Fatalf("Failed to navigate to search page: %v", err)
 	}
 	// Wait for search input
-	if err := b.WaitFor("#search-query"); err != nil {
+	if err := b.WaitFor(searchQuery); err != nil {
 		t.Fatalf("Search page did not load: %v", err)
 	}
 }
 
-// NavigateToSingle navigates to a single Bible chapter page.
+// NavigateToSingle navigates to a single Bible chapter page. Book IDs are
+// lowercased to match the generated routes.
 func NavigateToSingle(t *testing.T, b *e2e.Browser, bible, book string, chapter int) {
-	url := fmt.Sprintf("%s/bible/%s/%s/%d/", BaseURL, bible, book, chapter)
+	url := fmt.Sprintf("%s/bible/%s/%s/%d/", BaseURL, bible, strings.ToLower(book), chapter)
 	if err := b.Navigate(url); err != nil {
 		t.Fatalf("Failed to navigate to %s: %v", url, err)
 	}
 	// Wait for chapter content
-	if err := b.WaitFor(".verse"); err != nil {
+	if err := b.WaitFor(singleVerse); err != nil {
 		t.Fatalf("Single page did not load: %v", err)
 	}
 }
@@ -205,35 +188,15 @@ func ExpectOptionCount(t *testing.T, b *e2e.Browser, selector string, minCount i
 // PWA Test Helpers
 // =============================================================================
 
-// WaitForServiceWorker waits for the service worker to be registered and active.
-func WaitForServiceWorker(t *testing.T, b *e2e.Browser) {
-	t.Helper()
-	// Wait up to 10 seconds for SW to activate
-	for i := 0; i < 20; i++ {
-		time.Sleep(500 * time.Millisecond)
-		result, err := b.Evaluate(`
-			(async () => {
-				if (!('serviceWorker' in navigator)) return 'unsupported';
-				const reg = await navigator.serviceWorker.ready;
-				return reg.active ? 'active' : 'waiting';
-			})()
-		`)
-		if err == nil && result == "active" {
-			return
-		}
-	}
-	t.Log("Service worker may not be fully active")
-}
-
 // NavigateToOfflineSettings navigates to the offline settings page/section.
 func NavigateToOfflineSettings(t *testing.T, b *e2e.Browser) {
 	t.Helper()
-	if err := b.Navigate(BaseURL + "/bible/"); err != nil {
+	if err := b.Navigate(BaseURL + offlineURL); err != nil {
 		t.Fatalf("Failed to navigate to Bible page: %v", err)
 	}
 	// Look for offline settings section
-	if err := b.WaitFor("#offline-download-form"); err != nil {
-		t.Log("Offline settings form not found on page")
+	if err := b.WaitFor(offlineForm); err != nil {
+		t.Fatalf("Offline settings form not found on page: %v", err)
 	}
 }
 
@@ -267,51 +230,6 @@ func SimulateOffline(t *testing.T, b *e2e.Browser, offline bool) {
 	}
 }
 
-// FindWithFallbacks searches for an element using multiple selector fallbacks in order,
-// returning the first one that exists. If none exist, the last selector's result is returned.
-func FindWithFallbacks(b *e2e.Browser, selectors ...string) *e2e.Element {
-	for _, sel := range selectors[:len(selectors)-1] {
-		el := b.Find(sel)
-		if el.Exists() {
-			return el
-		}
-	}
-	return b.Find(selectors[len(selectors)-1])
-}
-
-// VerifyStrongsTooltip checks visibility and content of the Strong's tooltip, then tests closing it.
-func VerifyStrongsTooltip(t *testing.T, b *e2e.Browser, tooltip *e2e.Element) {
-	t.Helper()
-	Assert(t, tooltip.ShouldBeVisible())
-
-	// Verify tooltip has content
-	text, _ := tooltip.Text()
-	if len(text) > 0 {
-		limit := 50
-		if len(text) < limit {
-			limit = len(text)
-		}
-		t.Logf("Strong's tooltip displayed: %s...", text[:limit])
-	}
-
-	// Test Escape to close
-	if err := b.Press("Escape"); err == nil {
-		time.Sleep(200 * time.Millisecond)
-		ExpectHidden(t, b, ".strongs-tooltip")
-	}
-}
-
-// LogToastResult finds a toast/alert notification and logs whether it was displayed.
-func LogToastResult(t *testing.T, b *e2e.Browser) {
-	t.Helper()
-	toast := FindWithFallbacks(b, ".toast", "[role='alert']", ".notification")
-	if toast.Exists() {
-		t.Log("Copy success notification displayed")
-	} else {
-		t.Log("No toast notification found - copy may have succeeded silently")
-	}
-}
-
 // CheckManifestField verifies a specific field exists in the manifest.
 func CheckManifestField(t *testing.T, manifest map[string]interface{}, field string) bool {
 	t.Helper()
@@ -322,27 +240,41 @@ func CheckManifestField(t *testing.T, manifest map[string]interface{}, field str
 	return true
 }
 
-// CheckElementTouchTarget logs a warning if an element's touch target is smaller than 44x44 pixels.
+// MinTouchTarget is the width and height in CSS pixels the project aims for on
+// touch devices, per WCAG 2.5.5 Target Size (Enhanced). That criterion is level
+// AAA, so missing it is advisory.
+const MinTouchTarget = 44
+
+// MinPointerTarget is the minimum width and height in CSS pixels of a pointer
+// target, per WCAG 2.2 SC 2.5.8 Target Size (Minimum), level AA. Smaller
+// targets fail.
+const MinPointerTarget = 24
+
+// CheckElementTouchTarget fails the test if an element's touch target is smaller
+// than MinPointerTarget in either dimension, and logs it if it is smaller than
+// MinTouchTarget.
 func CheckElementTouchTarget(t *testing.T, label *e2e.Element, description string) {
 	t.Helper()
 	if !label.Exists() {
 		return
 	}
 	_, _, width, height, err := label.BoundingRect()
-	if err == nil && (height < 44 || width < 44) {
-		t.Logf("Warning: %s may be small for touch: %vx%v", description, width, height)
+	switch {
+	case err != nil:
+		t.Errorf("Failed to measure %s: %v", description, err)
+	case height < MinPointerTarget || width < MinPointerTarget:
+		t.Errorf("%s is too small for touch: %vx%v (minimum %dx%d)", description, width, height, MinPointerTarget, MinPointerTarget)
+	case height < MinTouchTarget || width < MinTouchTarget:
+		t.Logf("Advisory: %s is %vx%v, below the AAA %dx%d", description, width, height, MinTouchTarget, MinTouchTarget)
 	}
 }
 
-// TapAndVerifyChecked clicks a checkbox element and verifies it becomes checked.
-func TapAndVerifyChecked(t *testing.T, checkbox *e2e.Element) {
+// TapAndVerifyChecked clicks the first checkbox matching the selector and
+// waits for it to become checked.
+func TapAndVerifyChecked(t *testing.T, b *e2e.Browser, selector string) {
 	t.Helper()
-	if err := checkbox.Click(); err != nil {
-		t.Errorf("Failed to tap checkbox: %v", err)
-	}
-	time.Sleep(100 * time.Millisecond)
-	checked, _ := checkbox.IsChecked()
-	if !checked {
-		t.Error("Checkbox should be checked after tap")
+	if err := b.Find(selector).Click(); err != nil {
+		t.Fatalf("Failed to tap checkbox: %v", err)
 	}
+	WaitForChecked(t, b, selector, true)
 }
@@ -0,0 +1,260 @@
+package helpers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+)
+
+// =============================================================================
+// Localization
+// =============================================================================
+
+// RTLLanguages are the i18n languages written right to left.
+var RTLLanguages = map[string]bool{"ar": true, "fa": true, "he": true, "ur": true}
+
+// UILanguage is one of the UI translations in i18n/.
+type UILanguage struct {
+	// Code is the file name without .toml, such as "de" or "gez".
+	Code string
+	// Strings maps translation IDs to their "other" form.
+	Strings map[string]string
+}
+
+// Dir returns the text direction the language's pages should declare.
+func (l UILanguage) Dir() string {
+	if RTLLanguages[l.Code] {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Prefix returns the URL path the language's pages are served under.
+func (l UILanguage) Prefix() string {
+	if l.Code == "en" {
+		return ""
+	}
+	return "/" + l.Code
+}
+
+// LoadUILanguages reads every translation in i18n/, English first and the
+// rest by code.
+func LoadUILanguages(t *testing.T) []UILanguage {
+	t.Helper()
+	root, err := findSiteRoot()
+	if err != nil {
+		t.Fatalf("Failed to locate i18n directory: %v", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(root, "i18n", "*.toml"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("Failed to list i18n files: %v", err)
+	}
+
+	var langs []UILanguage
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		strs, err := parseI18n(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", filepath.Base(path), err)
+		}
+		langs = append(langs, UILanguage{Code: strings.TrimSuffix(filepath.Base(path), ".toml"), Strings: strs})
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if (langs[i].Code == "en") != (langs[j].Code == "en") {
+			return langs[i].Code == "en"
+		}
+		return langs[i].Code < langs[j].Code
+	})
+	return langs
+}
+
+// parseI18n reads the subset of TOML the i18n files use: a [id] table per
+// string holding a quoted other = "..." value.
+func parseI18n(f *os.File) (map[string]string, error) {
+	strs := map[string]string{}
+	id := ""
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			id = strings.Trim(line, "[] ")
+		case strings.HasPrefix(line, "other"):
+			_, value, ok := strings.Cut(line, "=")
+			if !ok || id == "" {
+				return nil, fmt.Errorf("line %d: other outside a table", n)
+			}
+			s, err := strconv.Unquote(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			strs[id] = s
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", n, line)
+		}
+	}
+	return strs, scanner.Err()
+}
+
+// i18nCall matches the translation ID of an i18n or T call in a template.
+var i18nCall = regexp.MustCompile(`\b(?:i18n|T)\s+"([^"]+)"`)
+
+// TemplateI18nIDs returns every translation ID the layouts look up, with
+// the templates that use it.
+func TemplateI18nIDs(t *testing.T) map[string][]string {
+	t.Helper()
+	root, err := findSiteRoot()
+	if err != nil {
+		t.Fatalf("Failed to locate layouts: %v", err)
+	}
+	ids := map[string][]string{}
+	err = filepath.WalkDir(filepath.Join(root, "layouts"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		for _, m := range i18nCall.FindAllStringSubmatch(string(src), -1) {
+			if uses := ids[m[1]]; len(uses) == 0 || uses[len(uses)-1] != rel {
+				ids[m[1]] = append(uses, filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan layouts: %v", err)
+	}
+	return ids
+}
+
+// localizationScript inspects the page's language attributes, text and
+// controls and returns a JSON-encoded LocalizationReport. The format verb is a
+// JSON array of English strings the page's language translates.
+const localizationScript = `
+(() => {
+	const english = new Set(%s);
+	const placeholder = /\{\{|\}\}|%%!|\[i18n\]|ZgotmplZ/;
+	const html = document.documentElement;
+	const report = {
+		lang: html.getAttribute('lang') || '',
+		dir: html.getAttribute('dir') || '',
+		placeholders: [],
+		untranslated: [],
+		overflow: []
+	};
+
+	const describe = (el) => {
+		let s = el.tagName.toLowerCase();
+		if (el.id) return s + '#' + el.id;
+		if (el.classList.length) s += '.' + Array.from(el.classList).join('.');
+		const parent = el.parentElement;
+		return parent && parent !== document.body ? describe(parent) + ' > ' + s : s;
+	};
+	const seen = new Set();
+	const inspect = (el, text, where) => {
+		text = text.trim();
+		if (!text) return;
+		if (placeholder.test(text)) report.placeholders.push(describe(el) + where + ': ' + text.slice(0, 80));
+		if (english.has(text) && !seen.has(text)) {
+			seen.add(text);
+			report.untranslated.push(describe(el) + where + ': ' + text);
+		}
+	};
+
+	const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT);
+	for (let node = walker.nextNode(); node; node = walker.nextNode()) {
+		const el = node.parentElement;
+		if (el && !el.closest('script, style, noscript, template')) inspect(el, node.textContent, '');
+	}
+	for (const el of document.querySelectorAll('[aria-label], [title], [placeholder], [alt]')) {
+		for (const name of ['aria-label', 'title', 'placeholder', 'alt']) {
+			if (el.hasAttribute(name)) inspect(el, el.getAttribute(name), '[' + name + ']');
+		}
+	}
+	inspect(document.head, document.title, ' title');
+
+	const viewport = html.clientWidth;
+	for (const el of document.querySelectorAll('nav a, nav button, header a, button, select, .btn')) {
+		if (!el.checkVisibility() || el.closest('.sr-only, .skip-link')) continue;
+		const rect = el.getBoundingClientRect();
+		if (!rect.width || !rect.height) continue;
+		const style = getComputedStyle(el);
+		const clips = style.overflowX !== 'visible' || style.textOverflow === 'ellipsis';
+		if (el.tagName !== 'SELECT' && el.clientWidth > 0 && el.scrollWidth > el.clientWidth + 1) {
+			report.overflow.push(describe(el) + (clips ? ' clips' : ' overflows') + ' its text (' +
+				el.scrollWidth + 'px in ' + el.clientWidth + 'px)');
+		} else if (rect.left < -1 || rect.right > viewport + 1) {
+			report.overflow.push(describe(el) + ' extends past the viewport (' +
+				Math.round(rect.left) + 'px to ' + Math.round(rect.right) + 'px of ' + viewport + 'px)');
+		}
+	}
+	return JSON.stringify(report);
+})()
+`
+
+// LocalizationReport is what InspectLocalization found on a page.
+type LocalizationReport struct {
+	Lang string `json:"lang"`
+	Dir  string `json:"dir"`
+	// Placeholders lists text showing template syntax, fmt errors or
+	// missing-translation markers.
+	Placeholders []string `json:"placeholders"`
+	// Untranslated lists text matching an English string the page's
+	// language has a different translation for.
+	Untranslated []string `json:"untranslated"`
+	// Overflow lists controls whose text does not fit them or that reach
+	// past the viewport.
+	Overflow []string `json:"overflow"`
+}
+
+// InspectLocalization checks the current page as a page in lang, comparing
+// its text against en, the English strings.
+func InspectLocalization(t *testing.T, b *e2e.Browser, lang, en UILanguage) LocalizationReport {
+	t.Helper()
+	var fallbacks []string
+	if lang.Code != en.Code {
+		for id, english := range en.Strings {
+			// Pages name books in the Bible's own language, not the UI's
+			if strings.HasPrefix(id, "bookName") {
+				continue
+			}
+			if translated, ok := lang.Strings[id]; ok && translated != english {
+				fallbacks = append(fallbacks, english)
+			}
+		}
+		sort.Strings(fallbacks)
+	}
+	list, err := json.Marshal(fallbacks)
+	if err != nil {
+		t.Fatalf("Failed to encode English strings: %v", err)
+	}
+
+	raw, err := b.Evaluate(fmt.Sprintf(localizationScript, list))
+	if err != nil {
+		t.Fatalf("Failed to inspect localization: %v", err)
+	}
+	s, _ := raw.(string)
+	var report LocalizationReport
+	if err := json.Unmarshal([]byte(s), &report); err != nil {
+		t.Fatalf("Failed to decode localization report: %v", err)
+	}
+	return report
+}
@@ -0,0 +1,343 @@
+package helpers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"michael-tests/jscover"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/profiler"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// JavaScript Coverage
+// =============================================================================
+
+// jsCoverDir turns on JavaScript coverage and names where the report goes:
+//
+//	go test ./regression/ -jscover ../artifacts/jscover
+//
+// writes lcov.info and index.html for the scripts in assets/js and static/js
+// and for sw.js, which is rendered from layouts/_default/sw.js. The site is
+// then built without --minify, so served scripts keep their source lines.
+var jsCoverDir = flag.String("jscover", "", "collect JavaScript coverage and write lcov.info and index.html to this directory")
+
+// jsCoverMin fails the run when a file's line coverage is below a minimum,
+// given as a default and per-file overrides such as
+// "40,assets/js/parallel.js=25".
+var jsCoverMin = flag.String("jscover-min", "", "minimum JavaScript line coverage: PCT,path=PCT,...")
+
+// jsCoverage collects coverage from every browser when -jscover is set.
+var jsCoverage *coverageCollector
+
+// coverageCollector merges the coverage of all browsers in the run.
+type coverageCollector struct {
+	mu      sync.Mutex
+	report  *jscover.Report
+	sources map[string]string
+	// workers holds a context attached to each service worker target seen.
+	// They stay attached: cancelling one would close the worker.
+	workers map[target.ID]context.Context
+}
+
+// CollectJSCoverage records which parts of the site's scripts b runs during
+// the rest of the test, when -jscover is set, including its service worker.
+// Browsers from NewDeviceBrowser are collected already; call this for
+// browsers created some other way.
+func CollectJSCoverage(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	if jsCoverage == nil {
+		return
+	}
+	if err := RunCDP(b, startCoverage()); err != nil {
+		t.Logf("Failed to start JavaScript coverage: %v", err)
+		return
+	}
+	workers := jsCoverage.watchWorkers(t, b)
+
+	t.Cleanup(func() {
+		targets := append([]context.Context{b.Context()}, workers()...)
+		for _, ctx := range targets {
+			var scripts []*profiler.ScriptCoverage
+			err := chromedp.Run(ctx,
+				chromedp.ActionFunc(func(ctx context.Context) (err error) {
+					scripts, _, err = profiler.TakePreciseCoverage().Do(ctx)
+					return err
+				}),
+				profiler.StopPreciseCoverage(),
+				profiler.Disable(),
+			)
+			if err != nil {
+				// A service worker stopped by the browser takes its coverage with it
+				t.Logf("Failed to take JavaScript coverage: %v", err)
+				continue
+			}
+			if err := jsCoverage.add(scripts); err != nil {
+				t.Logf("Failed to record JavaScript coverage: %v", err)
+			}
+		}
+	})
+}
+
+// startCoverage starts precise coverage with call counts in a target.
+func startCoverage() chromedp.Action {
+	return chromedp.Tasks{
+		profiler.Enable(),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := profiler.StartPreciseCoverage().WithCallCount(true).WithDetailed(true).Do(ctx)
+			return err
+		}),
+	}
+}
+
+// watchWorkers starts coverage in the site's service workers b is running
+// and in those it starts until the test ends, which the page target reports
+// through Target.setAutoAttach. It returns a function listing the worker
+// targets coverage was started in. A worker's code that runs before its
+// coverage starts, such as its first install, is not counted.
+func (c *coverageCollector) watchWorkers(t *testing.T, b *e2e.Browser) func() []context.Context {
+	var (
+		mu      sync.Mutex
+		done    bool
+		pending sync.WaitGroup
+		started = map[target.ID]context.Context{}
+	)
+	start := func(info *target.Info) {
+		defer pending.Done()
+		if info.Type != "service_worker" || !strings.HasPrefix(info.URL, BaseURL+"/") {
+			return
+		}
+		mu.Lock()
+		_, seen := started[info.TargetID]
+		started[info.TargetID] = nil
+		mu.Unlock()
+		if seen {
+			return
+		}
+		ctx := c.worker(b, info.TargetID)
+		if err := chromedp.Run(ctx, startCoverage()); err != nil {
+			t.Logf("Failed to start JavaScript coverage in %s: %v", info.URL, err)
+			return
+		}
+		mu.Lock()
+		started[info.TargetID] = ctx
+		mu.Unlock()
+	}
+	// goStart runs start off the event loop: listeners must not block, and
+	// attaching sends CDP messages.
+	goStart := func(info *target.Info) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			pending.Add(1)
+			go start(info)
+		}
+	}
+
+	listen, stop := context.WithCancel(b.Context())
+	chromedp.ListenTarget(listen, func(ev interface{}) {
+		if ev, ok := ev.(*target.EventAttachedToTarget); ok {
+			goStart(ev.TargetInfo)
+		}
+	})
+	err := RunCDP(b,
+		target.SetAutoAttach(true, false).WithFlatten(true),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			infos, err := target.GetTargets().Do(cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser))
+			for _, info := range infos {
+				goStart(info)
+			}
+			return err
+		}),
+	)
+	if err != nil {
+		t.Logf("Failed to watch service workers for JavaScript coverage: %v", err)
+	}
+
+	return func() []context.Context {
+		mu.Lock()
+		done = true
+		mu.Unlock()
+		stop()
+		pending.Wait()
+
+		var ctxs []context.Context
+		for _, ctx := range started {
+			if ctx != nil {
+				ctxs = append(ctxs, ctx)
+			}
+		}
+		return ctxs
+	}
+}
+
+// worker returns the context attached to a service worker target of b,
+// attaching on first use.
+func (c *coverageCollector) worker(b *e2e.Browser, id target.ID) context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ctx, ok := c.workers[id]
+	if !ok {
+		ctx, _ = chromedp.NewContext(b.Context(), chromedp.WithTargetID(id))
+		c.workers[id] = ctx
+	}
+	return ctx
+}
+
+// add merges the coverage of the site's external scripts into the report.
+func (c *coverageCollector) add(scripts []*profiler.ScriptCoverage) error {
+	for _, sc := range scripts {
+		if !strings.HasPrefix(sc.URL, BaseURL+"/") || !strings.HasSuffix(strings.SplitN(sc.URL, "?", 2)[0], ".js") {
+			continue // inline scripts, extensions and other origins
+		}
+		src, err := c.source(sc.URL)
+		if err != nil {
+			return err
+		}
+
+		script := jscover.Script{URL: sc.URL, Source: src}
+		for _, fn := range sc.Functions {
+			f := jscover.Function{Name: fn.FunctionName}
+			for _, rg := range fn.Ranges {
+				f.Ranges = append(f.Ranges, jscover.Range{
+					Start: int(rg.StartOffset),
+					End:   int(rg.EndOffset),
+					Count: int(rg.Count),
+				})
+			}
+			script.Functions = append(script.Functions, f)
+		}
+
+		c.mu.Lock()
+		err = c.report.Add(script)
+		c.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// source returns a script as the server sends it, fetching each URL once.
+func (c *coverageCollector) source(url string) (string, error) {
+	c.mu.Lock()
+	src, ok := c.sources[url]
+	c.mu.Unlock()
+	if ok {
+		return src, nil
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", url, err)
+	}
+
+	c.mu.Lock()
+	c.sources[url] = string(body)
+	c.mu.Unlock()
+	return string(body), nil
+}
+
+// runWithCoverage runs the tests, collecting JavaScript coverage when
+// -jscover is set. It writes the report afterwards and fails the run if a
+// file is below its -jscover-min threshold.
+func runWithCoverage(m *testing.M) int {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	if *jsCoverDir == "" {
+		return runWithPool(m)
+	}
+
+	thresholds, err := jscover.ParseThresholds(*jsCoverMin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -jscover-min: %v\n", err)
+		return 1
+	}
+	root, err := findSiteRoot()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to find site root: %v\n", err)
+		return 1
+	}
+	jsCoverage = &coverageCollector{
+		report:  jscover.NewReport(root),
+		sources: map[string]string{},
+		workers: map[target.ID]context.Context{},
+	}
+	defer func() { jsCoverage = nil }()
+
+	code := runWithPool(m)
+
+	report := jsCoverage.report
+	// Scripts no test loaded still count, at zero
+	dirs := []string{"assets/js", "static/js"}
+	for _, layout := range jscover.LayoutSources {
+		dirs = append(dirs, layout)
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(root, dir)); err == nil {
+			if err := report.IncludeAll(dir); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to list %s: %v\n", dir, err)
+				return 1
+			}
+		}
+	}
+	if err := writeCoverageReport(report, *jsCoverDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write JavaScript coverage: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "JavaScript coverage in %s\n", *jsCoverDir)
+
+	if failures := report.Check(thresholds); len(failures) > 0 {
+		fmt.Fprintln(os.Stderr, "JavaScript coverage below minimum:")
+		for _, f := range failures {
+			fmt.Fprintf(os.Stderr, "  %s\n", f)
+		}
+		return 1
+	}
+	return code
+}
+
+// writeCoverageReport writes lcov.info and index.html to dir.
+func writeCoverageReport(report *jscover.Report, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, write := range map[string]func(io.Writer) error{
+		"lcov.info":  report.WriteLCOV,
+		"index.html": report.WriteHTML,
+	} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if err := write(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers/selectors"
+)
+
+// =============================================================================
+// Offline Settings Page Object
+// =============================================================================
+
+// Offline settings selectors. The panel is a collapsed <details> on the Bible
+// list page; its checkboxes are visually hidden behind .bible-chip labels.
+const (
+	offlineURL       = "/bible/"
+	offlineSummary   = selectors.ClassOfflineSettingsSummary
+	offlineForm      = selectors.IDOfflineDownloadForm
+	offlineChip      = selectors.ClassBibleChip + "[data-bible-id='%s']"
+	offlineChipAny   = selectors.ClassBibleChip + "[data-bible-id]"
+	offlineCheckbox  = selectors.IDBibleFmt
+	offlineStatus    = selectors.IDStatusFmt
+	offlineDownload  = selectors.IDDownloadOfflineBtn
+	offlineClear     = selectors.IDClearCacheBtn
+	offlineProgress  = selectors.IDDownloadProgressContainer
+	offlineCachedNum = selectors.IDCachedChaptersCount
+)
+
+// DownloadProgress is the detail of an OfflineManager download-progress event.
+type DownloadProgress struct {
+	Bible     string `json:"bible"`
+	Progress  int    `json:"progress"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+}
+
+// DownloadResult is the detail of an OfflineManager download-complete event.
+// A download whose chapters partly failed still succeeds, with FailedCount
+// books and chapters left uncached.
+type DownloadResult struct {
+	Bible       string `json:"bible"`
+	Success     bool   `json:"success"`
+	ItemCount   int    `json:"itemCount"`
+	FailedCount int    `json:"failedCount"`
+	Cancelled   bool   `json:"cancelled"`
+	Error       string `json:"error"`
+}
+
+// BibleCacheStatus is what the service worker reports for one Bible through
+// OfflineManager.getBibleCacheStatus.
+type BibleCacheStatus struct {
+	CachedChapters   int  `json:"cachedChapters"`
+	CachedBooks      int  `json:"cachedBooks"`
+	TotalChapters    int  `json:"totalChapters"`
+	HasBibleOverview bool `json:"hasBibleOverview"`
+	IsFullyCached    bool `json:"isFullyCached"`
+}
+
+// DownloadTimeout bounds waiting for a whole Bible to download.
+var DownloadTimeout = 2 * time.Minute
+
+// OfflineSettings drives the offline download panel.
+type OfflineSettings struct {
+	t *testing.T
+	b *e2e.Browser
+}
+
+// OpenOfflineSettings navigates to the Bible list and expands the offline
+// settings panel.
+func OpenOfflineSettings(t *testing.T, b *e2e.Browser) *OfflineSettings {
+	t.Helper()
+	NavigateToOfflineSettings(t, b)
+	if !b.Find(offlineForm).Visible() {
+		WaitAndClick(t, b, offlineSummary)
+	}
+	if err := b.WaitForVisible(offlineForm); err != nil {
+		t.Fatalf("Offline settings did not expand: %v", err)
+	}
+	return &OfflineSettings{t: t, b: b}
+}
+
+// SelectBibles checks each Bible for download, leaving the others as they are.
+func (o *OfflineSettings) SelectBibles(ids ...string) {
+	o.t.Helper()
+	for _, id := range ids {
+		if checked, _ := o.Checkbox(id).IsChecked(); checked {
+			continue
+		}
+		WaitAndClick(o.t, o.b, fmt.Sprintf(offlineChip, id))
+		if checked, _ := o.Checkbox(id).IsChecked(); !checked {
+			o.t.Fatalf("Bible %s did not become selected for download", id)
+		}
+	}
+}
+
+// StartDownload selects the Bibles, starts the download and waits for the
+// service worker to report progress on any of them.
+func (o *OfflineSettings) StartDownload(ids ...string) DownloadProgress {
+	o.t.Helper()
+	o.SelectBibles(ids...)
+	progress := ListenForDownloadProgress(o.t, o.b, "", 1)
+	WaitAndClick(o.t, o.b, offlineDownload)
+
+	var p DownloadProgress
+	progress.WaitInto(&p)
+	return p
+}
+
+// Download selects the Bible, starts the download and waits for it to finish
+// or fail.
+func (o *OfflineSettings) Download(id string) DownloadResult {
+	o.t.Helper()
+	o.SelectBibles(id)
+	complete := ListenForDownloadComplete(o.t, o.b, id).Within(DownloadTimeout)
+	if err := o.b.WaitForEnabled(offlineDownload); err != nil {
+		o.t.Fatalf("Download button did not enable: %v", err)
+	}
+	WaitAndClick(o.t, o.b, offlineDownload)
+
+	var res DownloadResult
+	complete.WaitInto(&res)
+	return res
+}
+
+// Cancel cancels the Bible's download and waits for OfflineManager to report
+// it cancelled.
+func (o *OfflineSettings) Cancel(id string) DownloadResult {
+	o.t.Helper()
+	// The service worker may also report the aborted download as an error;
+	// wait for OfflineManager's own cancellation event
+	complete := ListenForAppEvent(o.t, o.b, OfflineManagerEvents, "download-complete",
+		"d => d && d.cancelled && d.bible === "+jsString(id))
+	cancelled, err := o.b.Evaluate(fmt.Sprintf(
+		`window.Michael.OfflineManager.cancelDownload(%s)`, jsString(id)))
+	if err != nil {
+		o.t.Fatalf("Failed to cancel download of %s: %v", id, err)
+	}
+	if cancelled != true {
+		o.t.Fatalf("No download of %s was in progress to cancel", id)
+	}
+
+	var res DownloadResult
+	complete.WaitInto(&res)
+	return res
+}
+
+// CacheStatus asks the service worker how much of the Bible is cached.
+func (o *OfflineSettings) CacheStatus(id string) BibleCacheStatus {
+	o.t.Helper()
+	raw, err := o.b.Evaluate(fmt.Sprintf(`(async () => JSON.stringify(
+		await window.Michael.OfflineManager.getBibleCacheStatus(%s, '/bible')))()`, jsString(id)))
+	if err != nil {
+		o.t.Fatalf("Failed to get cache status for %s: %v", id, err)
+	}
+	s, _ := raw.(string)
+	var status BibleCacheStatus
+	if err := json.Unmarshal([]byte(s), &status); err != nil {
+		o.t.Fatalf("Failed to decode cache status for %s: %v", id, err)
+	}
+	return status
+}
+
+// Status returns the per-Bible download status text.
+func (o *OfflineSettings) Status(id string) string {
+	text, _ := o.b.Find(fmt.Sprintf(offlineStatus, id)).Text()
+	return text
+}
+
+// Checkbox returns the download checkbox for a Bible.
+func (o *OfflineSettings) Checkbox(id string) *e2e.Element {
+	return o.b.Find(fmt.Sprintf(offlineCheckbox, id))
+}
+
+// FirstBible returns the ID of the first Bible offered for download, or ""
+// when none are.
+func (o *OfflineSettings) FirstBible() string {
+	id, _ := o.b.Find(offlineForm + " " + offlineChipAny).Attribute("data-bible-id")
+	return id
+}
+
+// DownloadButton returns the download button.
+func (o *OfflineSettings) DownloadButton() *e2e.Element { return o.b.Find(offlineDownload) }
+
+// ClearButton returns the clear-cache button.
+func (o *OfflineSettings) ClearButton() *e2e.Element { return o.b.Find(offlineClear) }
+
+// ProgressPanel returns the download progress container.
+func (o *OfflineSettings) ProgressPanel() *e2e.Element { return o.b.Find(offlineProgress) }
+
+// CachedChapters returns the cached chapter count element.
+func (o *OfflineSettings) CachedChapters() *e2e.Element { return o.b.Find(offlineCachedNum) }
@@ -0,0 +1,252 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Browser Pool
+// =============================================================================
+
+// BrowserPool hands out browsers for tests and takes them back afterwards,
+// so the suite launches a few Chrome instances instead of one per test.
+//
+// Every browser is a separate headless Chrome with its own throwaway
+// profile, so browsers checked out at the same time share nothing. When a
+// test ends, its browser goes back online, leaves the site and has the
+// site's storage wiped (Cache Storage, service workers, IndexedDB, local
+// storage, cookies), along with the HTTP cache and granted permissions. The
+// next test gets what is in effect a fresh incognito context. A browser that
+// cannot be reset is closed instead of returned.
+//
+// A BrowserPool is safe for concurrent use by parallel tests.
+type BrowserPool struct {
+	// size is how many idle browsers the pool keeps for reuse.
+	size int
+
+	mu    sync.Mutex
+	idle  []*pooledBrowser
+	live  map[*pooledBrowser]bool
+	stats PoolStats
+	// retired is the lifetime of browsers already closed.
+	retired time.Duration
+}
+
+// pooledBrowser is a browser owned by the pool.
+type pooledBrowser struct {
+	b        *e2e.Browser
+	key      Device
+	launched time.Time
+}
+
+// PoolStats reports how well a BrowserPool has been used.
+type PoolStats struct {
+	// Checkouts is how many browsers were handed out.
+	Checkouts int
+	// Launched is how many of them had to be started.
+	Launched int
+	// Discarded is how many browsers were closed because they could not be
+	// reset for the next test.
+	Discarded int
+	// InUse and PeakInUse count browsers checked out now and at most.
+	InUse, PeakInUse int
+	// Busy is the total time browsers spent checked out.
+	Busy time.Duration
+	// Alive is the total time browsers have been running.
+	Alive time.Duration
+}
+
+// Reused returns the share of checkouts served by an existing browser.
+func (s PoolStats) Reused() float64 {
+	if s.Checkouts == 0 {
+		return 0
+	}
+	return float64(s.Checkouts-s.Launched) / float64(s.Checkouts)
+}
+
+// Utilization returns the share of browser run time spent serving tests.
+func (s PoolStats) Utilization() float64 {
+	if s.Alive == 0 {
+		return 0
+	}
+	return float64(s.Busy) / float64(s.Alive)
+}
+
+func (s PoolStats) String() string {
+	return fmt.Sprintf("%d checkouts, %d browsers launched (%.0f%% reused), %d discarded, peak %d in use, %.0f%% utilization",
+		s.Checkouts, s.Launched, s.Reused()*100, s.Discarded, s.PeakInUse, s.Utilization()*100)
+}
+
+// NewBrowserPool returns a pool that keeps up to size idle browsers. It does
+// not limit how many are checked out at once; go test's -parallel does that.
+func NewBrowserPool(size int) *BrowserPool {
+	return &BrowserPool{
+		size: max(size, 1),
+		live: map[*pooledBrowser]bool{},
+	}
+}
+
+// Acquire returns a clean browser emulating d for the rest of the test.
+func (p *BrowserPool) Acquire(t *testing.T, d Device) *e2e.Browser {
+	t.Helper()
+	key := d
+	key.Name = ""
+
+	p.mu.Lock()
+	var pb *pooledBrowser
+	for i := len(p.idle) - 1; i >= 0; i-- {
+		if p.idle[i].key == key {
+			pb = p.idle[i]
+			p.idle = append(p.idle[:i], p.idle[i+1:]...)
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if pb == nil {
+		b, err := launchBrowser(d)
+		if err != nil {
+			t.Fatalf("Failed to create %s browser: %v", d.Name, err)
+		}
+		pb = &pooledBrowser{b: b, key: key, launched: time.Now()}
+	}
+
+	p.mu.Lock()
+	if !p.live[pb] {
+		p.live[pb] = true
+		p.stats.Launched++
+	}
+	p.stats.Checkouts++
+	p.stats.InUse++
+	p.stats.PeakInUse = max(p.stats.PeakInUse, p.stats.InUse)
+	p.mu.Unlock()
+
+	checkedOut := time.Now()
+	t.Cleanup(func() { p.release(pb, time.Since(checkedOut)) })
+	return pb.b
+}
+
+// release resets a browser and keeps it for the next test, closing it if
+// the reset fails or the pool already holds enough idle browsers.
+func (p *BrowserPool) release(pb *pooledBrowser, busy time.Duration) {
+	err := resetBrowser(pb.b)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.InUse--
+	p.stats.Busy += busy
+	if err != nil {
+		p.stats.Discarded++
+		p.closeLocked(pb)
+		return
+	}
+	p.idle = append(p.idle, pb)
+	if len(p.idle) > p.size {
+		p.closeLocked(p.idle[0])
+		p.idle = p.idle[1:]
+	}
+}
+
+// closeLocked closes a browser the pool owns. p.mu must be held.
+func (p *BrowserPool) closeLocked(pb *pooledBrowser) {
+	pb.b.Close()
+	delete(p.live, pb)
+	p.retired += time.Since(pb.launched)
+}
+
+// Stats returns the pool's usage so far.
+func (p *BrowserPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.stats
+	s.Alive = p.retired
+	for pb := range p.live {
+		s.Alive += time.Since(pb.launched)
+	}
+	return s
+}
+
+// Close closes the idle browsers. Browsers still checked out are closed
+// when their tests end.
+func (p *BrowserPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pb := range p.idle {
+		p.closeLocked(pb)
+	}
+	p.idle = nil
+	p.size = 0
+}
+
+// resetBrowser returns a browser to the state of a fresh profile as far as
+// the site under test can tell.
+func resetBrowser(b *e2e.Browser) error {
+	origin, err := siteOrigin()
+	if err != nil {
+		return err
+	}
+	if err := b.SetOffline(false); err != nil {
+		return fmt.Errorf("go online: %w", err)
+	}
+	// Leave the site so no page keeps IndexedDB open or a service worker in use
+	if err := b.Navigate("about:blank"); err != nil {
+		return fmt.Errorf("leave site: %w", err)
+	}
+	return RunCDP(b,
+		storage.ClearDataForOrigin(origin, "all"),
+		network.ClearBrowserCache(),
+		network.ClearBrowserCookies(),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			// Permissions belong to the browser, not the page target
+			exec := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Browser)
+			return browser.ResetPermissions().Do(exec)
+		}),
+	)
+}
+
+// sharedPool serves NewDeviceBrowser when RunTests set it up.
+var sharedPool *BrowserPool
+
+// =============================================================================
+// Parallel Tests
+// =============================================================================
+
+// parallelTests holds the names of tests marked with Parallel.
+var parallelTests sync.Map
+
+// Parallel marks the test as safe to run alongside other parallel tests and
+// pauses it until the sequential tests have finished. Tests that change
+// shared state, such as InjectFaults on the test server, must stay
+// sequential.
+func Parallel(t *testing.T) {
+	t.Helper()
+	parallelTests.Store(t.Name(), true)
+	t.Parallel()
+}
+
+// isParallel reports whether t or one of its parents called Parallel.
+func isParallel(t *testing.T) bool {
+	name := t.Name()
+	for {
+		if _, ok := parallelTests.Load(name); ok {
+			return true
+		}
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return false
+		}
+		name = name[:i]
+	}
+}
@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"michael-tests/pdftext"
+)
+
+// =============================================================================
+// Printing
+// =============================================================================
+
+// printMargin matches the page-number line theme-print.css puts in the
+// bottom-right page margin, which can fall in the middle of a verse that
+// breaks across pages.
+var printMargin = regexp.MustCompile(`(?m)^Printed: \d+$`)
+
+// PrintedDocument is the text layer of a page printed to PDF.
+type PrintedDocument struct {
+	*pdftext.Document
+	// Path is where the PDF was saved.
+	Path string
+	// body is the text of every page without the page margins and whitespace.
+	body string
+}
+
+// PrintToPDF prints the current page as Chrome's print dialog would, saves
+// the PDF as <name>.pdf in the test's artifact directory and extracts its
+// text.
+func PrintToPDF(t *testing.T, b *e2e.Browser, name string) *PrintedDocument {
+	t.Helper()
+	var data []byte
+	err := RunCDP(b, chromedp.ActionFunc(func(ctx context.Context) (err error) {
+		data, _, err = page.PrintToPDF().WithPreferCSSPageSize(true).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		t.Fatalf("Failed to print to PDF: %v", err)
+	}
+	path := filepath.Join(ArtifactDir(t), name+".pdf")
+	writeArtifact(t, path, data)
+
+	doc, err := pdftext.Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to extract text from %s: %v", path, err)
+	}
+	var body strings.Builder
+	for _, p := range doc.Pages {
+		body.WriteString(squashSpace(printMargin.ReplaceAllString(p, "")))
+	}
+	return &PrintedDocument{Document: doc, Path: path, body: body.String()}
+}
+
+// squashSpace removes all whitespace, which differs between the DOM and the
+// PDF text layer wherever lines wrap.
+func squashSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// printTextScript returns the text each element matching the selector prints,
+// as a JSON array: its visible text plus quoted and attr() generated content,
+// read under print media.
+const printTextScript = `
+(() => {
+	const generated = (el, which) => {
+		const content = getComputedStyle(el, which).content;
+		const quoted = /^"((?:[^"\\]|\\.)*)"$/.exec(content);
+		if (quoted) return quoted[1].replace(/\\(.)/g, '$1');
+		const attr = /^attr\(([\w-]+)\)$/.exec(content);
+		return attr ? el.getAttribute(attr[1]) || '' : '';
+	};
+	const text = (node) => {
+		if (node.nodeType === Node.TEXT_NODE) return node.textContent;
+		if (node.nodeType !== Node.ELEMENT_NODE) return '';
+		if (getComputedStyle(node).display === 'none') return '';
+		return generated(node, '::before') + Array.from(node.childNodes, text).join('') + generated(node, '::after');
+	};
+	return JSON.stringify(Array.from(document.querySelectorAll(%q), text));
+})()
+`
+
+// PrintedTexts returns the text each element matching selector should print,
+// in document order. Elements print media hides come back empty.
+func PrintedTexts(t *testing.T, b *e2e.Browser, selector string) []string {
+	t.Helper()
+	if err := RunCDP(b, emulation.SetEmulatedMedia().WithMedia("print")); err != nil {
+		t.Fatalf("Failed to emulate print media: %v", err)
+	}
+	defer RunCDP(b, emulation.SetEmulatedMedia().WithMedia(""))
+
+	raw, err := b.Evaluate(fmt.Sprintf(printTextScript, selector))
+	if err != nil {
+		t.Fatalf("Failed to read printed text of %s: %v", selector, err)
+	}
+	s, _ := raw.(string)
+	var texts []string
+	if err := json.Unmarshal([]byte(s), &texts); err != nil {
+		t.Fatalf("Failed to decode printed text of %s: %v", selector, err)
+	}
+	return texts
+}
+
+// markPrintScript appends a marker ending in its index to every element
+// matching the selector and returns how many it marked.
+const markPrintScript = `
+(() => {
+	const els = document.querySelectorAll(%q);
+	els.forEach((el, i) => {
+		const span = document.createElement('span');
+		span.textContent = %q + i;
+		el.appendChild(span);
+	});
+	return els.length;
+})()
+`
+
+// MarkForPrint tags every element matching selector with text naming label,
+// so ExpectUnmarked can tell whether any of them printed, and returns how
+// many it tagged. Mark elements after they render and before printing.
+func MarkForPrint(t *testing.T, b *e2e.Browser, label, selector string) int {
+	t.Helper()
+	raw, err := b.Evaluate(fmt.Sprintf(markPrintScript, selector, printMarker(label)))
+	if err != nil {
+		t.Fatalf("Failed to mark %s for print: %v", selector, err)
+	}
+	n, _ := raw.(float64)
+	return int(n)
+}
+
+// printMarker is the marker text for label, unlikely to occur in a page.
+func printMarker(label string) string {
+	return "NOPRINT-" + label + "-"
+}
+
+// ExpectUnmarked asserts that no element MarkForPrint tagged with label
+// printed.
+func (d *PrintedDocument) ExpectUnmarked(t *testing.T, label string) {
+	t.Helper()
+	marker := squashSpace(printMarker(label))
+	if n := strings.Count(d.body, marker); n > 0 {
+		t.Errorf("%d %s elements printed in %s", n, label, d.Path)
+	}
+}
+
+// ExpectInOrder asserts that each text prints after the one before it.
+// Only the first 60 characters of each are compared, ignoring whitespace,
+// which keeps failures readable for long verses.
+func (d *PrintedDocument) ExpectInOrder(t *testing.T, texts []string) {
+	t.Helper()
+	pos := 0
+	for i, text := range texts {
+		want := []rune(squashSpace(text))
+		if len(want) == 0 {
+			t.Errorf("Text %d to print is empty", i+1)
+			continue
+		}
+		want = want[:min(len(want), 60)]
+		at := strings.Index(d.body[pos:], string(want))
+		if at < 0 {
+			where := "after the text before it"
+			if !strings.Contains(d.body, string(want)) {
+				where = "anywhere"
+			}
+			t.Errorf("Text %d (%q) did not print %s in %s", i+1, string(want), where, d.Path)
+			continue
+		}
+		pos += at + len(string(want))
+	}
+}
+
+// ExpectPages asserts that the document has between lo and hi pages.
+func (d *PrintedDocument) ExpectPages(t *testing.T, lo, hi int) {
+	t.Helper()
+	if n := len(d.Pages); n < lo || n > hi {
+		t.Errorf("Expected %d to %d printed pages, got %d in %s", lo, hi, n, d.Path)
+	}
+}
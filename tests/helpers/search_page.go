@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers/selectors"
+)
+
+// =============================================================================
+// Search Page Object
+// =============================================================================
+
+// Search page selectors.
+const (
+	searchURL           = "/bible/search/"
+	searchQuery         = selectors.IDSearchQuery
+	searchBible         = selectors.IDBibleSelect
+	searchCaseSensitive = selectors.IDCaseSensitive
+	searchWholeWord     = selectors.IDWholeWord
+	searchStatus        = selectors.IDSearchStatus
+	searchResults       = selectors.IDSearchResults
+)
+
+// searchResultsScript reads the rendered results. The first paragraph is
+// either the "Found N results" summary or a notice such as "No results found".
+const searchResultsScript = `
+(() => {
+	const root = document.querySelector(%s);
+	if (!root) return JSON.stringify({ total: 0, refs: [], message: 'results container missing' });
+	const refs = Array.from(root.querySelectorAll('.search-result header a')).map(a => a.textContent.trim());
+	const first = root.querySelector('p');
+	const text = first ? first.textContent.trim() : '';
+	const found = text.match(/^Found (\d+) result/);
+	return JSON.stringify({ total: found ? Number(found[1]) : 0, refs, message: found ? '' : text });
+})()
+`
+
+// SearchOptions are the search form's Bible filter and checkboxes.
+type SearchOptions struct {
+	// Bible is the translation to search; empty keeps the current selection.
+	Bible         string
+	CaseSensitive bool
+	WholeWord     bool
+}
+
+// SearchResults is what the search page rendered for one query.
+type SearchResults struct {
+	// Total is the match count from the summary, which may exceed len(Refs)
+	// because only the first 100 results are shown.
+	Total int `json:"total"`
+	// Refs are the displayed references, e.g. "Genesis 1:1".
+	Refs []string `json:"refs"`
+	// Message is the notice shown when there are no results.
+	Message string `json:"message"`
+}
+
+// SearchPage drives the Bible search page.
+type SearchPage struct {
+	t *testing.T
+	b *e2e.Browser
+}
+
+// OpenSearchPage navigates to the search page and waits for the query input.
+func OpenSearchPage(t *testing.T, b *e2e.Browser) *SearchPage {
+	t.Helper()
+	NavigateToSearch(t, b)
+	return &SearchPage{t: t, b: b}
+}
+
+// Search fills in the form, submits it and waits for the results.
+func (p *SearchPage) Search(query string, opts SearchOptions) SearchResults {
+	p.t.Helper()
+	if opts.Bible != "" {
+		SelectOption(p.t, p.b, searchBible, opts.Bible)
+	}
+	p.setChecked(searchCaseSensitive, opts.CaseSensitive)
+	p.setChecked(searchWholeWord, opts.WholeWord)
+	WaitAndType(p.t, p.b, searchQuery, query)
+	if err := p.b.Press("Enter"); err != nil {
+		p.t.Fatalf("Failed to submit search: %v", err)
+	}
+	return p.WaitForResults()
+}
+
+// WaitForResults waits for a submitted search to finish and returns what it
+// rendered.
+func (p *SearchPage) WaitForResults() SearchResults {
+	p.t.Helper()
+	WaitForNetworkIdle(p.t, p.b)
+	if err := p.b.WaitForHidden(searchStatus); err != nil {
+		text, _ := p.b.Find(searchStatus).Text()
+		p.t.Fatalf("Search did not finish (status %q): %v", text, err)
+	}
+
+	var res SearchResults
+	res.Refs = []string{}
+	raw, err := p.b.Evaluate(fmt.Sprintf(searchResultsScript, jsString(searchResults)))
+	if err != nil {
+		p.t.Fatalf("Failed to read search results: %v", err)
+	}
+	s, ok := raw.(string)
+	if !ok {
+		p.t.Fatalf("Search results script returned %T", raw)
+	}
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		p.t.Fatalf("Failed to decode search results: %v", err)
+	}
+	return res
+}
+
+// BibleCount returns the number of Bibles offered in the filter.
+func (p *SearchPage) BibleCount() int {
+	p.t.Helper()
+	return optionCount(p.t, p.b, searchBible)
+}
+
+// setChecked clicks a checkbox until it matches want.
+func (p *SearchPage) setChecked(selector string, want bool) {
+	p.t.Helper()
+	cb := p.b.Find(selector)
+	if checked, _ := cb.IsChecked(); checked == want {
+		return
+	}
+	if err := cb.Click(); err != nil {
+		p.t.Fatalf("Failed to toggle %s: %v", selector, err)
+	}
+}
+
+// QueryInput returns the search text field.
+func (p *SearchPage) QueryInput() *e2e.Element { return p.b.Find(searchQuery) }
+
+// BibleSelect returns the translation dropdown.
+func (p *SearchPage) BibleSelect() *e2e.Element { return p.b.Find(searchBible) }
+
+// CaseSensitive returns the case-sensitive checkbox.
+func (p *SearchPage) CaseSensitive() *e2e.Element { return p.b.Find(searchCaseSensitive) }
+
+// WholeWord returns the whole-word checkbox.
+func (p *SearchPage) WholeWord() *e2e.Element { return p.b.Find(searchWholeWord) }
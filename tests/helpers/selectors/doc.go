@@ -0,0 +1,10 @@
+// Package selectors holds a CSS selector constant for every element ID and
+// class defined by the site's templates and scripts, such as IDBookSelect for
+// #book-select. Patterns whose names are partly rendered, like
+// id="bible-{{ .id }}", end in Fmt and take the rendered part as %s.
+//
+// Page objects build their selectors from these constants, so renaming an ID
+// or class in a template breaks the build once the package is regenerated.
+package selectors
+
+//go:generate go run ../../cmd/selectorgen -root ../../.. -out selectors_gen.go
@@ -0,0 +1,611 @@
+// Code generated by selectorgen; DO NOT EDIT.
+
+package selectors
+
+// Element ids.
+const (
+	// IDAllVersesBtn is defined in assets/js/parallel.js.
+	IDAllVersesBtn = "#all-verses-btn"
+	// IDAllVersesBtnFmt is defined in layouts/partials/michael/verse-grid.html.
+	IDAllVersesBtnFmt = "#%sall-verses-btn"
+	// IDBibleData is defined in layouts/bible/compare.html.
+	IDBibleData = "#bible-data"
+	// IDBibleFmt is defined in layouts/partials/michael/offline-settings.html.
+	IDBibleFmt = "#bible-%s"
+	// IDBibleGrid is defined in layouts/bible/list.html.
+	IDBibleGrid = "#bible-grid"
+	// IDBibleIndex is defined in layouts/bible/search.html.
+	IDBibleIndex = "#bible-index"
+	// IDBibleSelect is defined in layouts/bible/search.html and layouts/partials/michael/bible-nav-translation-select.html.
+	IDBibleSelect = "#bible-select"
+	// IDBookSelect is defined in layouts/bible/compare.html and layouts/partials/michael/bible-nav-book-select.html.
+	IDBookSelect = "#book-select"
+	// IDCacheSize is defined in layouts/partials/michael/offline-settings.html.
+	IDCacheSize = "#cache-size"
+	// IDCachedChaptersCount is defined in layouts/partials/michael/offline-settings.html.
+	IDCachedChaptersCount = "#cached-chapters-count"
+	// IDCachedList is defined in static/offline.html.
+	IDCachedList = "#cached-list"
+	// IDCaseSensitive is defined in layouts/bible/search.html.
+	IDCaseSensitive = "#case-sensitive"
+	// IDChapterContent is defined in layouts/partials/michael/bible-single-content.html.
+	IDChapterContent = "#chapter-content"
+	// IDChapterNotesRow is defined in layouts/partials/michael/bible-single-content.html.
+	IDChapterNotesRow = "#chapter-notes-row"
+	// IDChapterSelect is defined in layouts/bible/compare.html and layouts/partials/michael/bible-nav-chapter-nav.html.
+	IDChapterSelect = "#chapter-select"
+	// IDClearCacheBtn is defined in layouts/partials/michael/offline-settings.html.
+	IDClearCacheBtn = "#clear-cache-btn"
+	// IDClearDescription is defined in layouts/partials/michael/offline-settings.html.
+	IDClearDescription = "#clear-description"
+	// IDColorBtnFmt is defined in layouts/partials/michael/color-picker.html.
+	IDColorBtnFmt = "#%s-color-btn"
+	// IDColorPickerFmt is defined in layouts/partials/michael/color-picker.html.
+	IDColorPickerFmt = "#%s-color-picker"
+	// IDCompareAnnouncer is defined in layouts/bible/compare.html.
+	IDCompareAnnouncer = "#compare-announcer"
+	// IDConnectionStatus is defined in static/offline.html.
+	IDConnectionStatus = "#connection-status"
+	// IDContinueReading is defined in layouts/partials/michael/continue-reading.html.
+	IDContinueReading = "#continue-reading"
+	// IDContinueReadingDismiss is defined in layouts/partials/michael/continue-reading.html.
+	IDContinueReadingDismiss = "#continue-reading-dismiss"
+	// IDContinueReadingLink is defined in layouts/partials/michael/continue-reading.html.
+	IDContinueReadingLink = "#continue-reading-link"
+	// IDContinueReadingTitle is defined in layouts/partials/michael/continue-reading.html.
+	IDContinueReadingTitle = "#continue-reading-title"
+	// IDDiffLegendFmt is defined in layouts/partials/michael/diff-legend.html.
+	IDDiffLegendFmt = "#%sdiff-legend"
+	// IDDownloadDescription is defined in layouts/partials/michael/offline-settings.html.
+	IDDownloadDescription = "#download-description"
+	// IDDownloadOfflineBtn is defined in layouts/partials/michael/offline-settings.html.
+	IDDownloadOfflineBtn = "#download-offline-btn"
+	// IDDownloadProgressBar is defined in layouts/partials/michael/offline-settings.html.
+	IDDownloadProgressBar = "#download-progress-bar"
+	// IDDownloadProgressContainer is defined in layouts/partials/michael/offline-settings.html.
+	IDDownloadProgressContainer = "#download-progress-container"
+	// IDDownloadProgressLabel is defined in layouts/partials/michael/offline-settings.html.
+	IDDownloadProgressLabel = "#download-progress-label"
+	// IDDownloadProgressText is defined in layouts/partials/michael/offline-settings.html.
+	IDDownloadProgressText = "#download-progress-text"
+	// IDFootnotesListFmt is defined in layouts/partials/michael/notes-section.html.
+	IDFootnotesListFmt = "#%sfootnotes-list"
+	// IDFootnotesSectionFmt is defined in layouts/partials/michael/notes-section.html.
+	IDFootnotesSectionFmt = "#%sfootnotes-section"
+	// IDHighlight is defined in layouts/bible/compare.html.
+	IDHighlight = "#highlight"
+	// IDHighlightToggle is defined in layouts/bible/compare.html.
+	IDHighlightToggle = "#highlight-toggle"
+	// IDMainContent is defined in layouts/404.html.
+	IDMainContent = "#main-content"
+	// IDNormalMode is defined in layouts/bible/compare.html.
+	IDNormalMode = "#normal-mode"
+	// IDOfflineDownloadForm is defined in layouts/partials/michael/offline-settings.html.
+	IDOfflineDownloadForm = "#offline-download-form"
+	// IDOfflineMessages is defined in layouts/partials/michael/offline-settings.html.
+	IDOfflineMessages = "#offline-messages"
+	// IDParallelContent is defined in layouts/bible/compare.html.
+	IDParallelContent = "#parallel-content"
+	// IDPwaInstallBanner is defined in layouts/partials/michael/pwa-install-banner.html.
+	IDPwaInstallBanner = "#pwa-install-banner"
+	// IDPwaInstallBtn is defined in layouts/partials/michael/pwa-install-banner.html.
+	IDPwaInstallBtn = "#pwa-install-btn"
+	// IDPwaInstallDismiss is defined in layouts/partials/michael/pwa-install-banner.html.
+	IDPwaInstallDismiss = "#pwa-install-dismiss"
+	// IDPwaIosDismiss is defined in layouts/partials/michael/pwa-install-banner.html.
+	IDPwaIosDismiss = "#pwa-ios-dismiss"
+	// IDPwaIosInstructions is defined in layouts/partials/michael/pwa-install-banner.html.
+	IDPwaIosInstructions = "#pwa-ios-instructions"
+	// IDSearchAnnouncer is defined in layouts/bible/search.html.
+	IDSearchAnnouncer = "#search-announcer"
+	// IDSearchForm is defined in layouts/bible/search.html.
+	IDSearchForm = "#search-form"
+	// IDSearchQuery is defined in layouts/bible/search.html.
+	IDSearchQuery = "#search-query"
+	// IDSearchResults is defined in layouts/bible/search.html.
+	IDSearchResults = "#search-results"
+	// IDSearchStatus is defined in layouts/bible/search.html.
+	IDSearchStatus = "#search-status"
+	// IDShowMoreBibles is defined in layouts/bible/list.html.
+	IDShowMoreBibles = "#show-more-bibles"
+	// IDSss is defined in layouts/bible/compare.html.
+	IDSss = "#sss"
+	// IDSssAllVersesBtn is defined in assets/js/parallel.js.
+	IDSssAllVersesBtn = "#sss-all-verses-btn"
+	// IDSssBackBtn is defined in layouts/bible/compare.html.
+	IDSssBackBtn = "#sss-back-btn"
+	// IDSssBibleLeft is defined in layouts/bible/compare.html.
+	IDSssBibleLeft = "#sss-bible-left"
+	// IDSssBibleRight is defined in layouts/bible/compare.html.
+	IDSssBibleRight = "#sss-bible-right"
+	// IDSssBookSelect is defined in layouts/bible/compare.html.
+	IDSssBookSelect = "#sss-book-select"
+	// IDSssChapterSelect is defined in layouts/bible/compare.html.
+	IDSssChapterSelect = "#sss-chapter-select"
+	// IDSssHighlight is defined in layouts/bible/compare.html.
+	IDSssHighlight = "#sss-highlight"
+	// IDSssHighlightToggle is defined in layouts/bible/compare.html.
+	IDSssHighlightToggle = "#sss-highlight-toggle"
+	// IDSssLeftPane is defined in layouts/bible/compare.html.
+	IDSssLeftPane = "#sss-left-pane"
+	// IDSssMode is defined in layouts/bible/compare.html.
+	IDSssMode = "#sss-mode"
+	// IDSssModeBtn is defined in layouts/bible/compare.html.
+	IDSssModeBtn = "#sss-mode-btn"
+	// IDSssNotesRow is defined in layouts/bible/compare.html.
+	IDSssNotesRow = "#sss-notes-row"
+	// IDSssPanes is defined in layouts/bible/compare.html.
+	IDSssPanes = "#sss-panes"
+	// IDSssRightPane is defined in layouts/bible/compare.html.
+	IDSssRightPane = "#sss-right-pane"
+	// IDSssToggleBtn is defined in layouts/bible/compare.html.
+	IDSssToggleBtn = "#sss-toggle-btn"
+	// IDStatusFmt is defined in layouts/partials/michael/offline-settings.html.
+	IDStatusFmt = "#status-%s"
+	// IDStrongsListFmt is defined in layouts/partials/michael/notes-section.html.
+	IDStrongsListFmt = "#%sstrongs-list"
+	// IDStrongsNoteFmt is defined in assets/js/strongs.js.
+	IDStrongsNoteFmt = "#strongs-note-%s"
+	// IDStrongsSectionFmt is defined in layouts/partials/michael/notes-section.html.
+	IDStrongsSectionFmt = "#%sstrongs-section"
+	// IDStrongsTooltip is defined in assets/js/strongs.js.
+	IDStrongsTooltip = "#strongs-tooltip"
+	// IDSwUpdateDismissBtn is defined in layouts/partials/michael/sw-register.html.
+	IDSwUpdateDismissBtn = "#sw-update-dismiss-btn"
+	// IDSwUpdateReloadBtn is defined in layouts/partials/michael/sw-register.html.
+	IDSwUpdateReloadBtn = "#sw-update-reload-btn"
+	// IDThemeIconDark is defined in layouts/partials/header.html.
+	IDThemeIconDark = "#theme-icon-dark"
+	// IDThemeIconLight is defined in layouts/partials/header.html.
+	IDThemeIconLight = "#theme-icon-light"
+	// IDThemeToggle is defined in layouts/partials/header.html.
+	IDThemeToggle = "#theme-toggle"
+	// IDTranslationCheckboxes is defined in layouts/bible/compare.html.
+	IDTranslationCheckboxes = "#translation-checkboxes"
+	// IDVerseButtonsFmt is defined in layouts/partials/michael/verse-grid.html.
+	IDVerseButtonsFmt = "#%sverse-buttons"
+	// IDVerseGridFmt is defined in layouts/partials/michael/verse-grid.html.
+	IDVerseGridFmt = "#%sverse-grid"
+	// IDVvvNotesRow is defined in layouts/bible/compare.html.
+	IDVvvNotesRow = "#vvv-notes-row"
+	// IDWholeWord is defined in layouts/bible/search.html.
+	IDWholeWord = "#whole-word"
+)
+
+// Element classs.
+const (
+	// ClassActions is defined in layouts/bible/list.html and 7 other files.
+	ClassActions = ".actions"
+	// ClassBadge is defined in layouts/_default/list.html and 6 other files.
+	ClassBadge = ".badge"
+	// ClassBadgeSuccess is defined in layouts/license/list.html and layouts/license/single.html.
+	ClassBadgeSuccess = ".badge--success"
+	// ClassBadgeWarning is defined in layouts/license/single.html.
+	ClassBadgeWarning = ".badge--warning"
+	// ClassBibleChip is defined in layouts/partials/michael/offline-settings.html.
+	ClassBibleChip = ".bible-chip"
+	// ClassBibleChipAbbrev is defined in layouts/partials/michael/offline-settings.html.
+	ClassBibleChipAbbrev = ".bible-chip__abbrev"
+	// ClassBibleChipStatus is defined in assets/js/michael/offline-settings-ui.js and layouts/partials/michael/offline-settings.html.
+	ClassBibleChipStatus = ".bible-chip__status"
+	// ClassBibleDownloadCheckbox is defined in layouts/partials/michael/offline-settings.html.
+	ClassBibleDownloadCheckbox = ".bible-download-checkbox"
+	// ClassBibleDownloadChips is defined in layouts/partials/michael/offline-settings.html.
+	ClassBibleDownloadChips = ".bible-download-chips"
+	// ClassBibleDownloadForm is defined in layouts/partials/michael/offline-settings.html.
+	ClassBibleDownloadForm = ".bible-download-form"
+	// ClassBibleDownloadSection is defined in layouts/partials/michael/offline-settings.html.
+	ClassBibleDownloadSection = ".bible-download-section"
+	// ClassBibleExtra is defined in layouts/bible/list.html.
+	ClassBibleExtra = ".bible-extra"
+	// ClassBibleGrid is defined in layouts/index.html.
+	ClassBibleGrid = ".bible-grid"
+	// ClassBibleNav is defined in layouts/partials/michael/bible-nav.html.
+	ClassBibleNav = ".bible-nav"
+	// ClassBibleNavSelect is defined in layouts/partials/michael/bible-nav-book-select.html and 2 other files.
+	ClassBibleNavSelect = ".bible-nav__select"
+	// ClassBibleText is defined in layouts/partials/michael/bible-single-content.html.
+	ClassBibleText = ".bible-text"
+	// ClassBookContent is defined in layouts/partials/michael/bible-single-content.html.
+	ClassBookContent = ".book-content"
+	// ClassBookGrid is defined in content/bible/_content.gotmpl.
+	ClassBookGrid = ".book-grid"
+	// ClassBookLink is defined in content/bible/_content.gotmpl.
+	ClassBookLink = ".book-link"
+	// ClassBtn is defined in layouts/404.html and 12 other files.
+	ClassBtn = ".btn"
+	// ClassBtnPrimary is defined in layouts/bible/search.html and 2 other files.
+	ClassBtnPrimary = ".btn--primary"
+	// ClassBtnSecondary is defined in layouts/bible/list.html and 4 other files.
+	ClassBtnSecondary = ".btn--secondary"
+	// ClassBtnSecondary2 is defined in static/offline.html.
+	ClassBtnSecondary2 = ".btn-secondary"
+	// ClassBtnSm is defined in layouts/bible/compare.html and 3 other files.
+	ClassBtnSm = ".btn--sm"
+	// ClassCacheStatus is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatus = ".cache-status"
+	// ClassCacheStatusInfo is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatusInfo = ".cache-status__info"
+	// ClassCacheStatusItem is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatusItem = ".cache-status__item"
+	// ClassCacheStatusLabel is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatusLabel = ".cache-status__label"
+	// ClassCacheStatusList is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatusList = ".cache-status__list"
+	// ClassCacheStatusTitle is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatusTitle = ".cache-status__title"
+	// ClassCacheStatusValue is defined in layouts/partials/michael/offline-settings.html.
+	ClassCacheStatusValue = ".cache-status__value"
+	// ClassCachedContent is defined in static/offline.html.
+	ClassCachedContent = ".cached-content"
+	// ClassCard is defined in layouts/_default/list.html and 3 other files.
+	ClassCard = ".card"
+	// ClassCardDescription is defined in layouts/bible/list.html and layouts/index.html.
+	ClassCardDescription = ".card__description"
+	// ClassCardFooter is defined in layouts/bible/list.html and layouts/index.html.
+	ClassCardFooter = ".card__footer"
+	// ClassCardMeta is defined in layouts/_default/list.html and layouts/license/single.html.
+	ClassCardMeta = ".card__meta"
+	// ClassCardTitle is defined in layouts/_default/list.html and 3 other files.
+	ClassCardTitle = ".card__title"
+	// ClassCenter is defined in assets/js/bible-search.js and 2 other files.
+	ClassCenter = ".center"
+	// ClassChapterGrid is defined in content/bible/_content.gotmpl.
+	ClassChapterGrid = ".chapter-grid"
+	// ClassChapterLink is defined in content/bible/_content.gotmpl.
+	ClassChapterLink = ".chapter-link"
+	// ClassChapterNotesRow is defined in layouts/partials/michael/bible-single-content.html.
+	ClassChapterNotesRow = ".chapter-notes-row"
+	// ClassChip is defined in assets/js/parallel.js and 4 other files.
+	ClassChip = ".chip"
+	// ClassChipCompact is defined in layouts/bible/compare.html.
+	ClassChipCompact = ".chip--compact"
+	// ClassChrome is defined in layouts/partials/footer.html and layouts/partials/header.html.
+	ClassChrome = ".chrome"
+	// ClassChromeBar is defined in layouts/partials/header.html.
+	ClassChromeBar = ".chrome__bar"
+	// ClassColorOption is defined in layouts/bible/compare.html.
+	ClassColorOption = ".color-option"
+	// ClassColorOptionBtn is defined in layouts/partials/michael/color-picker.html.
+	ClassColorOptionBtn = ".color-option-btn"
+	// ClassColorPickerBtn is defined in layouts/partials/michael/color-picker.html.
+	ClassColorPickerBtn = ".color-picker-btn"
+	// ClassColorPickerDropdown is defined in layouts/partials/michael/color-picker.html.
+	ClassColorPickerDropdown = ".color-picker-dropdown"
+	// ClassColorPickerOptions is defined in layouts/partials/michael/color-picker.html.
+	ClassColorPickerOptions = ".color-picker-options"
+	// ClassCompareContent is defined in layouts/bible/compare.html.
+	ClassCompareContent = ".compare-content"
+	// ClassCompareControls is defined in layouts/bible/compare.html.
+	ClassCompareControls = ".compare-controls"
+	// ClassCompareControlsCompact is defined in layouts/bible/compare.html.
+	ClassCompareControlsCompact = ".compare-controls--compact"
+	// ClassCompareControlsRow is defined in layouts/bible/compare.html.
+	ClassCompareControlsRow = ".compare-controls__row"
+	// ClassCompareControlsTranslations is defined in layouts/bible/compare.html.
+	ClassCompareControlsTranslations = ".compare-controls__translations"
+	// ClassCompareNotesRow is defined in layouts/bible/compare.html.
+	ClassCompareNotesRow = ".compare-notes-row"
+	// ClassComparePane is defined in layouts/bible/compare.html.
+	ClassComparePane = ".compare-pane"
+	// ClassComparePaneWrapper is defined in layouts/bible/compare.html.
+	ClassComparePaneWrapper = ".compare-pane-wrapper"
+	// ClassComparePanes is defined in layouts/bible/compare.html.
+	ClassComparePanes = ".compare-panes"
+	// ClassContainer is defined in layouts/404.html and 12 other files.
+	ClassContainer = ".container"
+	// ClassContinueReading is defined in layouts/partials/michael/continue-reading.html.
+	ClassContinueReading = ".continue-reading"
+	// ClassContinueReadingArrow is defined in layouts/partials/michael/continue-reading.html.
+	ClassContinueReadingArrow = ".continue-reading__arrow"
+	// ClassContinueReadingChapter is defined in layouts/partials/michael/continue-reading.html.
+	ClassContinueReadingChapter = ".continue-reading__chapter"
+	// ClassContinueReadingContent is defined in layouts/partials/michael/continue-reading.html.
+	ClassContinueReadingContent = ".continue-reading__content"
+	// ClassContinueReadingDismiss is defined in layouts/partials/michael/continue-reading.html.
+	ClassContinueReadingDismiss = ".continue-reading__dismiss"
+	// ClassContinueReadingLabel is defined in layouts/partials/michael/continue-reading.html.
+	ClassContinueReadingLabel = ".continue-reading__label"
+	// ClassCopied is defined in assets/js/michael/share-menu.js and assets/js/share.js.
+	ClassCopied = ".copied"
+	// ClassCursorPointer is defined in layouts/bible/compare.html and layouts/partials/michael/compare-diff-control.html.
+	ClassCursorPointer = ".cursor-pointer"
+	// ClassDiffFmt is defined in assets/js/parallel.js and assets/js/text-compare.js.
+	ClassDiffFmt = ".diff-%s"
+	// ClassDiffInsert is defined in assets/js/michael/diff-highlight.js and assets/js/parallel.js.
+	ClassDiffInsert = ".diff-insert"
+	// ClassDiffLegend is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegend = ".diff-legend"
+	// ClassDiffLegendItem is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendItem = ".diff-legend__item"
+	// ClassDiffLegendSwatch is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendSwatch = ".diff-legend__swatch"
+	// ClassDiffLegendSwatchAdd is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendSwatchAdd = ".diff-legend__swatch--add"
+	// ClassDiffLegendSwatchOmit is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendSwatchOmit = ".diff-legend__swatch--omit"
+	// ClassDiffLegendSwatchPunct is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendSwatchPunct = ".diff-legend__swatch--punct"
+	// ClassDiffLegendSwatchSpelling is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendSwatchSpelling = ".diff-legend__swatch--spelling"
+	// ClassDiffLegendSwatchSubst is defined in layouts/partials/michael/diff-legend.html.
+	ClassDiffLegendSwatchSubst = ".diff-legend__swatch--subst"
+	// ClassDownloadProgress is defined in layouts/partials/michael/offline-settings.html.
+	ClassDownloadProgress = ".download-progress"
+	// ClassDownloadProgressBar is defined in layouts/partials/michael/offline-settings.html.
+	ClassDownloadProgressBar = ".download-progress__bar"
+	// ClassDownloadProgressContainer is defined in layouts/partials/michael/offline-settings.html.
+	ClassDownloadProgressContainer = ".download-progress-container"
+	// ClassDownloadProgressLabel is defined in layouts/partials/michael/offline-settings.html.
+	ClassDownloadProgressLabel = ".download-progress__label"
+	// ClassDownloadProgressText is defined in layouts/partials/michael/offline-settings.html.
+	ClassDownloadProgressText = ".download-progress__text"
+	// ClassErrorPage is defined in layouts/404.html.
+	ClassErrorPage = ".error-page"
+	// ClassField is defined in layouts/bible/search.html.
+	ClassField = ".field"
+	// ClassFlex1 is defined in layouts/bible/search.html.
+	ClassFlex1 = ".flex-1"
+	// ClassFooterCopyright is defined in layouts/partials/footer.html.
+	ClassFooterCopyright = ".footer-copyright"
+	// ClassFooterCopyrightText is defined in layouts/partials/footer.html.
+	ClassFooterCopyrightText = ".footer-copyright__text"
+	// ClassFootnoteBackref is defined in assets/js/michael/footnotes.js.
+	ClassFootnoteBackref = ".footnote-backref"
+	// ClassFootnoteCatchword is defined in assets/js/michael/footnotes.js.
+	ClassFootnoteCatchword = ".footnote-catchword"
+	// ClassFootnoteLiteral is defined in assets/js/michael/footnotes.js.
+	ClassFootnoteLiteral = ".footnote-literal"
+	// ClassFootnoteNum is defined in assets/js/michael/footnotes.js.
+	ClassFootnoteNum = ".footnote-num"
+	// ClassFootnoteRef is defined in assets/js/michael/footnotes.js.
+	ClassFootnoteRef = ".footnote-ref"
+	// ClassFootnotesList is defined in layouts/partials/michael/notes-section.html.
+	ClassFootnotesList = ".footnotes-list"
+	// ClassFootnotesSectionFmt is defined in layouts/partials/michael/notes-section.html.
+	ClassFootnotesSectionFmt = ".footnotes-section%s"
+	// ClassFullWidthMode is defined in assets/js/michael/chapter-reader.js.
+	ClassFullWidthMode = ".full-width-mode"
+	// ClassGap1 is defined in layouts/license/list.html and layouts/license/single.html.
+	ClassGap1 = ".gap-1"
+	// ClassGap2 is defined in layouts/license/list.html.
+	ClassGap2 = ".gap-2"
+	// ClassGrid2 is defined in layouts/_default/list.html and 3 other files.
+	ClassGrid2 = ".grid-2"
+	// ClassHidden is defined in assets/js/bible-search.js and 17 other files.
+	ClassHidden = ".hidden"
+	// ClassHighlightVerse is defined in assets/js/share.js.
+	ClassHighlightVerse = ".highlight-verse"
+	// ClassIcon is defined in static/offline.html.
+	ClassIcon = ".icon"
+	// ClassInput is defined in layouts/bible/search.html.
+	ClassInput = ".input"
+	// ClassIsActive is defined in assets/js/michael/bible-filter.js and 3 other files.
+	ClassIsActive = ".is-active"
+	// ClassIsCached is defined in assets/js/michael/offline-settings-ui.js.
+	ClassIsCached = ".is-cached"
+	// ClassLabel is defined in layouts/bible/search.html.
+	ClassLabel = ".label"
+	// ClassLicenseContent is defined in layouts/license/single.html.
+	ClassLicenseContent = ".license-content"
+	// ClassLicenseDetails is defined in layouts/license/single.html.
+	ClassLicenseDetails = ".license-details"
+	// ClassLicenseRightsCol is defined in layouts/partials/michael/license-rights-col.html.
+	ClassLicenseRightsCol = ".license-rights-col"
+	// ClassLicenseRightsColFmt is defined in layouts/partials/michael/license-rights-col.html.
+	ClassLicenseRightsColFmt = ".license-rights-col--%s"
+	// ClassLicenseRightsGrid is defined in layouts/license/single.html.
+	ClassLicenseRightsGrid = ".license-rights-grid"
+	// ClassLicenseSection is defined in layouts/license/list.html and layouts/license/single.html.
+	ClassLicenseSection = ".license-section"
+	// ClassLicenseTable is defined in layouts/license/list.html and layouts/partials/michael/dep-table.html.
+	ClassLicenseTable = ".license-table"
+	// ClassLicenseTextDisplay is defined in layouts/license/single.html.
+	ClassLicenseTextDisplay = ".license-text-display"
+	// ClassLoading is defined in layouts/partials/michael/offline-settings.html.
+	ClassLoading = ".loading"
+	// ClassLogo is defined in static/offline.html.
+	ClassLogo = ".logo"
+	// ClassMb0 is defined in layouts/license/list.html and layouts/license/single.html.
+	ClassMb0 = ".mb-0"
+	// ClassMb2 is defined in layouts/bible/list.html and layouts/license/single.html.
+	ClassMb2 = ".mb-2"
+	// ClassMl1 is defined in layouts/license/list.html.
+	ClassMl1 = ".ml-1"
+	// ClassMr1 is defined in layouts/partials/michael/compare-diff-control.html.
+	ClassMr1 = ".mr-1"
+	// ClassMt0 is defined in layouts/license/list.html and layouts/license/single.html.
+	ClassMt0 = ".mt-0"
+	// ClassMt1 is defined in layouts/_default/list.html and 2 other files.
+	ClassMt1 = ".mt-1"
+	// ClassMt2 is defined in layouts/bible/list.html and 2 other files.
+	ClassMt2 = ".mt-2"
+	// ClassMt3 is defined in layouts/bible/search.html.
+	ClassMt3 = ".mt-3"
+	// ClassMtXs is defined in layouts/license/list.html.
+	ClassMtXs = ".mt-xs"
+	// ClassMuted is defined in assets/js/bible-search.js and 6 other files.
+	ClassMuted = ".muted"
+	// ClassNav is defined in layouts/partials/footer.html and layouts/partials/header.html.
+	ClassNav = ".nav"
+	// ClassNoscriptNotice is defined in layouts/partials/michael/offline-settings.html.
+	ClassNoscriptNotice = ".noscript-notice"
+	// ClassNotice is defined in assets/js/michael/bible-nav.js and 2 other files.
+	ClassNotice = ".notice"
+	// ClassOffline is defined in static/offline.html.
+	ClassOffline = ".offline"
+	// ClassOfflineMessage is defined in assets/js/michael/offline-settings-ui.js.
+	ClassOfflineMessage = ".offline-message"
+	// ClassOfflineMessageFmt is defined in assets/js/michael/offline-settings-ui.js.
+	ClassOfflineMessageFmt = ".offline-message--%s"
+	// ClassOfflineMessages is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineMessages = ".offline-messages"
+	// ClassOfflineSettings is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettings = ".offline-settings"
+	// ClassOfflineSettingsActions is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsActions = ".offline-settings__actions"
+	// ClassOfflineSettingsContent is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsContent = ".offline-settings__content"
+	// ClassOfflineSettingsDetails is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsDetails = ".offline-settings__details"
+	// ClassOfflineSettingsSpacing is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsSpacing = ".offline-settings--spacing"
+	// ClassOfflineSettingsSubtitle is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsSubtitle = ".offline-settings__subtitle"
+	// ClassOfflineSettingsSummary is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsSummary = ".offline-settings__summary"
+	// ClassOfflineSettingsTitle is defined in layouts/partials/michael/offline-settings.html.
+	ClassOfflineSettingsTitle = ".offline-settings__title"
+	// ClassPad32 is defined in layouts/_default/list.html and 7 other files.
+	ClassPad32 = ".pad-3-2"
+	// ClassPageHeader is defined in layouts/_default/list.html and 8 other files.
+	ClassPageHeader = ".page-header"
+	// ClassPanel is defined in layouts/_default/list.html and 9 other files.
+	ClassPanel = ".panel"
+	// ClassPanelInner is defined in layouts/bible/search.html and 3 other files.
+	ClassPanelInner = ".panel--inner"
+	// ClassParallelVerse is defined in assets/js/michael/sss-mode.js and assets/js/parallel.js.
+	ClassParallelVerse = ".parallel-verse"
+	// ClassParallelVerseNum is defined in assets/js/michael/sss-mode.js and assets/js/parallel.js.
+	ClassParallelVerseNum = ".parallel-verse-num"
+	// ClassProse is defined in layouts/_default/single.html and layouts/partials/michael/bible-single-content.html.
+	ClassProse = ".prose"
+	// ClassPwaHome is defined in layouts/index.html.
+	ClassPwaHome = ".pwa-home"
+	// ClassPwaInstallActions is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallActions = ".pwa-install-actions"
+	// ClassPwaInstallBanner is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallBanner = ".pwa-install-banner"
+	// ClassPwaInstallButton is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallButton = ".pwa-install-button"
+	// ClassPwaInstallContent is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallContent = ".pwa-install-content"
+	// ClassPwaInstallDismiss is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallDismiss = ".pwa-install-dismiss"
+	// ClassPwaInstallIcon is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallIcon = ".pwa-install-icon"
+	// ClassPwaInstallText is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaInstallText = ".pwa-install-text"
+	// ClassPwaIosInstructions is defined in layouts/partials/michael/pwa-install-banner.html.
+	ClassPwaIosInstructions = ".pwa-ios-instructions"
+	// ClassReaderBar is defined in layouts/partials/michael/bible-nav.html.
+	ClassReaderBar = ".reader-bar"
+	// ClassRow is defined in layouts/_default/list.html and 7 other files.
+	ClassRow = ".row"
+	// ClassRowCenter is defined in layouts/partials/michael/bible-single-header.html.
+	ClassRowCenter = ".row--center"
+	// ClassRowSpaceBetween is defined in layouts/license/list.html.
+	ClassRowSpaceBetween = ".row--space-between"
+	// ClassRowWrap is defined in layouts/license/list.html.
+	ClassRowWrap = ".row--wrap"
+	// ClassSearchResult is defined in assets/js/bible-search.js.
+	ClassSearchResult = ".search-result"
+	// ClassSelect is defined in layouts/bible/compare.html and 6 other files.
+	ClassSelect = ".select"
+	// ClassSelectCompact is defined in layouts/bible/compare.html.
+	ClassSelectCompact = ".select--compact"
+	// ClassShareMenu is defined in assets/js/michael/share-menu.js.
+	ClassShareMenu = ".share-menu"
+	// ClassShareMenuDivider is defined in assets/js/michael/share-menu.js.
+	ClassShareMenuDivider = ".share-menu-divider"
+	// ClassShareMenuItem is defined in assets/js/michael/share-menu.js.
+	ClassShareMenuItem = ".share-menu-item"
+	// ClassShareMenuOffline is defined in assets/js/michael/share-menu.js.
+	ClassShareMenuOffline = ".share-menu--offline"
+	// ClassShareToast is defined in assets/js/share.js.
+	ClassShareToast = ".share-toast"
+	// ClassShareToastVisible is defined in assets/js/share.js.
+	ClassShareToastVisible = ".share-toast--visible"
+	// ClassShareWrapper is defined in assets/js/share.js.
+	ClassShareWrapper = ".share-wrapper"
+	// ClassSkipLink is defined in layouts/_default/baseof.html.
+	ClassSkipLink = ".skip-link"
+	// ClassSrOnly is defined in layouts/bible/compare.html and 7 other files.
+	ClassSrOnly = ".sr-only"
+	// ClassSssBiblePair is defined in layouts/bible/compare.html.
+	ClassSssBiblePair = ".sss-bible-pair"
+	// ClassSssChapterMode is defined in assets/js/michael/chapter-reader.js.
+	ClassSssChapterMode = ".sss-chapter-mode"
+	// ClassSssColorOption is defined in layouts/bible/compare.html.
+	ClassSssColorOption = ".sss-color-option"
+	// ClassSssLoading is defined in assets/js/michael/chapter-reader.js.
+	ClassSssLoading = ".sss-loading"
+	// ClassSssMissing is defined in assets/js/michael/chapter-reader.js.
+	ClassSssMissing = ".sss-missing"
+	// ClassSssToggleIcon is defined in layouts/partials/michael/sss-toggle.html.
+	ClassSssToggleIcon = ".sss-toggle-icon"
+	// ClassSssVerseBtn is defined in assets/js/michael/sss-mode.js and assets/js/parallel.js.
+	ClassSssVerseBtn = ".sss-verse-btn"
+	// ClassSssVerseLeft is defined in assets/js/michael/chapter-reader.js.
+	ClassSssVerseLeft = ".sss-verse-left"
+	// ClassSssVerseRight is defined in assets/js/michael/chapter-reader.js.
+	ClassSssVerseRight = ".sss-verse-right"
+	// ClassSssVerseRow is defined in assets/js/michael/chapter-reader.js.
+	ClassSssVerseRow = ".sss-verse-row"
+	// ClassStatus is defined in static/offline.html.
+	ClassStatus = ".status"
+	// ClassStrongsDef is defined in assets/js/strongs.js.
+	ClassStrongsDef = ".strongs-def"
+	// ClassStrongsDefinition is defined in assets/js/strongs.js.
+	ClassStrongsDefinition = ".strongs-definition"
+	// ClassStrongsDeriv is defined in assets/js/strongs.js.
+	ClassStrongsDeriv = ".strongs-deriv"
+	// ClassStrongsLemma is defined in assets/js/strongs.js.
+	ClassStrongsLemma = ".strongs-lemma"
+	// ClassStrongsNotesList is defined in layouts/partials/michael/notes-section.html.
+	ClassStrongsNotesList = ".strongs-notes-list"
+	// ClassStrongsNotesSection is defined in layouts/partials/michael/notes-section.html.
+	ClassStrongsNotesSection = ".strongs-notes-section"
+	// ClassStrongsNumber is defined in assets/js/strongs.js.
+	ClassStrongsNumber = ".strongs-number"
+	// ClassStrongsRef is defined in assets/js/strongs.js.
+	ClassStrongsRef = ".strongs-ref"
+	// ClassStrongsTooltip is defined in assets/js/strongs.js.
+	ClassStrongsTooltip = ".strongs-tooltip"
+	// ClassStrongsWord is defined in assets/js/strongs.js.
+	ClassStrongsWord = ".strongs-word"
+	// ClassSwUpdateBannerActions is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerActions = ".sw-update-banner__actions"
+	// ClassSwUpdateBannerContent is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerContent = ".sw-update-banner__content"
+	// ClassSwUpdateBannerDismissBtn is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerDismissBtn = ".sw-update-banner__dismiss-btn"
+	// ClassSwUpdateBannerIcon is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerIcon = ".sw-update-banner__icon"
+	// ClassSwUpdateBannerMessage is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerMessage = ".sw-update-banner__message"
+	// ClassSwUpdateBannerReloadBtn is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerReloadBtn = ".sw-update-banner__reload-btn"
+	// ClassSwUpdateBannerText is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerText = ".sw-update-banner__text"
+	// ClassSwUpdateBannerTitle is defined in layouts/partials/michael/sw-register.html.
+	ClassSwUpdateBannerTitle = ".sw-update-banner__title"
+	// ClassThemeToggle is defined in layouts/partials/header.html.
+	ClassThemeToggle = ".theme-toggle"
+	// ClassThemeToggleIcon is defined in layouts/partials/header.html.
+	ClassThemeToggleIcon = ".theme-toggle__icon"
+	// ClassToast is defined in assets/js/michael/dom-utils.js.
+	ClassToast = ".toast"
+	// ClassToastTop is defined in assets/js/michael/dom-utils.js.
+	ClassToastTop = ".toast--top"
+	// ClassToastVisible is defined in assets/js/michael/dom-utils.js.
+	ClassToastVisible = ".toast--visible"
+	// ClassTranslationCheckbox is defined in layouts/bible/compare.html.
+	ClassTranslationCheckbox = ".translation-checkbox"
+	// ClassTranslationLabel is defined in assets/js/michael/sss-mode.js and assets/js/parallel.js.
+	ClassTranslationLabel = ".translation-label"
+	// ClassVerse is defined in content/bible/_content.gotmpl.
+	ClassVerse = ".verse"
+	// ClassVerseBtn is defined in assets/js/michael/sss-mode.js and assets/js/parallel.js.
+	ClassVerseBtn = ".verse-btn"
+	// ClassVerseButtonsRow is defined in layouts/partials/michael/verse-grid.html.
+	ClassVerseButtonsRow = ".verse-buttons-row"
+	// ClassVerseGridRow is defined in layouts/partials/michael/verse-grid.html.
+	ClassVerseGridRow = ".verse-grid-row"
+	// ClassVerseShareBtn is defined in assets/js/share.js.
+	ClassVerseShareBtn = ".verse-share-btn"
+	// ClassVerseText is defined in assets/js/bible-search.js.
+	ClassVerseText = ".verse-text"
+	// ClassWAuto is defined in layouts/partials/michael/bible-select.html and layouts/partials/michael/book-chapter-selects.html.
+	ClassWAuto = ".w-auto"
+)
@@ -0,0 +1,354 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"michael-tests/fixtures"
+)
+
+// =============================================================================
+// Test Server Lifecycle
+// =============================================================================
+
+// contentSecurityPolicy matches the CSP set in the Caddyfile (and baseof.html).
+const contentSecurityPolicy = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; connect-src 'self'; font-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self';"
+
+// securityHeaders are applied to every response, mirroring the Caddyfile header block.
+var securityHeaders = map[string]string{
+	"Content-Security-Policy": contentSecurityPolicy,
+	"X-Content-Type-Options":  "nosniff",
+	"X-Frame-Options":         "DENY",
+	"Referrer-Policy":         "strict-origin-when-cross-origin",
+	"Permissions-Policy":      "accelerometer=(), camera=(), geolocation=(), gyroscope=(), magnetometer=(), microphone=(), payment=(), usb=()",
+}
+
+// staticExtensions are the paths the Caddyfile @static matcher marks as immutable.
+var staticExtensions = map[string]bool{
+	".css": true, ".js": true, ".png": true, ".jpg": true, ".jpeg": true,
+	".gif": true, ".ico": true, ".svg": true, ".woff": true, ".woff2": true,
+}
+
+// ServerOptions configures how the test site is built and served.
+type ServerOptions struct {
+	// SiteDir is the Hugo project root. Defaults to the repository root.
+	SiteDir string
+	// Hugo is the hugo binary. Defaults to $HUGO, tools/hugo/hugo, then hugo on PATH.
+	Hugo string
+	// DataDir replaces the data/example mount with another data tree, such as
+	// one written by the fixtures package. Empty keeps the vendored Bibles.
+	DataDir string
+	// Languages builds the site in each of these i18n languages, with English
+	// as the default at the root and the others under /<code>/. Empty builds
+	// the single-language site hugo.toml describes.
+	Languages []string
+	// BuildTimeout bounds the hugo build. Defaults to 5 minutes.
+	BuildTimeout time.Duration
+	// NoMinify builds without --minify, so served scripts keep the lines of
+	// their sources for JavaScript coverage.
+	NoMinify bool
+}
+
+// Server is an in-process HTTP server serving a freshly built copy of the site.
+type Server struct {
+	// URL is the base URL of the server without a trailing slash.
+	URL string
+	// Faults injects failures into the server's responses. It sits behind
+	// the Caddyfile headers, so faulted responses still carry them.
+	Faults *FaultInjector
+
+	dir      string
+	listener net.Listener
+	http     *http.Server
+}
+
+// StartServer builds the site into a temporary directory and serves it on a random
+// loopback port with the same security and cache headers as the Caddyfile.
+func StartServer(opts ServerOptions) (*Server, error) {
+	siteDir := opts.SiteDir
+	if siteDir == "" {
+		root, err := findSiteRoot()
+		if err != nil {
+			return nil, err
+		}
+		siteDir = root
+	}
+
+	// Listen first so the port is known before Hugo bakes it into the build
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	url := "http://" + listener.Addr().String()
+
+	dir, err := os.MkdirTemp("", "michael-site-")
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to create build directory: %w", err)
+	}
+
+	s := &Server{URL: url, dir: dir, listener: listener}
+	if err := buildSite(opts, siteDir, dir, url); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	s.Faults = NewFaultInjector(http.FileServer(http.Dir(filepath.Join(dir, "public"))))
+	s.http = &http.Server{
+		Handler:           withCaddyHeaders(s.Faults),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go s.http.Serve(listener)
+	return s, nil
+}
+
+// Close stops the server and removes the built site.
+func (s *Server) Close() {
+	if s.http != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.http.Shutdown(ctx)
+	} else if s.listener != nil {
+		s.listener.Close()
+	}
+	os.RemoveAll(s.dir)
+}
+
+// RunTests is the body of a package TestMain. It builds and serves the site,
+// points BaseURL at it, runs the tests and tears the server down again.
+// Setting BASE_URL skips the build and runs against an already running server;
+// setting MICHAEL_TEST_DATA=fixtures builds against the synthetic fixture Bibles.
+// Browsers come from a shared BrowserPool; see runWithPool. With -jscover the
+// site is built unminified and the run also reports JavaScript coverage; see
+// runWithCoverage.
+func RunTests(m *testing.M) int {
+	if external := os.Getenv("BASE_URL"); external != "" {
+		BaseURL = strings.TrimSuffix(external, "/")
+		return runWithCoverage(m)
+	}
+
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	opts := ServerOptions{NoMinify: *jsCoverDir != ""}
+	if os.Getenv("MICHAEL_TEST_DATA") == "fixtures" {
+		dataDir, err := fixtures.Default().WriteTemp()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write fixtures: %v\n", err)
+			return 1
+		}
+		defer os.RemoveAll(dataDir)
+		opts.DataDir = dataDir
+		sourceDataDir = dataDir
+	}
+
+	srv, err := StartServer(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to start test server: %v\n", err)
+		return 1
+	}
+	defer srv.Close()
+
+	BaseURL = srv.URL
+	serverFaults = srv.Faults
+	return runWithCoverage(m)
+}
+
+// runWithPool runs the tests with browsers from a shared pool and reports its
+// use afterwards. The pool keeps as many idle browsers as go test runs tests
+// in parallel; MICHAEL_BROWSER_POOL overrides that, and 0 launches a fresh
+// browser for every test instead.
+func runWithPool(m *testing.M) int {
+	if !flag.Parsed() {
+		flag.Parse()
+	}
+	size := runtime.GOMAXPROCS(0)
+	if f := flag.Lookup("test.parallel"); f != nil {
+		if n, err := strconv.Atoi(f.Value.String()); err == nil {
+			size = n
+		}
+	}
+	if env := os.Getenv("MICHAEL_BROWSER_POOL"); env != "" {
+		n, err := strconv.Atoi(env)
+		if err != nil || n < 0 {
+			fmt.Fprintf(os.Stderr, "Invalid MICHAEL_BROWSER_POOL %q\n", env)
+			return 1
+		}
+		size = n
+	}
+	if size == 0 {
+		return m.Run()
+	}
+
+	sharedPool = NewBrowserPool(size)
+	defer func() {
+		sharedPool.Close()
+		sharedPool = nil
+	}()
+	code := m.Run()
+	if stats := sharedPool.Stats(); stats.Checkouts > 0 {
+		fmt.Fprintf(os.Stderr, "Browser pool: %s\n", stats)
+	}
+	return code
+}
+
+// buildSite runs hugo for siteDir, writing output and generated resources under dir.
+func buildSite(opts ServerOptions, siteDir, dir, baseURL string) error {
+	timeout := opts.BuildTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := []string{
+		"--source", siteDir,
+		"--destination", filepath.Join(dir, "public"),
+		"--cacheDir", filepath.Join(dir, "cache"),
+		"--baseURL", baseURL + "/",
+		"--quiet",
+	}
+	if !opts.NoMinify {
+		args = append(args, "--minify")
+	}
+	config := filepath.Join(siteDir, "hugo.toml")
+	if opts.DataDir != "" {
+		var err error
+		if config, err = writeDataConfig(siteDir, dir, opts.DataDir); err != nil {
+			return err
+		}
+	}
+	if len(opts.Languages) > 0 {
+		languages, err := writeLanguagesConfig(dir, opts.Languages)
+		if err != nil {
+			return err
+		}
+		config += "," + languages
+	}
+	if opts.DataDir != "" || len(opts.Languages) > 0 {
+		args = append(args, "--config", config)
+	}
+
+	cmd := exec.CommandContext(ctx, hugoBinary(opts, siteDir), args...)
+	// Keep resources/_gen out of the working tree
+	cmd.Env = append(os.Environ(), "HUGO_RESOURCEDIR="+filepath.Join(dir, "resources"))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("hugo build failed: %w\n%s", err, out)
+	}
+	return nil
+}
+
+// writeDataConfig writes a copy of hugo.toml whose data/example mount points at
+// dataDir and returns its path.
+func writeDataConfig(siteDir, dir, dataDir string) (string, error) {
+	const mount = `source = "data/example"`
+
+	config, err := os.ReadFile(filepath.Join(siteDir, "hugo.toml"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read hugo.toml: %w", err)
+	}
+	if !strings.Contains(string(config), mount) {
+		return "", errors.New("hugo.toml has no data/example mount to replace")
+	}
+	abs, err := filepath.Abs(dataDir)
+	if err != nil {
+		return "", err
+	}
+	replaced := strings.Replace(string(config), mount, fmt.Sprintf("source = %q", abs), 1)
+
+	path := filepath.Join(dir, "hugo.toml")
+	if err := os.WriteFile(path, []byte(replaced), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write test config: %w", err)
+	}
+	return path, nil
+}
+
+// writeLanguagesConfig writes a config file declaring each language, right to
+// left where RTLLanguages says so, and returns its path.
+func writeLanguagesConfig(dir string, languages []string) (string, error) {
+	if !slices.Contains(languages, "en") {
+		languages = append([]string{"en"}, languages...)
+	}
+	var sb strings.Builder
+	sb.WriteString("defaultContentLanguage = \"en\"\ndefaultContentLanguageInSubdir = false\n")
+	for i, code := range languages {
+		direction := "ltr"
+		if RTLLanguages[code] {
+			direction = "rtl"
+		}
+		fmt.Fprintf(&sb, "\n[languages.%s]\n  languageCode = %q\n  languageDirection = %q\n  weight = %d\n",
+			code, code, direction, i+1)
+	}
+
+	path := filepath.Join(dir, "languages.toml")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write languages config: %w", err)
+	}
+	return path, nil
+}
+
+// hugoBinary resolves the hugo executable the same way the Makefile does.
+func hugoBinary(opts ServerOptions, siteDir string) string {
+	if opts.Hugo != "" {
+		return opts.Hugo
+	}
+	if env := os.Getenv("HUGO"); env != "" {
+		return env
+	}
+	local := filepath.Join(siteDir, "tools", "hugo", "hugo")
+	if info, err := os.Stat(local); err == nil && info.Mode()&0o111 != 0 {
+		return local
+	}
+	return "hugo"
+}
+
+// findSiteRoot walks up from the working directory to the directory holding hugo.toml.
+func findSiteRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "hugo.toml")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("hugo.toml not found in any parent directory")
+		}
+		dir = parent
+	}
+}
+
+// withCaddyHeaders sets the Caddyfile security headers on every response and its
+// Cache-Control rules on static assets and .html paths.
+func withCaddyHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		for name, value := range securityHeaders {
+			h.Set(name, value)
+		}
+		ext := strings.ToLower(filepath.Ext(r.URL.Path))
+		switch {
+		case staticExtensions[ext]:
+			h.Set("Cache-Control", "public, max-age=31536000, immutable")
+		case ext == ".html":
+			h.Set("Cache-Control", "no-cache, must-revalidate")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
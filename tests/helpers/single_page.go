@@ -0,0 +1,185 @@
+package helpers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers/selectors"
+)
+
+// =============================================================================
+// Single Chapter Page Object
+// =============================================================================
+
+// Single chapter page selectors. Share buttons and the Strong's tooltip are
+// created by share.js and strongs.js after load.
+const (
+	singleVerse       = selectors.ClassVerse + "[data-verse]"
+	singleVerseNumber = selectors.ClassVerse + "[data-verse='%d']"
+	singleNext        = "a[rel='next']"
+	singlePrev        = "a[rel='prev']"
+	singleStrongs     = selectors.ClassStrongsRef + ", " + selectors.ClassStrongsWord
+	strongsTooltip    = selectors.ClassStrongsTooltip
+	chapterShare      = selectors.ClassShareWrapper + " > button"
+	verseShare        = selectors.ClassVerseShareBtn
+	shareMenu         = selectors.ClassShareMenu
+	shareMenuItem     = selectors.ClassShareMenuItem
+	shareCopiedClass  = selectors.ClassCopied
+)
+
+// SingleChapterPage drives a single-translation chapter page.
+type SingleChapterPage struct {
+	t       *testing.T
+	b       *e2e.Browser
+	Bible   string
+	Book    string
+	Chapter int
+}
+
+// OpenSingleChapter navigates to a chapter and waits for its verses.
+func OpenSingleChapter(t *testing.T, b *e2e.Browser, bible, book string, chapter int) *SingleChapterPage {
+	t.Helper()
+	NavigateToSingle(t, b, bible, book, chapter)
+	return &SingleChapterPage{t: t, b: b, Bible: bible, Book: strings.ToLower(book), Chapter: chapter}
+}
+
+// Next follows the next-chapter link and waits for the new chapter.
+func (p *SingleChapterPage) Next() *SingleChapterPage {
+	p.t.Helper()
+	return p.follow(singleNext, p.Chapter+1)
+}
+
+// Previous follows the previous-chapter link and waits for the new chapter.
+func (p *SingleChapterPage) Previous() *SingleChapterPage {
+	p.t.Helper()
+	return p.follow(singlePrev, p.Chapter-1)
+}
+
+// follow clicks a chapter navigation link and waits for the target chapter.
+func (p *SingleChapterPage) follow(selector string, chapter int) *SingleChapterPage {
+	p.t.Helper()
+	WaitAndClick(p.t, p.b, selector)
+	path := fmt.Sprintf("/bible/%s/%s/%d/", p.Bible, p.Book, chapter)
+	if err := p.b.WaitForURL(path); err != nil {
+		p.t.Fatalf("Did not navigate to %s: %v", path, err)
+	}
+	if err := p.b.WaitFor(singleVerse); err != nil {
+		p.t.Fatalf("Chapter %d did not load: %v", chapter, err)
+	}
+	return &SingleChapterPage{t: p.t, b: p.b, Bible: p.Bible, Book: p.Book, Chapter: chapter}
+}
+
+// WaitForVerses waits for the chapter's verses, e.g. after a reload.
+func (p *SingleChapterPage) WaitForVerses() error {
+	return p.b.WaitFor(singleVerse)
+}
+
+// VerseCount returns the number of verses on the page.
+func (p *SingleChapterPage) VerseCount() int {
+	p.t.Helper()
+	return countElements(p.t, p.b, singleVerse)
+}
+
+// HasStrongs waits for Strong's references and reports whether any rendered.
+func (p *SingleChapterPage) HasStrongs() bool {
+	return p.b.WaitFor(singleStrongs) == nil
+}
+
+// OpenStrongs activates the first Strong's reference and returns the tooltip.
+func (p *SingleChapterPage) OpenStrongs() *e2e.Element {
+	p.t.Helper()
+	if err := p.b.WaitFor(singleStrongs); err != nil {
+		p.t.Fatalf("No Strong's references on %s %s %d: %v", p.Bible, p.Book, p.Chapter, err)
+	}
+	if err := p.b.Find(singleStrongs).Click(); err != nil {
+		p.t.Fatalf("Failed to click Strong's reference: %v", err)
+	}
+	if err := p.b.WaitForVisible(strongsTooltip); err != nil {
+		p.t.Fatalf("Strong's tooltip did not open: %v", err)
+	}
+	return p.b.Find(strongsTooltip)
+}
+
+// CloseStrongs dismisses the Strong's tooltip with Escape.
+func (p *SingleChapterPage) CloseStrongs() {
+	p.t.Helper()
+	if err := p.b.Press("Escape"); err != nil {
+		p.t.Fatalf("Failed to press Escape: %v", err)
+	}
+	if err := p.b.WaitForHidden(strongsTooltip); err != nil {
+		p.t.Errorf("Strong's tooltip did not close: %v", err)
+	}
+}
+
+// OpenShareMenu opens the chapter share menu.
+func (p *SingleChapterPage) OpenShareMenu() *ShareMenu {
+	p.t.Helper()
+	return openShareMenu(p.t, p.b, chapterShare)
+}
+
+// OpenVerseShareMenu opens the share menu for one verse.
+func (p *SingleChapterPage) OpenVerseShareMenu(verse int) *ShareMenu {
+	p.t.Helper()
+	return openShareMenu(p.t, p.b, fmt.Sprintf("%s[data-verse='%d']", verseShare, verse))
+}
+
+// Verse returns the element for a verse.
+func (p *SingleChapterPage) Verse(verse int) *e2e.Element {
+	return p.b.Find(fmt.Sprintf(singleVerseNumber, verse))
+}
+
+// NextLink returns the next-chapter link.
+func (p *SingleChapterPage) NextLink() *e2e.Element { return p.b.Find(singleNext) }
+
+// PrevLink returns the previous-chapter link.
+func (p *SingleChapterPage) PrevLink() *e2e.Element { return p.b.Find(singlePrev) }
+
+// =============================================================================
+// Share Menu
+// =============================================================================
+
+// ShareMenu is an open share menu.
+type ShareMenu struct {
+	t *testing.T
+	b *e2e.Browser
+	// button is the selector of the share button that opened the menu.
+	button string
+}
+
+// openShareMenu clicks a share button and waits for the menu.
+func openShareMenu(t *testing.T, b *e2e.Browser, button string) *ShareMenu {
+	t.Helper()
+	WaitAndClick(t, b, button)
+	if err := b.WaitForVisible(shareMenu); err != nil {
+		t.Fatalf("Share menu did not open from %s: %v", button, err)
+	}
+	return &ShareMenu{t: t, b: b, button: button}
+}
+
+// ItemCount returns the number of items in the menu.
+func (m *ShareMenu) ItemCount() int {
+	m.t.Helper()
+	return countElements(m.t, m.b, shareMenu+" "+shareMenuItem)
+}
+
+// Choose clicks the item with the given data-action, e.g. "copy-link".
+func (m *ShareMenu) Choose(action string) {
+	m.t.Helper()
+	if strings.HasPrefix(action, "copy-") {
+		GrantClipboard(m.t, m.b)
+	}
+	WaitAndClick(m.t, m.b, fmt.Sprintf("%s [data-action='%s']", shareMenu, action))
+}
+
+// WaitForCopied waits for the share button to confirm a copy.
+func (m *ShareMenu) WaitForCopied() {
+	m.t.Helper()
+	if err := m.b.WaitFor(m.button + shareCopiedClass); err != nil {
+		m.t.Fatalf("Share button %s did not confirm the copy: %v", m.button, err)
+	}
+}
+
+// Element returns the menu element.
+func (m *ShareMenu) Element() *e2e.Element { return m.b.Find(shareMenu) }
@@ -0,0 +1,306 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+)
+
+// =============================================================================
+// User Storage (IndexedDB)
+// =============================================================================
+
+// UserStorageDB is the IndexedDB database user-storage.js keeps user data in.
+const UserStorageDB = "michael-user-data"
+
+// Object stores of user-storage.js.
+const (
+	ProgressStore  = "reading-progress"
+	BookmarksStore = "bookmarks"
+	NotesStore     = "notes"
+	SettingsStore  = "settings"
+)
+
+// UserData holds IndexedDB records by object store name. It is the format of
+// the fixtures in tests/testdata/userstorage.
+type UserData map[string][]map[string]any
+
+// StoreSchema describes an object store to create when seeding.
+type StoreSchema struct {
+	KeyPath       string `json:"keyPath"`
+	AutoIncrement bool   `json:"autoIncrement,omitempty"`
+	// Indexes are non-unique indexes, each on the property of the same name.
+	Indexes []string `json:"indexes,omitempty"`
+}
+
+// UserStorageSchema is the layout of the database at one DB_VERSION.
+type UserStorageSchema struct {
+	Version int
+	Stores  map[string]StoreSchema
+}
+
+// UserStorageSchemaV1 is the layout user-storage.js creates at DB_VERSION 1.
+var UserStorageSchemaV1 = UserStorageSchema{
+	Version: 1,
+	Stores: map[string]StoreSchema{
+		ProgressStore:  {KeyPath: "bibleId", Indexes: []string{"lastRead"}},
+		BookmarksStore: {KeyPath: "id", AutoIncrement: true, Indexes: []string{"reference", "bibleId", "createdAt"}},
+		NotesStore:     {KeyPath: "id", AutoIncrement: true, Indexes: []string{"reference", "bibleId", "createdAt"}},
+		SettingsStore:  {KeyPath: "key"},
+	},
+}
+
+// UserStorageSchemas lists every layout user-storage.js has shipped, oldest
+// first. Add the new layout here when DB_VERSION is bumped so that upgrades
+// from the old ones are tested.
+var UserStorageSchemas = []UserStorageSchema{UserStorageSchemaV1}
+
+// CurrentUserStorageSchema returns the newest entry of UserStorageSchemas.
+func CurrentUserStorageSchema() UserStorageSchema {
+	return UserStorageSchemas[len(UserStorageSchemas)-1]
+}
+
+// UserStorageSnapshot is the content of the database at a point in time.
+type UserStorageSnapshot struct {
+	Version int      `json:"version"`
+	Stores  UserData `json:"stores"`
+}
+
+// RecentTimestamp replaces timestamps written during a test in snapshots
+// compared against golden files.
+const RecentTimestamp = "<recent>"
+
+// timestampFields are the record properties user-storage.js sets to Date.now().
+var timestampFields = []string{"lastRead", "createdAt", "updatedAt"}
+
+// seedUserStorageScript recreates the database at a given version with the
+// given stores, writes the records and closes it again.
+const seedUserStorageScript = `
+(async () => {
+	const name = %s, version = %d, schema = %s, data = %s;
+	const request = (req) => new Promise((resolve, reject) => {
+		req.onsuccess = () => resolve(req.result);
+		req.onerror = () => reject(req.error);
+		req.onblocked = () => reject(new Error('database is still open in a page'));
+	});
+	try {
+		await request(indexedDB.deleteDatabase(name));
+		const open = indexedDB.open(name, version);
+		open.onupgradeneeded = () => {
+			for (const [store, s] of Object.entries(schema)) {
+				const os = open.result.createObjectStore(store, { keyPath: s.keyPath, autoIncrement: !!s.autoIncrement });
+				for (const index of s.indexes || []) os.createIndex(index, index, { unique: false });
+			}
+		};
+		const db = await request(open);
+		try {
+			const stores = Object.keys(data);
+			if (stores.length > 0) {
+				await new Promise((resolve, reject) => {
+					const tx = db.transaction(stores, 'readwrite');
+					for (const store of stores) {
+						for (const record of data[store]) tx.objectStore(store).put(record);
+					}
+					tx.oncomplete = resolve;
+					tx.onerror = () => reject(tx.error);
+					tx.onabort = () => reject(tx.error);
+				});
+			}
+		} finally {
+			db.close();
+		}
+		return JSON.stringify({ ok: true });
+	} catch (e) {
+		return JSON.stringify({ ok: false, state: String(e && e.message || e) });
+	}
+})()
+`
+
+// snapshotUserStorageScript reads every record of every store in key order.
+const snapshotUserStorageScript = `
+(async () => {
+	const name = %s;
+	try {
+		const dbs = await indexedDB.databases();
+		if (!dbs.some((d) => d.name === name)) {
+			return JSON.stringify({ ok: false, state: 'database does not exist' });
+		}
+		const db = await new Promise((resolve, reject) => {
+			const req = indexedDB.open(name);
+			req.onsuccess = () => resolve(req.result);
+			req.onerror = () => reject(req.error);
+		});
+		try {
+			const stores = {};
+			for (const store of db.objectStoreNames) {
+				stores[store] = await new Promise((resolve, reject) => {
+					const req = db.transaction(store).objectStore(store).getAll();
+					req.onsuccess = () => resolve(req.result);
+					req.onerror = () => reject(req.error);
+				});
+			}
+			return JSON.stringify({ ok: true, detail: { version: db.version, stores } });
+		} finally {
+			db.close();
+		}
+	} catch (e) {
+		return JSON.stringify({ ok: false, state: String(e && e.message || e) });
+	}
+})()
+`
+
+// LoadUserData reads tests/testdata/userstorage/<name>.json.
+func LoadUserData(t *testing.T, name string) UserData {
+	t.Helper()
+	root, err := findSiteRoot()
+	if err != nil {
+		t.Fatalf("Failed to locate testdata directory: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(root, "tests", "testdata", "userstorage", name+".json"))
+	if err != nil {
+		t.Fatalf("Failed to read user data fixture: %v", err)
+	}
+	var data UserData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("Failed to parse user data fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// SeedUserStorage replaces the user database with data in the current
+// schema. See SeedUserStorageSchema.
+func SeedUserStorage(t *testing.T, b *e2e.Browser, data UserData) {
+	t.Helper()
+	SeedUserStorageSchema(t, b, CurrentUserStorageSchema(), data)
+}
+
+// SeedUserStorageSchema deletes the user database and recreates it with
+// schema's stores at schema's version, holding data. An older schema leaves
+// the upgrade to user-storage.js when the next page opens the database.
+//
+// The database cannot be deleted while a page holds it open, so this
+// navigates to the web app manifest, which loads no scripts; navigate to the
+// page under test afterwards.
+func SeedUserStorageSchema(t *testing.T, b *e2e.Browser, schema UserStorageSchema, data UserData) {
+	t.Helper()
+	for store := range data {
+		if _, ok := schema.Stores[store]; !ok {
+			t.Fatalf("Schema version %d has no store %q", schema.Version, store)
+		}
+	}
+	if data == nil {
+		data = UserData{}
+	}
+	if err := b.Navigate(BaseURL + "/manifest.json"); err != nil {
+		t.Fatalf("Failed to leave the app before seeding: %v", err)
+	}
+
+	stores, err := json.Marshal(schema.Stores)
+	if err != nil {
+		t.Fatalf("Failed to encode schema: %v", err)
+	}
+	records, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to encode user data: %v", err)
+	}
+	res, err := evalSync(b, fmt.Sprintf(seedUserStorageScript, jsString(UserStorageDB), schema.Version, stores, records))
+	if err != nil {
+		t.Fatalf("Failed to seed user storage: %v", err)
+	}
+	if !res.OK {
+		t.Fatalf("Failed to seed user storage: %s", res.State)
+	}
+}
+
+// SnapshotUserStorage returns every record of the user database. The page
+// keeps its own connection open.
+func SnapshotUserStorage(t *testing.T, b *e2e.Browser) UserStorageSnapshot {
+	t.Helper()
+	res, err := evalSync(b, fmt.Sprintf(snapshotUserStorageScript, jsString(UserStorageDB)))
+	if err != nil {
+		t.Fatalf("Failed to snapshot user storage: %v", err)
+	}
+	if !res.OK {
+		t.Fatalf("Failed to snapshot user storage: %s", res.State)
+	}
+	var snap UserStorageSnapshot
+	if err := json.Unmarshal(res.Detail, &snap); err != nil {
+		t.Fatalf("Failed to decode user storage snapshot: %v", err)
+	}
+	return snap
+}
+
+// WithRecentTimestamps returns a copy of the snapshot in which timestamps at
+// or after since are replaced by RecentTimestamp, so that records written
+// during a test compare equal from run to run.
+func (s UserStorageSnapshot) WithRecentTimestamps(since time.Time) UserStorageSnapshot {
+	cutoff := float64(since.UnixMilli())
+	out := UserStorageSnapshot{Version: s.Version, Stores: UserData{}}
+	for store, records := range s.Stores {
+		copied := make([]map[string]any, len(records))
+		for i, record := range records {
+			copied[i] = make(map[string]any, len(record))
+			for k, v := range record {
+				copied[i][k] = v
+			}
+			for _, field := range timestampFields {
+				if ms, ok := record[field].(float64); ok && ms >= cutoff {
+					copied[i][field] = RecentTimestamp
+				}
+			}
+		}
+		out.Stores[store] = copied
+	}
+	return out
+}
+
+// MatchUserStorageGolden snapshots the user database and compares it against
+// tests/golden/<name>.json, with timestamps at or after since written as
+// RecentTimestamp. A missing golden fails the test. Run with -update to
+// create or rewrite the golden file.
+func MatchUserStorageGolden(t *testing.T, b *e2e.Browser, name string, since time.Time) {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(SnapshotUserStorage(t, b).WithRecentTimestamps(since)); err != nil {
+		t.Fatalf("Failed to encode user storage snapshot: %v", err)
+	}
+	actual := buf.Bytes()
+
+	goldenPath, err := goldenFile(name + ".json")
+	if err != nil {
+		t.Fatalf("Failed to locate golden directory: %v", err)
+	}
+
+	if *updateGolden {
+		if err := os.WriteFile(goldenPath, actual, 0o644); err != nil {
+			t.Fatalf("Failed to update golden snapshot %s: %v", goldenPath, err)
+		}
+		t.Logf("Updated golden snapshot %s", goldenPath)
+		return
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if os.IsNotExist(err) {
+		writeArtifact(t, filepath.Join(ArtifactDir(t), name+"-actual.json"), actual)
+		t.Fatalf("No golden snapshot %s; run with -update to create it", goldenPath)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read golden snapshot: %v", err)
+	}
+	if bytes.Equal(expected, actual) {
+		return
+	}
+
+	dir := ArtifactDir(t)
+	writeArtifact(t, filepath.Join(dir, name+"-actual.json"), actual)
+	t.Errorf("User storage differs from golden snapshot %s; actual snapshot in %s", name, dir)
+}
@@ -0,0 +1,393 @@
+package helpers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/heapprofiler"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/performance"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Web Vitals
+// =============================================================================
+
+// vitalsObserver runs before any page script and accumulates layout shifts,
+// long tasks and the latest LCP candidate in window.__michaelVitals. CLS is
+// the largest session window, as web-vitals computes it.
+const vitalsObserver = `
+(() => {
+	const v = { lcp: 0, cls: 0, tbt: 0, longTasks: 0 };
+	let session = 0, sessionStart = 0, sessionLast = 0;
+	const observe = (type, fn) => {
+		try {
+			new PerformanceObserver((list) => list.getEntries().forEach(fn)).observe({ type, buffered: true });
+		} catch (e) {}
+	};
+	observe('largest-contentful-paint', (e) => { v.lcp = e.startTime; });
+	observe('layout-shift', (e) => {
+		if (e.hadRecentInput) return;
+		if (session && e.startTime - sessionLast < 1000 && e.startTime - sessionStart < 5000) {
+			session += e.value;
+		} else {
+			session = e.value;
+			sessionStart = e.startTime;
+		}
+		sessionLast = e.startTime;
+		v.cls = Math.max(v.cls, session);
+	});
+	observe('longtask', (e) => {
+		v.longTasks++;
+		v.tbt += Math.max(0, e.duration - 50);
+	});
+	window.__michaelVitals = v;
+})();
+`
+
+// vitalsScript waits for a frame to settle and returns the observed vitals
+// and the page's transfer size as JSON, or "null" if the observer is missing.
+const vitalsScript = `
+(async () => {
+	await new Promise((resolve) => requestAnimationFrame(() => setTimeout(resolve, 0)));
+	const v = window.__michaelVitals;
+	if (!v) return 'null';
+	const entries = [...performance.getEntriesByType('navigation'), ...performance.getEntriesByType('resource')];
+	const transfer = entries.reduce((sum, e) => sum + (e.transferSize || 0), 0);
+	return JSON.stringify({ ...v, transfer, requests: entries.length });
+})()
+`
+
+// Vitals are the Web Vitals and resource costs of one page load.
+type Vitals struct {
+	// LCP is the largest contentful paint, in milliseconds since navigation.
+	LCP float64 `json:"lcpMs"`
+	// CLS is the cumulative layout shift of the worst session window.
+	CLS float64 `json:"cls"`
+	// TBT is the total blocking time: the sum over long tasks of the part
+	// over 50 ms.
+	TBT float64 `json:"tbtMs"`
+	// LongTasks counts main-thread tasks of over 50 ms.
+	LongTasks int `json:"longTasks"`
+	// JSHeap is the used JavaScript heap after garbage collection, in bytes.
+	JSHeap int64 `json:"jsHeapBytes"`
+	// Transfer is the bytes fetched over the network for the document and its
+	// resources. Responses from the HTTP cache or the service worker count zero.
+	Transfer int64 `json:"transferBytes"`
+	// Requests counts the document and its resources.
+	Requests int `json:"requests"`
+}
+
+// VitalsRecorder measures the page loads of one browser.
+type VitalsRecorder struct {
+	t *testing.T
+	b *e2e.Browser
+}
+
+// RecordVitals starts measuring b. Call it before navigating to the page
+// under test; the observers attach to each new document. Timings are only
+// meaningful without -jscover, whose profiler slows every script.
+func RecordVitals(t *testing.T, b *e2e.Browser) *VitalsRecorder {
+	t.Helper()
+	var id page.ScriptIdentifier
+	err := RunCDP(b,
+		performance.Enable(),
+		chromedp.ActionFunc(func(ctx context.Context) (err error) {
+			id, err = page.AddScriptToEvaluateOnNewDocument(vitalsObserver).Do(ctx)
+			return err
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to start recording Web Vitals: %v", err)
+	}
+	// Pooled browsers outlive the test, so take the observer back out
+	t.Cleanup(func() {
+		RunCDP(b, page.RemoveScriptToEvaluateOnNewDocument(id), performance.Disable())
+	})
+	return &VitalsRecorder{t: t, b: b}
+}
+
+// Collect returns the vitals of the current page so far.
+func (r *VitalsRecorder) Collect() Vitals {
+	r.t.Helper()
+	raw, err := r.b.Evaluate(vitalsScript)
+	if err != nil {
+		r.t.Fatalf("Failed to read Web Vitals: %v", err)
+	}
+	s, _ := raw.(string)
+	var observed struct {
+		LCP       float64 `json:"lcp"`
+		CLS       float64 `json:"cls"`
+		TBT       float64 `json:"tbt"`
+		LongTasks int     `json:"longTasks"`
+		Transfer  int64   `json:"transfer"`
+		Requests  int     `json:"requests"`
+	}
+	if s == "null" {
+		r.t.Fatal("Web Vitals observer is not installed; call RecordVitals before navigating")
+	}
+	if err := json.Unmarshal([]byte(s), &observed); err != nil {
+		r.t.Fatalf("Failed to decode Web Vitals: %v", err)
+	}
+
+	var metrics []*performance.Metric
+	err = RunCDP(r.b,
+		heapprofiler.CollectGarbage(),
+		chromedp.ActionFunc(func(ctx context.Context) (err error) {
+			metrics, err = performance.GetMetrics().Do(ctx)
+			return err
+		}),
+	)
+	if err != nil {
+		r.t.Fatalf("Failed to read JS heap size: %v", err)
+	}
+	v := Vitals{
+		LCP:       observed.LCP,
+		CLS:       observed.CLS,
+		TBT:       observed.TBT,
+		LongTasks: observed.LongTasks,
+		Transfer:  observed.Transfer,
+		Requests:  observed.Requests,
+	}
+	for _, m := range metrics {
+		if m.Name == "JSHeapUsedSize" {
+			v.JSHeap = int64(m.Value)
+		}
+	}
+	return v
+}
+
+// MedianVitals returns the median of each metric across samples.
+func MedianVitals(samples []Vitals) Vitals {
+	var v Vitals
+	if len(samples) == 0 {
+		return v
+	}
+	median := func(get func(Vitals) float64) float64 {
+		values := make([]float64, len(samples))
+		for i, s := range samples {
+			values[i] = get(s)
+		}
+		sort.Float64s(values)
+		if n := len(values); n%2 == 0 {
+			return (values[n/2-1] + values[n/2]) / 2
+		}
+		return values[len(values)/2]
+	}
+	v.LCP = median(func(s Vitals) float64 { return s.LCP })
+	v.CLS = median(func(s Vitals) float64 { return s.CLS })
+	v.TBT = median(func(s Vitals) float64 { return s.TBT })
+	v.LongTasks = int(median(func(s Vitals) float64 { return float64(s.LongTasks) }))
+	v.JSHeap = int64(median(func(s Vitals) float64 { return float64(s.JSHeap) }))
+	v.Transfer = int64(median(func(s Vitals) float64 { return float64(s.Transfer) }))
+	v.Requests = int(median(func(s Vitals) float64 { return float64(s.Requests) }))
+	return v
+}
+
+// =============================================================================
+// Performance Budgets
+// =============================================================================
+
+// perfMetric is one budgeted measurement, keyed as in the budget file.
+type perfMetric struct {
+	Key   string
+	Label string
+	Unit  string
+	Value func(Vitals) float64
+}
+
+// perfMetrics lists the budgeted metrics in report order.
+var perfMetrics = []perfMetric{
+	{"lcpMs", "LCP", "ms", func(v Vitals) float64 { return v.LCP }},
+	{"cls", "CLS", "", func(v Vitals) float64 { return v.CLS }},
+	{"tbtMs", "TBT", "ms", func(v Vitals) float64 { return v.TBT }},
+	{"longTasks", "Long tasks", "", func(v Vitals) float64 { return float64(v.LongTasks) }},
+	{"jsHeapKB", "JS heap", "KB", func(v Vitals) float64 { return float64(v.JSHeap) / 1024 }},
+	{"transferKB", "Transfer", "KB", func(v Vitals) float64 { return float64(v.Transfer) / 1024 }},
+}
+
+// PerfBudgets maps page names to the maximum of each metric: lcpMs, cls,
+// tbtMs, longTasks, jsHeapKB and transferKB. A metric without a budget is
+// reported but not checked.
+type PerfBudgets map[string]map[string]float64
+
+// LoadPerfBudgets reads tests/testdata/perf-budgets.json.
+func LoadPerfBudgets(t *testing.T) PerfBudgets {
+	t.Helper()
+	root, err := findSiteRoot()
+	if err != nil {
+		t.Fatalf("Failed to locate performance budgets: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(root, "tests", "testdata", "perf-budgets.json"))
+	if err != nil {
+		t.Fatalf("Failed to read performance budgets: %v", err)
+	}
+	var budgets PerfBudgets
+	if err := json.Unmarshal(raw, &budgets); err != nil {
+		t.Fatalf("Failed to parse performance budgets: %v", err)
+	}
+	for page, limits := range budgets {
+		for key := range limits {
+			if perfMetricIndex(key) < 0 {
+				t.Fatalf("Performance budget for %s has unknown metric %q", page, key)
+			}
+		}
+	}
+	return budgets
+}
+
+// CheckPerfBudget fails the test for each metric of v over the page's budget.
+func CheckPerfBudget(t *testing.T, budgets PerfBudgets, page string, v Vitals) {
+	t.Helper()
+	limits, ok := budgets[page]
+	if !ok {
+		t.Errorf("No performance budget for %s in testdata/perf-budgets.json", page)
+		return
+	}
+	for _, m := range perfMetrics {
+		limit, ok := limits[m.Key]
+		if ok && m.Value(v) > limit {
+			t.Errorf("%s %s is %s, over the budget of %s",
+				page, m.Label, formatMetric(m, m.Value(v)), formatMetric(m, limit))
+		}
+	}
+}
+
+// perfMetricIndex returns the position of a metric key in perfMetrics, or -1.
+func perfMetricIndex(key string) int {
+	for i, m := range perfMetrics {
+		if m.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatMetric renders a metric value with its unit.
+func formatMetric(m perfMetric, value float64) string {
+	switch m.Unit {
+	case "":
+		if m.Key == "cls" {
+			return fmt.Sprintf("%.3f", value)
+		}
+		return fmt.Sprintf("%.0f", value)
+	default:
+		return fmt.Sprintf("%.0f %s", value, m.Unit)
+	}
+}
+
+// =============================================================================
+// Performance Trend
+// =============================================================================
+
+// perfTrendRuns is how many earlier runs the trend report summarizes.
+const perfTrendRuns = 10
+
+// perfRun is one run's vitals per page, as kept in history.jsonl.
+type perfRun struct {
+	Time  time.Time         `json:"time"`
+	Pages map[string]Vitals `json:"pages"`
+}
+
+// WritePerfTrend appends this run's vitals to history.jsonl in the test's
+// artifact directory and writes trend.md, comparing each page and metric
+// against its budget, the previous run and the median of recent runs.
+func WritePerfTrend(t *testing.T, budgets PerfBudgets, pages map[string]Vitals) {
+	t.Helper()
+	dir := ArtifactDir(t)
+	historyPath := filepath.Join(dir, "history.jsonl")
+
+	var history []perfRun
+	if f, err := os.Open(historyPath); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var run perfRun
+			if json.Unmarshal(scanner.Bytes(), &run) == nil {
+				history = append(history, run)
+			}
+		}
+		f.Close()
+	}
+	if len(history) > perfTrendRuns {
+		history = history[len(history)-perfTrendRuns:]
+	}
+
+	run := perfRun{Time: time.Now().UTC(), Pages: pages}
+	line, err := json.Marshal(run)
+	if err != nil {
+		t.Fatalf("Failed to encode performance run: %v", err)
+	}
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open performance history: %v", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatalf("Failed to append performance history: %v", err)
+	}
+
+	trendPath := filepath.Join(dir, "trend.md")
+	writeArtifact(t, trendPath, []byte(perfTrendMarkdown(run, history, budgets)))
+	t.Logf("Performance trend: %s", trendPath)
+}
+
+// perfTrendMarkdown renders the trend report for run against earlier runs.
+func perfTrendMarkdown(run perfRun, history []perfRun, budgets PerfBudgets) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Performance trend\n\nRun %s, compared with %d earlier runs.\n",
+		run.Time.Format(time.RFC3339), len(history))
+
+	names := make([]string, 0, len(run.Pages))
+	for name := range run.Pages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := run.Pages[name]
+		var earlier []Vitals
+		for _, h := range history {
+			if hv, ok := h.Pages[name]; ok {
+				earlier = append(earlier, hv)
+			}
+		}
+
+		fmt.Fprintf(&sb, "\n## %s\n\n", name)
+		sb.WriteString("| Metric | Budget | This run | Previous | Change | Median of earlier |\n")
+		sb.WriteString("|---|---|---|---|---|---|\n")
+		for _, m := range perfMetrics {
+			value := m.Value(v)
+			budget, previous, change, median := "-", "-", "-", "-"
+			if limit, ok := budgets[name][m.Key]; ok {
+				budget = formatMetric(m, limit)
+				if value > limit {
+					budget += " **over**"
+				}
+			}
+			if len(earlier) > 0 {
+				prev := m.Value(earlier[len(earlier)-1])
+				previous = formatMetric(m, prev)
+				if prev != 0 {
+					change = fmt.Sprintf("%+.0f%%", (value-prev)/prev*100)
+				}
+				median = formatMetric(m, m.Value(MedianVitals(earlier)))
+			}
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s |\n",
+				m.Label, budget, formatMetric(m, value), previous, change, median)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,510 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+)
+
+// =============================================================================
+// Event-Driven Synchronization
+// =============================================================================
+
+// SyncTimeout bounds every wait in this file. A wait that runs out fails the
+// test with the last state it observed.
+var SyncTimeout = 15 * time.Second
+
+// NetworkQuietPeriod is how long no request may be in flight before the
+// network counts as idle.
+const NetworkQuietPeriod = 500 * time.Millisecond
+
+// syncResult is the JSON shape returned by the in-page wait scripts.
+type syncResult struct {
+	OK     bool            `json:"ok"`
+	State  string          `json:"state"`
+	Detail json.RawMessage `json:"detail"`
+}
+
+// evalSync runs a wait script that resolves to a JSON-encoded syncResult.
+func evalSync(b *e2e.Browser, script string) (syncResult, error) {
+	var res syncResult
+	raw, err := b.Evaluate(script)
+	if err != nil {
+		return res, err
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return res, fmt.Errorf("wait script returned %T", raw)
+	}
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		return res, fmt.Errorf("failed to decode wait result: %w", err)
+	}
+	return res, nil
+}
+
+// jsString quotes s as a JavaScript string literal.
+func jsString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// =============================================================================
+// Network
+// =============================================================================
+
+// WaitForNetworkIdle waits until no request has been in flight for
+// NetworkQuietPeriod. Requests that started before the call are only seen
+// when they finish.
+func WaitForNetworkIdle(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(b.Context(), SyncTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	inflight := map[network.RequestID]string{}
+	activity := make(chan struct{}, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			inflight[e.RequestID] = e.Request.URL
+		case *network.EventLoadingFinished:
+			delete(inflight, e.RequestID)
+		case *network.EventLoadingFailed:
+			delete(inflight, e.RequestID)
+		default:
+			return
+		}
+		select {
+		case activity <- struct{}{}:
+		default:
+		}
+	})
+	if err := RunCDP(b, network.Enable()); err != nil {
+		t.Fatalf("Failed to enable network events: %v", err)
+	}
+
+	pending := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		urls := make([]string, 0, len(inflight))
+		for _, u := range inflight {
+			urls = append(urls, u)
+		}
+		sort.Strings(urls)
+		return urls
+	}
+
+	quiet := time.NewTimer(NetworkQuietPeriod)
+	defer quiet.Stop()
+	for {
+		select {
+		case <-activity:
+			quiet.Reset(NetworkQuietPeriod)
+		case <-quiet.C:
+			if len(pending()) == 0 {
+				return
+			}
+			quiet.Reset(NetworkQuietPeriod)
+		case <-ctx.Done():
+			urls := pending()
+			t.Fatalf("Timed out after %v waiting for network idle; %d requests in flight: %s",
+				SyncTimeout, len(urls), strings.Join(urls, ", "))
+		}
+	}
+}
+
+// =============================================================================
+// Service Worker
+// =============================================================================
+
+// serviceWorkerScript resolves once a worker is activated and controls the
+// page. It listens for statechange and controllerchange rather than polling.
+const serviceWorkerScript = `
+(async () => {
+	if (!('serviceWorker' in navigator)) {
+		return JSON.stringify({ ok: false, state: 'serviceWorker is not supported' });
+	}
+	const sw = navigator.serviceWorker;
+	const describe = async () => {
+		const reg = await sw.getRegistration();
+		const state = w => (w ? w.state : 'none');
+		if (!reg) return 'no registration, controlled=' + !!sw.controller;
+		return 'installing=' + state(reg.installing) + ' waiting=' + state(reg.waiting) +
+			' active=' + state(reg.active) + ' controlled=' + !!sw.controller;
+	};
+	const ready = (async () => {
+		const reg = await sw.ready;
+		const worker = reg.active;
+		if (worker.state !== 'activated') {
+			await new Promise(resolve => worker.addEventListener('statechange', () => {
+				if (worker.state === 'activated') resolve();
+			}));
+		}
+		if (!sw.controller) {
+			await new Promise(resolve => sw.addEventListener('controllerchange', resolve, { once: true }));
+		}
+		return true;
+	})();
+	const timedOut = new Promise(resolve => setTimeout(() => resolve(false), %d));
+	const ok = await Promise.race([ready, timedOut]);
+	return JSON.stringify({ ok, state: await describe() });
+})()
+`
+
+// WaitForServiceWorker waits until the service worker is activated and
+// controls the page. Activation happens after install has pre-cached the
+// shell assets and default chapters.
+func WaitForServiceWorker(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	res, err := evalSync(b, fmt.Sprintf(serviceWorkerScript, SyncTimeout.Milliseconds()))
+	if err != nil {
+		t.Fatalf("Failed to wait for service worker: %v", err)
+	}
+	if !res.OK {
+		t.Fatalf("Timed out after %v waiting for service worker to activate and control the page; %s",
+			SyncTimeout, res.State)
+	}
+}
+
+// =============================================================================
+// Cache Storage
+// =============================================================================
+
+// cacheLookupScript reports whether any cache whose name starts with the
+// prefix holds the URL, and lists the matching caches otherwise.
+const cacheLookupScript = `
+(async () => {
+	const prefix = %s;
+	const url = %s;
+	const seen = [];
+	for (const name of await caches.keys()) {
+		if (!name.startsWith(prefix)) continue;
+		const cache = await caches.open(name);
+		if (await cache.match(url)) return JSON.stringify({ ok: true, state: name });
+		seen.push(name + ' (' + (await cache.keys()).length + ' entries)');
+	}
+	return JSON.stringify({ ok: false, state: seen.length ? seen.join(', ') : 'no matching caches' });
+})()
+`
+
+// WaitForCachedURL waits until a Cache Storage cache whose name starts with
+// cachePrefix (e.g. "michael-chapters-") holds resource. It re-checks whenever
+// Chrome reports a content update for the site's origin.
+func WaitForCachedURL(t *testing.T, b *e2e.Browser, cachePrefix, resource string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(b.Context(), SyncTimeout)
+	defer cancel()
+
+	updated := make(chan struct{}, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		e, ok := ev.(*storage.EventCacheStorageContentUpdated)
+		if !ok || !strings.HasPrefix(e.CacheName, cachePrefix) {
+			return
+		}
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+	origin, err := siteOrigin()
+	if err != nil {
+		t.Fatalf("Failed to determine site origin: %v", err)
+	}
+	if err := RunCDP(b, storage.TrackCacheStorageForOrigin(origin)); err != nil {
+		t.Fatalf("Failed to track Cache Storage for %s: %v", origin, err)
+	}
+	defer RunCDP(b, storage.UntrackCacheStorageForOrigin(origin))
+
+	script := fmt.Sprintf(cacheLookupScript, jsString(cachePrefix), jsString(resource))
+	for {
+		res, err := evalSync(b, script)
+		if err != nil {
+			t.Fatalf("Failed to read Cache Storage: %v", err)
+		}
+		if res.OK {
+			return
+		}
+		select {
+		case <-updated:
+		case <-ctx.Done():
+			t.Fatalf("Timed out after %v waiting for %s in cache %s*; caches: %s",
+				SyncTimeout, resource, cachePrefix, res.State)
+		}
+	}
+}
+
+// siteOrigin returns the scheme and host of BaseURL.
+func siteOrigin() (string, error) {
+	u, err := url.Parse(BaseURL)
+	if err != nil {
+		return "", err
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// =============================================================================
+// App Events
+// =============================================================================
+
+// AppEventSource is a JavaScript expression for an EventTarget in the page.
+type AppEventSource string
+
+const (
+	// DocumentEvents receives events page modules dispatch on document, such
+	// as michael:chapter-loaded.
+	DocumentEvents AppEventSource = "document"
+	// OfflineManagerEvents receives OfflineManager download events.
+	OfflineManagerEvents AppEventSource = "window.Michael && window.Michael.OfflineManager"
+)
+
+// listenerSeq keeps listener slots unique within a page.
+var listenerSeq atomic.Int64
+
+// listenScript installs a one-shot listener that records into
+// window.__michaelSync[key]. The match predicate filters on event.detail.
+const listenScript = `
+(() => {
+	const target = (%s);
+	const name = %s;
+	if (!target || typeof target.addEventListener !== 'function') {
+		return JSON.stringify({ ok: false, state: %s + ' is not available' });
+	}
+	const match = %s;
+	const slots = window.__michaelSync = window.__michaelSync || {};
+	const slot = slots[%s] = { fired: false, seen: 0, last: null, detail: null, waiters: [] };
+	const handler = (e) => {
+		slot.seen++;
+		slot.last = e.detail === undefined ? null : e.detail;
+		if (match && !match(slot.last)) return;
+		slot.fired = true;
+		slot.detail = slot.last;
+		target.removeEventListener(name, handler);
+		slot.waiters.forEach(resolve => resolve());
+	};
+	target.addEventListener(name, handler);
+	return JSON.stringify({ ok: true });
+})()
+`
+
+// awaitScript resolves when the slot's event fires or the timeout passes.
+const awaitScript = `
+(async () => {
+	const slot = (window.__michaelSync || {})[%s];
+	if (!slot) {
+		return JSON.stringify({ ok: false, state: 'listener is gone; the page navigated after it was installed' });
+	}
+	if (!slot.fired) {
+		await Promise.race([
+			new Promise(resolve => slot.waiters.push(resolve)),
+			new Promise(resolve => setTimeout(resolve, %d)),
+		]);
+	}
+	return JSON.stringify({
+		ok: slot.fired,
+		detail: slot.detail,
+		state: slot.seen + ' events seen, last detail ' + JSON.stringify(slot.last),
+	});
+})()
+`
+
+// AppEvent is a listener for one app-level event, installed before the
+// action that triggers it so a fast dispatch is not missed.
+type AppEvent struct {
+	t       *testing.T
+	b       *e2e.Browser
+	name    string
+	key     string
+	timeout time.Duration
+}
+
+// ListenForAppEvent installs a listener for the named event on source. match
+// is an optional JavaScript predicate over event.detail, e.g.
+// "d => d.bible === 'asv'"; non-matching events are counted but ignored.
+func ListenForAppEvent(t *testing.T, b *e2e.Browser, source AppEventSource, name, match string) *AppEvent {
+	t.Helper()
+	if match == "" {
+		match = "null"
+	}
+	key := fmt.Sprintf("%s#%d", name, listenerSeq.Add(1))
+	script := fmt.Sprintf(listenScript, source, jsString(name), jsString(string(source)), match, jsString(key))
+	res, err := evalSync(b, script)
+	if err != nil {
+		t.Fatalf("Failed to listen for %s: %v", name, err)
+	}
+	if !res.OK {
+		t.Fatalf("Failed to listen for %s: %s", name, res.State)
+	}
+	return &AppEvent{t: t, b: b, name: name, key: key, timeout: SyncTimeout}
+}
+
+// Within sets how long Wait and WaitInto wait, for events such as a whole
+// Bible download that take longer than SyncTimeout.
+func (e *AppEvent) Within(timeout time.Duration) *AppEvent {
+	e.timeout = timeout
+	return e
+}
+
+// Wait blocks until the event fires and returns its detail.
+func (e *AppEvent) Wait() map[string]interface{} {
+	e.t.Helper()
+	detail := map[string]interface{}{}
+	e.WaitInto(&detail)
+	return detail
+}
+
+// WaitInto blocks until the event fires and decodes its detail into v.
+func (e *AppEvent) WaitInto(v interface{}) {
+	e.t.Helper()
+	// Long waits are split into SyncTimeout slices so no single evaluation
+	// outlives the browser's own command timeout
+	deadline := time.Now().Add(e.timeout)
+	var res syncResult
+	for {
+		slice := min(time.Until(deadline), SyncTimeout)
+		var err error
+		res, err = evalSync(e.b, fmt.Sprintf(awaitScript, jsString(e.key), slice.Milliseconds()))
+		if err != nil {
+			e.t.Fatalf("Failed to wait for %s: %v", e.name, err)
+		}
+		if res.OK {
+			break
+		}
+		if time.Until(deadline) <= 0 {
+			e.t.Fatalf("Timed out after %v waiting for %s; %s", e.timeout, e.name, res.State)
+		}
+	}
+	if len(res.Detail) == 0 || string(res.Detail) == "null" {
+		return
+	}
+	if err := json.Unmarshal(res.Detail, v); err != nil {
+		e.t.Fatalf("Failed to decode %s detail: %v", e.name, err)
+	}
+}
+
+// ListenForChapterLoaded listens for michael:chapter-loaded, which the compare
+// page dispatches after rendering a chapter in either mode.
+func ListenForChapterLoaded(t *testing.T, b *e2e.Browser) *AppEvent {
+	t.Helper()
+	return ListenForAppEvent(t, b, DocumentEvents, "michael:chapter-loaded", "")
+}
+
+// ListenForDownloadProgress listens for an OfflineManager download-progress
+// event at or above percent. An empty bibleID matches any Bible.
+func ListenForDownloadProgress(t *testing.T, b *e2e.Browser, bibleID string, percent int) *AppEvent {
+	t.Helper()
+	match := fmt.Sprintf("d => d && d.progress >= %d", percent)
+	if bibleID != "" {
+		match += " && d.bible === " + jsString(bibleID)
+	}
+	return ListenForAppEvent(t, b, OfflineManagerEvents, "download-progress", match)
+}
+
+// ListenForDownloadComplete listens for the OfflineManager download-complete
+// event for bibleID. The detail's success field reports the outcome.
+func ListenForDownloadComplete(t *testing.T, b *e2e.Browser, bibleID string) *AppEvent {
+	t.Helper()
+	return ListenForAppEvent(t, b, OfflineManagerEvents, "download-complete", "d => d && d.bible === "+jsString(bibleID))
+}
+
+// =============================================================================
+// Page Conditions
+// =============================================================================
+
+// conditionScript resolves once the expression is truthy. It re-checks after
+// every DOM mutation and focus change, and on a short timer for state that
+// announces nothing, such as globals and IndexedDB writes. The expression may
+// return a promise.
+const conditionScript = `
+(async () => {
+	const check = async () => {
+		try {
+			return !!(await (%s));
+		} catch (e) {
+			return false;
+		}
+	};
+	if (await check()) return JSON.stringify({ ok: true });
+	const ok = await new Promise(resolve => {
+		let checking = false;
+		const finish = (result) => {
+			observer.disconnect();
+			document.removeEventListener('focusin', recheck, true);
+			document.removeEventListener('focusout', recheck, true);
+			clearInterval(timer);
+			clearTimeout(deadline);
+			resolve(result);
+		};
+		const recheck = async () => {
+			if (checking) return;
+			checking = true;
+			if (await check()) finish(true);
+			checking = false;
+		};
+		const observer = new MutationObserver(recheck);
+		observer.observe(document, { subtree: true, childList: true, attributes: true, characterData: true });
+		document.addEventListener('focusin', recheck, true);
+		document.addEventListener('focusout', recheck, true);
+		const timer = setInterval(recheck, 50);
+		const deadline = setTimeout(() => finish(false), %d);
+	});
+	return JSON.stringify({ ok });
+})()
+`
+
+// WaitForCondition waits until the JavaScript expression is truthy, such as
+// "document.activeElement.checked" or an async IndexedDB read. what
+// describes the condition in the failure message.
+func WaitForCondition(t *testing.T, b *e2e.Browser, what, expr string) {
+	t.Helper()
+	res, err := evalSync(b, fmt.Sprintf(conditionScript, expr, SyncTimeout.Milliseconds()))
+	if err != nil {
+		t.Fatalf("Failed to wait for %s: %v", what, err)
+	}
+	if !res.OK {
+		t.Fatalf("Timed out after %v waiting for %s", SyncTimeout, what)
+	}
+}
+
+// WaitForGlobal waits until a page module has defined the global at path
+// under window, such as "Michael.UserStorage".
+func WaitForGlobal(t *testing.T, b *e2e.Browser, path string) {
+	t.Helper()
+	expr := "window"
+	for _, name := range strings.Split(path, ".") {
+		expr += "?.[" + jsString(name) + "]"
+	}
+	WaitForCondition(t, b, "window."+path, expr+" !== undefined")
+}
+
+// WaitForFocus waits until the focused element matches the selector.
+func WaitForFocus(t *testing.T, b *e2e.Browser, selector string) {
+	t.Helper()
+	WaitForCondition(t, b, "focus on "+selector,
+		"document.activeElement && document.activeElement.matches("+jsString(selector)+")")
+}
+
+// WaitForChecked waits until the first element matching the selector is
+// checked, or unchecked when checked is false.
+func WaitForChecked(t *testing.T, b *e2e.Browser, selector string, checked bool) {
+	t.Helper()
+	state := "checked"
+	if !checked {
+		state = "unchecked"
+	}
+	WaitForCondition(t, b, selector+" to be "+state,
+		fmt.Sprintf("document.querySelector(%s)?.checked === %t", jsString(selector), checked))
+}
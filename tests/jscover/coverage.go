@@ -0,0 +1,365 @@
+// Package jscover turns V8 precise coverage of the site's scripts into line
+// coverage of the JavaScript sources in the repository, and writes it as an
+// lcov tracefile and an HTML summary.
+//
+// Served scripts are matched to sources by URL path, with Hugo fingerprints
+// removed. A script served verbatim maps line for line; one Hugo rewrote, for
+// example with ExecuteAsTemplate, maps through its lines that appear
+// unchanged in the source. Minified scripts share no lines with their source
+// and are reported as unmapped.
+package jscover
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// Range is a block of a function and how often it ran. Offsets count UTF-16
+// code units, as V8 reports them.
+type Range struct {
+	Start, End int
+	Count      int
+}
+
+// Function is the coverage of one function. Its first range spans the whole
+// function; later ranges are nested blocks.
+type Function struct {
+	Name   string
+	Ranges []Range
+}
+
+// Script is the coverage of one script as served, with the text it was
+// served with.
+type Script struct {
+	URL       string
+	Source    string
+	Functions []Function
+}
+
+// SourceRoots are the directories, relative to the site root, searched for a
+// served script's source, in order.
+var SourceRoots = []string{"assets", "static"}
+
+// LayoutSources maps the URL paths of scripts Hugo renders from a layout
+// template, rather than copying from SourceRoots, to that template.
+var LayoutSources = map[string]string{
+	"/sw.js": "layouts/_default/sw.js",
+}
+
+// fingerprint matches the hash Hugo's fingerprint inserts before ".js".
+var fingerprint = regexp.MustCompile(`\.[0-9a-f]{32,128}(\.js)$`)
+
+// File is the coverage of one source file.
+type File struct {
+	// Path is relative to the site root, with forward slashes.
+	Path string
+	// Lines maps 1-based code lines to how often they ran.
+	Lines map[int]int
+	// Functions maps functionKey to the function's call count.
+	Functions map[functionKey]int
+}
+
+// functionKey identifies a function by name and source line.
+type functionKey struct {
+	Name string
+	Line int
+}
+
+// Report accumulates coverage across scripts and test runs.
+type Report struct {
+	root  string
+	files map[string]*File
+	// unmapped lists served scripts that could not be matched to a source.
+	unmapped map[string]string
+	// unmeasured holds sources that were served in a form coverage cannot be
+	// mapped back from, so IncludeAll leaves them out.
+	unmeasured map[string]bool
+}
+
+// NewReport returns an empty report for the site rooted at root.
+func NewReport(root string) *Report {
+	return &Report{
+		root:       root,
+		files:      map[string]*File{},
+		unmapped:   map[string]string{},
+		unmeasured: map[string]bool{},
+	}
+}
+
+// Add merges a script's coverage into the report. Scripts without a source
+// file in SourceRoots are recorded as unmapped rather than failing.
+func (r *Report) Add(s Script) error {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return fmt.Errorf("parse script URL: %w", err)
+	}
+	rel, source, ok := r.findSource(u.Path)
+	if !ok {
+		r.unmapped[u.Path] = "no source file"
+		return nil
+	}
+	lineMap := mapLines(s.Source, source)
+	if len(lineMap) == 0 {
+		r.unmapped[u.Path] = "shares no lines with " + rel
+		r.unmeasured[rel] = true
+		return nil
+	}
+
+	f := r.file(rel, source)
+	served := lineCounts(s.Source, s.Functions)
+	for servedLine, count := range served {
+		if line, ok := lineMap[servedLine]; ok {
+			if _, code := f.Lines[line]; code {
+				f.Lines[line] += count
+			}
+		}
+	}
+
+	starts := lineStarts(utf16.Encode([]rune(s.Source)))
+	for i, fn := range s.Functions {
+		if len(fn.Ranges) == 0 || (i == 0 && fn.Name == "" && fn.Ranges[0].Start == 0) {
+			continue // the script's top level
+		}
+		line, ok := lineMap[lineAt(starts, fn.Ranges[0].Start)]
+		if !ok {
+			continue
+		}
+		name := fn.Name
+		if name == "" {
+			name = fmt.Sprintf("(anonymous_%d)", line)
+		}
+		f.Functions[functionKey{name, line}] += fn.Ranges[0].Count
+	}
+	return nil
+}
+
+// IncludeAll adds every .js file under dir, relative to the site root, that
+// no script has covered, with all of its code lines unexecuted. Sources only
+// seen minified are left out.
+func (r *Report) IncludeAll(dir string) error {
+	return filepath.WalkDir(filepath.Join(r.root, dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".js" {
+			return err
+		}
+		rel, err := filepath.Rel(r.root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if _, seen := r.files[rel]; seen || r.unmeasured[rel] {
+			return nil
+		}
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		r.file(rel, string(src))
+		return nil
+	})
+}
+
+// file returns the entry for a source file, creating it with every code line
+// at zero.
+func (r *Report) file(rel, source string) *File {
+	if f, ok := r.files[rel]; ok {
+		return f
+	}
+	f := &File{Path: rel, Lines: map[int]int{}, Functions: map[functionKey]int{}}
+	for i, line := range codeLines(source) {
+		if line {
+			f.Lines[i+1] = 0
+		}
+	}
+	r.files[rel] = f
+	return f
+}
+
+// findSource returns the source file serving urlPath.
+func (r *Report) findSource(urlPath string) (rel, source string, ok bool) {
+	clean := fingerprint.ReplaceAllString(path.Clean(urlPath), "$1")
+	if rel, ok := LayoutSources[clean]; ok {
+		src, err := os.ReadFile(filepath.Join(r.root, filepath.FromSlash(rel)))
+		return rel, string(src), err == nil
+	}
+	for _, root := range SourceRoots {
+		rel := path.Join(root, clean)
+		src, err := os.ReadFile(filepath.Join(r.root, filepath.FromSlash(rel)))
+		if err == nil {
+			return rel, string(src), true
+		}
+	}
+	return "", "", false
+}
+
+// Files returns the covered files sorted by path.
+func (r *Report) Files() []*File {
+	files := make([]*File, 0, len(r.files))
+	for _, f := range r.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// Unmapped returns the served scripts left out of the report, with why.
+func (r *Report) Unmapped() map[string]string { return r.unmapped }
+
+// Covered returns how many of the file's code lines ran, and how many there are.
+func (f *File) Covered() (hit, total int) {
+	for _, count := range f.Lines {
+		total++
+		if count > 0 {
+			hit++
+		}
+	}
+	return hit, total
+}
+
+// Percent returns the file's line coverage, or 100 for a file without code.
+func (f *File) Percent() float64 {
+	hit, total := f.Covered()
+	if total == 0 {
+		return 100
+	}
+	return float64(hit) * 100 / float64(total)
+}
+
+// lineCounts returns how often each 1-based line of src ran: the most any
+// non-blank character on it ran, counting each character by the innermost
+// range around it.
+func lineCounts(src string, functions []Function) map[int]int {
+	units := utf16.Encode([]rune(src))
+	counts := make([]int, len(units))
+	for i := range counts {
+		counts[i] = -1
+	}
+
+	var ranges []Range
+	for _, fn := range functions {
+		ranges = append(ranges, fn.Ranges...)
+	}
+	// Outer ranges first, so nested blocks overwrite them
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Start != ranges[j].Start {
+			return ranges[i].Start < ranges[j].Start
+		}
+		return ranges[i].End > ranges[j].End
+	})
+	for _, rg := range ranges {
+		for k := max(rg.Start, 0); k < min(rg.End, len(units)); k++ {
+			counts[k] = rg.Count
+		}
+	}
+
+	lines := map[int]int{}
+	line := 1
+	for k, u := range units {
+		switch {
+		case u == '\n':
+			line++
+		case u == ' ' || u == '\t' || u == '\r' || counts[k] < 0:
+		default:
+			if c, seen := lines[line]; !seen || counts[k] > c {
+				lines[line] = counts[k]
+			}
+		}
+	}
+	return lines
+}
+
+// lineStarts returns the UTF-16 offset at which each line begins.
+func lineStarts(units []uint16) []int {
+	starts := []int{0}
+	for k, u := range units {
+		if u == '\n' {
+			starts = append(starts, k+1)
+		}
+	}
+	return starts
+}
+
+// lineAt returns the 1-based line holding a UTF-16 offset.
+func lineAt(starts []int, offset int) int {
+	return sort.Search(len(starts), func(i int) bool { return starts[i] > offset })
+}
+
+// mapLines maps 1-based lines of served to the lines of source they came
+// from. Identical texts map one to one; otherwise each non-blank served line
+// maps to the next source line after the previous match with the same
+// trimmed text.
+func mapLines(served, source string) map[int]int {
+	servedLines := strings.Split(served, "\n")
+	m := make(map[int]int, len(servedLines))
+	if served == source {
+		for i := range servedLines {
+			m[i+1] = i + 1
+		}
+		return m
+	}
+
+	sourceLines := strings.Split(source, "\n")
+	next := 0
+	for i, line := range servedLines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		for j := next; j < len(sourceLines); j++ {
+			if strings.TrimSpace(sourceLines[j]) == line {
+				m[i+1] = j + 1
+				next = j + 1
+				break
+			}
+		}
+	}
+	return m
+}
+
+// codeLines reports for each line of src whether it holds code rather than
+// only whitespace, comments or Hugo template actions, which render to
+// nothing or to text other than the line.
+func codeLines(src string) []bool {
+	lines := strings.Split(src, "\n")
+	code := make([]bool, len(lines))
+	inComment, inTemplate := false, false
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if inComment {
+			end := strings.Index(line, "*/")
+			if end < 0 {
+				continue
+			}
+			inComment = false
+			line = strings.TrimSpace(line[end+2:])
+			if inTemplate {
+				line = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "-"), "}}"))
+			}
+		}
+		inTemplate = strings.HasPrefix(line, "{{/*") || strings.HasPrefix(line, "{{- /*")
+		if inTemplate {
+			line = strings.TrimSpace(strings.TrimPrefix(line[2:], "-"))
+		}
+		if strings.HasPrefix(line, "/*") {
+			end := strings.Index(line[2:], "*/")
+			if end < 0 {
+				inComment = true
+				continue
+			}
+			line = strings.TrimSpace(line[end+4:])
+			if inTemplate {
+				line = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "-"), "}}"))
+			}
+		}
+		action := strings.HasPrefix(line, "{{") && strings.HasSuffix(line, "}}")
+		code[i] = line != "" && !strings.HasPrefix(line, "//") && !action
+	}
+	return code
+}
@@ -0,0 +1,236 @@
+package jscover
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// site writes files, keyed by slash path, under a temporary site root.
+func site(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// TestLineCounts covers nested blocks, untouched lines and UTF-16 offsets.
+func TestLineCounts(t *testing.T) {
+	// "é" is one UTF-16 unit and "😀" is two, so offsets after them differ
+	// from byte offsets
+	src := "function f(x) {\n  if (x) {\n    g('😀é');\n  }\n}\n"
+	fn := Function{Name: "f", Ranges: []Range{
+		{Start: 0, End: 47, Count: 3},
+		{Start: 25, End: 44, Count: 0},
+	}}
+
+	got := lineCounts(src, []Function{fn})
+	want := map[int]int{1: 3, 2: 3, 3: 0, 4: 0, 5: 3}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// TestMapLines covers verbatim and rewritten scripts.
+func TestMapLines(t *testing.T) {
+	source := "const a = 1;\n\nconst url = '{{ .URL }}';\nfunction f() {\n  return a;\n}\n"
+	served := "const a = 1;\nconst url = '/x/';\nfunction f() {\n  return a;\n}\n"
+
+	if got := mapLines(source, source); len(got) != 7 || got[3] != 3 {
+		t.Errorf("identical texts: got %v", got)
+	}
+	want := map[int]int{1: 1, 3: 4, 4: 5, 5: 6}
+	if got := mapLines(served, source); !maps.Equal(got, want) {
+		t.Errorf("rewritten: got %v, want %v", got, want)
+	}
+}
+
+// TestCodeLines covers blank lines, line and block comments and Hugo
+// template actions.
+func TestCodeLines(t *testing.T) {
+	src := "// header\n/**\n * doc\n */\nfunction f() {\n\n  /* inline */ return 1; // trailing\n}\n"
+	want := []bool{false, false, false, false, true, false, true, true, false}
+	if got := codeLines(src); !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	tmpl := "{{/*\n  Template: sw.js\n*/}}\n{{- $css := resources.Get \"x\" -}}\n{{/* one line */}}\nconst v = '{{ $css.RelPermalink }}';\n"
+	want = []bool{false, false, false, false, false, true, false}
+	if got := codeLines(tmpl); !slices.Equal(got, want) {
+		t.Errorf("template: got %v, want %v", got, want)
+	}
+}
+
+// TestReport covers source lookup, fingerprints, unmapped scripts and
+// uncovered files.
+func TestReport(t *testing.T) {
+	src := "function f() {\n  return 1;\n}\nfunction g() {\n  return 2;\n}\nf();\n"
+	root := site(t, map[string]string{
+		"assets/js/app.js":   src,
+		"assets/js/idle.js":  "// never loaded\nidle();\n",
+		"assets/js/data.js":  "const data = {\n  a: 1\n};\n",
+		"static/js/other.js": "x();\n",
+	})
+
+	r := NewReport(root)
+	fns := []Function{
+		{Ranges: []Range{{Start: 0, End: len(src), Count: 1}}},
+		{Name: "f", Ranges: []Range{{Start: 0, End: 28, Count: 2}}},
+		{Name: "g", Ranges: []Range{{Start: 29, End: 58, Count: 0}}},
+	}
+	for _, s := range []Script{
+		{URL: "http://localhost/js/app.js", Source: src, Functions: fns},
+		{URL: "http://localhost/js/app.js?v=2", Source: src, Functions: fns},
+		{URL: "http://localhost/js/data.0123456789abcdef0123456789abcdef.js", Source: "const data={a:1};", Functions: nil},
+		{URL: "http://localhost/js/missing.js", Source: "m();", Functions: nil},
+	} {
+		if err := r.Add(s); err != nil {
+			t.Fatalf("Add(%s): %v", s.URL, err)
+		}
+	}
+	if err := r.IncludeAll("assets/js"); err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, f := range r.Files() {
+		paths = append(paths, f.Path)
+	}
+	if want := []string{"assets/js/app.js", "assets/js/idle.js"}; !slices.Equal(paths, want) {
+		t.Errorf("files: got %q, want %q", paths, want)
+	}
+
+	app := r.Files()[0]
+	wantLines := map[int]int{1: 4, 2: 4, 3: 4, 4: 0, 5: 0, 6: 0, 7: 2}
+	if !maps.Equal(app.Lines, wantLines) {
+		t.Errorf("app.js lines: got %v, want %v", app.Lines, wantLines)
+	}
+	if hit, total := app.Covered(); hit != 4 || total != 7 {
+		t.Errorf("app.js covered %d/%d, want 4/7", hit, total)
+	}
+	wantFuncs := map[functionKey]int{{"f", 1}: 4, {"g", 4}: 0}
+	if !maps.Equal(app.Functions, wantFuncs) {
+		t.Errorf("app.js functions: got %v, want %v", app.Functions, wantFuncs)
+	}
+
+	unmapped := r.Unmapped()
+	if !strings.HasPrefix(unmapped["/js/data.0123456789abcdef0123456789abcdef.js"], "shares no lines with assets/js/data.js") {
+		t.Errorf("minified data.js: got %q", unmapped)
+	}
+	if unmapped["/js/missing.js"] != "no source file" {
+		t.Errorf("missing.js: got %q", unmapped)
+	}
+}
+
+// TestLayoutSource covers a script Hugo renders from a layout template, as
+// it does the service worker.
+func TestLayoutSource(t *testing.T) {
+	tmpl := "{{/*\n  Template: sw.js\n*/}}\n{{- $v := \"1\" -}}\nconst VERSION = '{{ $v }}';\nself.addEventListener('fetch', () => {\n  respond();\n});\n"
+	served := "\nconst VERSION = '1';\nself.addEventListener('fetch', () => {\n  respond();\n});\n"
+	r := NewReport(site(t, map[string]string{"layouts/_default/sw.js": tmpl}))
+	err := r.Add(Script{URL: "http://localhost/sw.js", Source: served, Functions: []Function{
+		{Ranges: []Range{{Start: 0, End: len(served), Count: 1}}},
+		{Ranges: []Range{{Start: 59, End: len(served) - 3, Count: 0}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := r.Files()
+	if len(files) != 1 || files[0].Path != "layouts/_default/sw.js" {
+		t.Fatalf("files: got %v, unmapped %v", files, r.Unmapped())
+	}
+	want := map[int]int{5: 0, 6: 1, 7: 0, 8: 1}
+	if !maps.Equal(files[0].Lines, want) {
+		t.Errorf("sw.js lines: got %v, want %v", files[0].Lines, want)
+	}
+}
+
+// TestWriteLCOV checks the tracefile records of one file.
+func TestWriteLCOV(t *testing.T) {
+	src := "function f() {\n  return 1;\n}\nf();\n"
+	root := site(t, map[string]string{"assets/js/app.js": src})
+	r := NewReport(root)
+	err := r.Add(Script{URL: "http://localhost/js/app.js", Source: src, Functions: []Function{
+		{Ranges: []Range{{Start: 0, End: len(src), Count: 1}}},
+		{Name: "f", Ranges: []Range{{Start: 0, End: 28, Count: 1}}},
+		{Ranges: []Range{{Start: 0, End: 5, Count: 0}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteLCOV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `TN:
+SF:assets/js/app.js
+FN:1,(anonymous_1)
+FN:1,f
+FNDA:0,(anonymous_1)
+FNDA:1,f
+FNF:2
+FNH:1
+DA:1,1
+DA:2,1
+DA:3,1
+DA:4,1
+LF:4
+LH:4
+end_of_record
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<td>assets/js/app.js</td><td>4/4</td><td>100.0</td>") {
+		t.Errorf("HTML summary lacks the app.js row:\n%s", buf.String())
+	}
+}
+
+// TestThresholds covers parsing and checking minimum coverage.
+func TestThresholds(t *testing.T) {
+	th, err := ParseThresholds("50, assets/js/b.js=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Default != 50 || th.Files["assets/js/b.js"] != 20 {
+		t.Errorf("got %+v", th)
+	}
+	for _, bad := range []string{"x", "120", "50,30", "assets/js/a.js=-1"} {
+		if _, err := ParseThresholds(bad); err == nil {
+			t.Errorf("ParseThresholds(%q) succeeded", bad)
+		}
+	}
+
+	r := NewReport(t.TempDir())
+	r.files["assets/js/a.js"] = &File{Path: "assets/js/a.js", Lines: map[int]int{1: 1, 2: 0, 3: 0}}
+	r.files["assets/js/b.js"] = &File{Path: "assets/js/b.js", Lines: map[int]int{1: 1, 2: 0, 3: 0}}
+	r.files["assets/js/empty.js"] = &File{Path: "assets/js/empty.js", Lines: map[int]int{}}
+	th.Files["assets/js/gone.js"] = 10
+
+	want := []string{
+		"assets/js/a.js: 33.3% line coverage, minimum 50.0%",
+		"assets/js/gone.js: has a coverage threshold but no coverage",
+	}
+	if got := r.Check(th); !slices.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
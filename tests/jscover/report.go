@@ -0,0 +1,191 @@
+package jscover
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteLCOV writes the report as an lcov tracefile, with paths relative to
+// the site root.
+func (r *Report) WriteLCOV(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range r.Files() {
+		fmt.Fprintf(bw, "TN:\nSF:%s\n", f.Path)
+
+		keys := make([]functionKey, 0, len(f.Functions))
+		for k := range f.Functions {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].Line != keys[j].Line {
+				return keys[i].Line < keys[j].Line
+			}
+			return keys[i].Name < keys[j].Name
+		})
+		fnHit := 0
+		for _, k := range keys {
+			fmt.Fprintf(bw, "FN:%d,%s\n", k.Line, k.Name)
+		}
+		for _, k := range keys {
+			fmt.Fprintf(bw, "FNDA:%d,%s\n", f.Functions[k], k.Name)
+			if f.Functions[k] > 0 {
+				fnHit++
+			}
+		}
+		fmt.Fprintf(bw, "FNF:%d\nFNH:%d\n", len(keys), fnHit)
+
+		lines := make([]int, 0, len(f.Lines))
+		for line := range f.Lines {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+		for _, line := range lines {
+			fmt.Fprintf(bw, "DA:%d,%d\n", line, f.Lines[line])
+		}
+		hit, total := f.Covered()
+		fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", total, hit)
+	}
+	return bw.Flush()
+}
+
+// summaryRow is one file of the HTML summary.
+type summaryRow struct {
+	Path                 string
+	Hit, Total           int
+	Percent              float64
+	FuncsHit, FuncsTotal int
+}
+
+var summaryTemplate = template.Must(template.New("summary").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>JavaScript coverage</title>
+<style>
+body { font: 14px system-ui, sans-serif; margin: 2rem; }
+table { border-collapse: collapse; }
+th, td { padding: .25rem .75rem; text-align: right; border-bottom: 1px solid #ddd; }
+th:first-child, td:first-child { text-align: left; }
+.bar { width: 10rem; background: #f3d3d3; }
+.bar div { height: .75rem; background: #3a8f3a; }
+tfoot td { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>JavaScript coverage</h1>
+<table>
+<thead><tr><th>File</th><th>Lines</th><th>%</th><th></th><th>Functions</th></tr></thead>
+<tbody>
+{{- range .Rows }}
+<tr><td>{{ .Path }}</td><td>{{ .Hit }}/{{ .Total }}</td><td>{{ printf "%.1f" .Percent }}</td><td class="bar"><div style="width: {{ printf "%.0f" .Percent }}%"></div></td><td>{{ .FuncsHit }}/{{ .FuncsTotal }}</td></tr>
+{{- end }}
+</tbody>
+<tfoot><tr><td>Total</td><td>{{ .Total.Hit }}/{{ .Total.Total }}</td><td>{{ printf "%.1f" .Total.Percent }}</td><td></td><td>{{ .Total.FuncsHit }}/{{ .Total.FuncsTotal }}</td></tr></tfoot>
+</table>
+{{- if .Unmapped }}
+<h2>Unmapped scripts</h2>
+<ul>
+{{- range .Unmapped }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+</body>
+</html>
+`))
+
+// WriteHTML writes a one-page summary of line and function coverage per file.
+func (r *Report) WriteHTML(w io.Writer) error {
+	var data struct {
+		Rows     []summaryRow
+		Total    summaryRow
+		Unmapped []string
+	}
+	for _, f := range r.Files() {
+		row := summaryRow{Path: f.Path, Percent: f.Percent(), FuncsTotal: len(f.Functions)}
+		row.Hit, row.Total = f.Covered()
+		for _, count := range f.Functions {
+			if count > 0 {
+				row.FuncsHit++
+			}
+		}
+		data.Rows = append(data.Rows, row)
+		data.Total.Hit += row.Hit
+		data.Total.Total += row.Total
+		data.Total.FuncsHit += row.FuncsHit
+		data.Total.FuncsTotal += row.FuncsTotal
+	}
+	data.Total.Percent = 100
+	if data.Total.Total > 0 {
+		data.Total.Percent = float64(data.Total.Hit) * 100 / float64(data.Total.Total)
+	}
+	for url, why := range r.unmapped {
+		data.Unmapped = append(data.Unmapped, url+": "+why)
+	}
+	sort.Strings(data.Unmapped)
+	return summaryTemplate.Execute(w, data)
+}
+
+// Thresholds are minimum line coverage percentages.
+type Thresholds struct {
+	// Default applies to every file without its own entry. Zero disables it.
+	Default float64
+	// Files maps source paths, relative to the site root, to their minimum.
+	Files map[string]float64
+}
+
+// ParseThresholds reads "PCT" or "PCT,path=PCT,..." such as
+// "40,assets/js/parallel.js=25". The leading default may be omitted.
+func ParseThresholds(s string) (Thresholds, error) {
+	th := Thresholds{Files: map[string]float64{}}
+	for i, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, perFile := strings.Cut(part, "=")
+		if !perFile {
+			value = name
+		}
+		pct, err := strconv.ParseFloat(value, 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return th, fmt.Errorf("invalid coverage threshold %q", part)
+		}
+		switch {
+		case perFile:
+			th.Files[strings.TrimSpace(name)] = pct
+		case i == 0:
+			th.Default = pct
+		default:
+			return th, fmt.Errorf("coverage threshold %q needs a path", part)
+		}
+	}
+	return th, nil
+}
+
+// Check returns a message for each file whose line coverage is below its
+// threshold, and for each per-file threshold naming a file not in the report.
+func (r *Report) Check(th Thresholds) []string {
+	var failures []string
+	for _, f := range r.Files() {
+		minimum, ok := th.Files[f.Path]
+		if !ok {
+			minimum = th.Default
+		}
+		if got := f.Percent(); got < minimum {
+			failures = append(failures, fmt.Sprintf("%s: %.1f%% line coverage, minimum %.1f%%", f.Path, got, minimum))
+		}
+	}
+	for path := range th.Files {
+		if _, ok := r.files[path]; !ok {
+			failures = append(failures, fmt.Sprintf("%s: has a coverage threshold but no coverage", path))
+		}
+	}
+	sort.Strings(failures)
+	return failures
+}
@@ -0,0 +1,257 @@
+package pdftext
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// font maps a font's character codes to text and glyph widths.
+type font struct {
+	// lengths are the code lengths in bytes, shortest first.
+	lengths []int
+	// unicode maps codes, as raw bytes, to the text they stand for.
+	unicode map[string]string
+	// widths are glyph advances in thousandths of an em.
+	widths       map[int]float64
+	defaultWidth float64
+}
+
+// font returns the font for a /Font resource entry, loading it once per
+// object.
+func (f *file) font(v any) *font {
+	r, isRef := v.(ref)
+	if isRef {
+		if ft, ok := f.fonts[r]; ok {
+			return ft
+		}
+	}
+	d, ok := f.resolve(v).(dict)
+	if !ok {
+		return nil
+	}
+
+	ft := &font{lengths: []int{1}, widths: map[int]float64{}, defaultWidth: 1000}
+	if d["Subtype"] == name("Type0") {
+		ft.lengths = []int{2}
+		descendants, _ := f.resolve(d["DescendantFonts"]).(array)
+		if len(descendants) > 0 {
+			if cid, ok := f.resolve(descendants[0]).(dict); ok {
+				f.cidWidths(cid, ft)
+			}
+		}
+	} else {
+		f.simpleWidths(d, ft)
+	}
+	if s, ok := f.resolve(d["ToUnicode"]).(stream); ok {
+		if data, err := f.decode(s); err == nil {
+			ft.parseCMap(data)
+		}
+	}
+
+	if isRef {
+		f.fonts[r] = ft
+	}
+	return ft
+}
+
+// cidWidths reads a CIDFont's /DW and /W widths.
+func (f *file) cidWidths(d dict, ft *font) {
+	if dw, ok := f.resolve(d["DW"]).(float64); ok {
+		ft.defaultWidth = dw
+	}
+	w, _ := f.resolve(d["W"]).(array)
+	for i := 0; i+1 < len(w); {
+		first, _ := f.resolve(w[i]).(float64)
+		// Either "c [w1 w2 ...]" or "cFirst cLast w"
+		if list, ok := f.resolve(w[i+1]).(array); ok {
+			for j, v := range list {
+				if width, ok := f.resolve(v).(float64); ok {
+					ft.widths[int(first)+j] = width
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		last, _ := f.resolve(w[i+1]).(float64)
+		width, _ := f.resolve(w[i+2]).(float64)
+		for c := int(first); c <= int(last) && c-int(first) < 1<<16; c++ {
+			ft.widths[c] = width
+		}
+		i += 3
+	}
+}
+
+// simpleWidths reads a simple font's /FirstChar and /Widths.
+func (f *file) simpleWidths(d dict, ft *font) {
+	if desc, ok := f.resolve(d["FontDescriptor"]).(dict); ok {
+		if mw, ok := f.resolve(desc["MissingWidth"]).(float64); ok {
+			ft.defaultWidth = mw
+		}
+	}
+	first, _ := f.resolve(d["FirstChar"]).(float64)
+	widths, _ := f.resolve(d["Widths"]).(array)
+	for i, v := range widths {
+		if width, ok := f.resolve(v).(float64); ok {
+			ft.widths[int(first)+i] = width
+		}
+	}
+}
+
+// parseCMap reads the code lengths and mappings of a ToUnicode CMap.
+func (ft *font) parseCMap(data []byte) {
+	ft.unicode = map[string]string{}
+	l := &lexer{data: data}
+	var operands []any
+	for {
+		v, err := l.object()
+		if err != nil {
+			return
+		}
+		op, ok := v.(keyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+		switch op {
+		case "endcodespacerange":
+			var lengths []int
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].(pdfBytes); ok && len(lo) > 0 && !slices.Contains(lengths, len(lo)) {
+					lengths = append(lengths, len(lo))
+				}
+			}
+			if len(lengths) > 0 {
+				slices.Sort(lengths)
+				ft.lengths = lengths
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfBytes)
+				dst, ok2 := operands[i+1].(pdfBytes)
+				if ok1 && ok2 {
+					ft.unicode[string(src)] = utf16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfBytes)
+				hi, ok2 := operands[i+1].(pdfBytes)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) > 4 {
+					continue
+				}
+				ft.mapRange(lo, codeValue(hi)-codeValue(lo), operands[i+2])
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// mapRange maps n+1 codes from lo to either successive text, incrementing
+// the last UTF-16 unit of dst, or the strings of an array.
+func (ft *font) mapRange(lo []byte, n int, dst any) {
+	if n < 0 || n > 1<<16 {
+		return
+	}
+	code := append([]byte(nil), lo...)
+	for i := 0; i <= n; i++ {
+		switch d := dst.(type) {
+		case pdfBytes:
+			if len(d) < 2 {
+				return
+			}
+			units := append([]byte(nil), d...)
+			last := int(units[len(units)-2])<<8 | int(units[len(units)-1])
+			last += i
+			units[len(units)-2], units[len(units)-1] = byte(last>>8), byte(last)
+			ft.unicode[string(code)] = utf16BE(units)
+		case array:
+			if i < len(d) {
+				if s, ok := d[i].(pdfBytes); ok {
+					ft.unicode[string(code)] = utf16BE(s)
+				}
+			}
+		}
+		// Increment the code as a big-endian number
+		for j := len(code) - 1; j >= 0; j-- {
+			if code[j]++; code[j] != 0 {
+				break
+			}
+		}
+	}
+}
+
+// decode returns the text of a string shown in the font and its advance in
+// thousandths of an em. A nil font reads bytes as Latin-1.
+func (ft *font) decode(codes []byte) (string, float64) {
+	if ft == nil {
+		return latin1(codes), 500 * float64(len(codes))
+	}
+	var sb strings.Builder
+	var advance float64
+	for len(codes) > 0 {
+		n := ft.codeLength(codes)
+		code := codes[:n]
+		codes = codes[n:]
+
+		if w, ok := ft.widths[codeValue(code)]; ok {
+			advance += w
+		} else {
+			advance += ft.defaultWidth
+		}
+		if s, ok := ft.unicode[string(code)]; ok {
+			sb.WriteString(s)
+		} else if ft.unicode == nil && n == 1 {
+			sb.WriteString(latin1(code))
+		} else {
+			sb.WriteRune(unicode.ReplacementChar)
+		}
+	}
+	return sb.String(), advance
+}
+
+// codeLength returns the length of the code at the start of codes: the
+// shortest mapped one, else the shortest the font uses.
+func (ft *font) codeLength(codes []byte) int {
+	for _, n := range ft.lengths {
+		if n <= len(codes) {
+			if _, ok := ft.unicode[string(codes[:n])]; ok {
+				return n
+			}
+		}
+	}
+	if n := ft.lengths[0]; n <= len(codes) {
+		return n
+	}
+	return len(codes)
+}
+
+// codeValue returns a code as a big-endian number.
+func codeValue(code []byte) int {
+	v := 0
+	for _, c := range code {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+// utf16BE decodes UTF-16BE text.
+func utf16BE(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
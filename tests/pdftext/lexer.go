@@ -0,0 +1,294 @@
+package pdftext
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// The PDF object types, as the lexer returns them. Numbers are float64,
+// booleans bool and null nil.
+type (
+	name     string
+	keyword  string
+	pdfBytes []byte
+	dict     map[name]any
+	array    []any
+	ref      struct{ num, gen int }
+)
+
+// stream is a stream object: its dictionary and its still-encoded data.
+type stream struct {
+	dict dict
+	data []byte
+}
+
+// Delimiters returned as keywords.
+const (
+	dictStart  keyword = "<<"
+	dictEnd    keyword = ">>"
+	arrayStart keyword = "["
+	arrayEnd   keyword = "]"
+)
+
+var errEOF = errors.New("unexpected end of data")
+
+// lexer reads PDF tokens and objects from a byte slice.
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isDelim(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// skipSpace skips whitespace and comments.
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch c := l.data[l.pos]; {
+		case isSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// token returns the next token.
+func (l *lexer) token() (any, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, errEOF
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return l.name(), nil
+	case c == '(':
+		l.pos++
+		return l.literal()
+	case c == '<' && l.peek(1) == '<':
+		l.pos += 2
+		return dictStart, nil
+	case c == '>' && l.peek(1) == '>':
+		l.pos += 2
+		return dictEnd, nil
+	case c == '<':
+		l.pos++
+		return l.hex()
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return keyword(c), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		start := l.pos
+		for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		f, err := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q at %d", l.data[start:l.pos], start)
+		}
+		return f, nil
+	default:
+		start := l.pos
+		for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		if l.pos == start {
+			return nil, fmt.Errorf("unexpected %q at %d", c, start)
+		}
+		return keyword(l.data[start:l.pos]), nil
+	}
+}
+
+func (l *lexer) peek(n int) byte {
+	if l.pos+n < len(l.data) {
+		return l.data[l.pos+n]
+	}
+	return 0
+}
+
+// name reads a name after its slash, decoding #xx escapes.
+func (l *lexer) name() name {
+	var b []byte
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		l.pos++
+	}
+	return name(b)
+}
+
+// literal reads a (string) after its opening parenthesis.
+func (l *lexer) literal() (pdfBytes, error) {
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return b, nil
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return nil, errEOF
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.peek(0) == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return nil, errEOF
+}
+
+// hex reads a <hex string> after its opening bracket.
+func (l *lexer) hex() (pdfBytes, error) {
+	var digits []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		if c == '>' {
+			if len(digits)%2 == 1 {
+				digits = append(digits, '0')
+			}
+			b := make([]byte, len(digits)/2)
+			for i := range b {
+				v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+				if err != nil {
+					return nil, fmt.Errorf("bad hex string at %d", l.pos)
+				}
+				b[i] = byte(v)
+			}
+			return b, nil
+		}
+		if !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	return nil, errEOF
+}
+
+// object reads the next object, combining "num gen R" into a ref. Operators
+// and other bare words come back as keywords.
+func (l *lexer) object() (any, error) {
+	tok, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case dictStart:
+		d := dict{}
+		for {
+			key, err := l.object()
+			if err != nil {
+				return nil, err
+			}
+			if key == dictEnd {
+				return d, nil
+			}
+			k, ok := key.(name)
+			if !ok {
+				return nil, fmt.Errorf("dictionary key %v is not a name", key)
+			}
+			if d[k], err = l.object(); err != nil {
+				return nil, err
+			}
+		}
+	case arrayStart:
+		var a array
+		for {
+			v, err := l.object()
+			if err != nil {
+				return nil, err
+			}
+			if v == arrayEnd {
+				return a, nil
+			}
+			a = append(a, v)
+		}
+	case keyword("true"):
+		return true, nil
+	case keyword("false"):
+		return false, nil
+	case keyword("null"):
+		return nil, nil
+	}
+
+	if num, ok := tok.(float64); ok && num == float64(int(num)) {
+		save := l.pos
+		if gen, err := l.token(); err == nil {
+			if g, ok := gen.(float64); ok && g == float64(int(g)) {
+				if r, err := l.token(); err == nil && r == keyword("R") {
+					return ref{int(num), int(g)}, nil
+				}
+			}
+		}
+		l.pos = save
+	}
+	return tok, nil
+}
+
+// skipInlineImage moves past the data of an inline image, from just after
+// its ID operator to just after EI.
+func (l *lexer) skipInlineImage() {
+	for i := l.pos; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && i > 0 && isSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isSpace(l.data[i+2]) || isDelim(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,413 @@
+// Package pdftext extracts the text layer of PDFs written by Chrome's
+// print-to-PDF, so tests can check what a printed page says.
+//
+// It reads the uncompressed cross-reference layout Skia writes: objects are
+// found by scanning for "N G obj" rather than through the xref table, and
+// object streams are not supported. Text is decoded through each font's
+// ToUnicode CMap. Runs are joined with a newline where the baseline moves and
+// a space where a gap follows the previous run, judged from the font widths,
+// so spacing is approximate; compare text with whitespace removed where it
+// matters.
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Document is the text of a PDF, page by page.
+type Document struct {
+	Pages []string
+}
+
+// Text returns the text of all pages, separated by form feeds.
+func (d *Document) Text() string {
+	return strings.Join(d.Pages, "\f")
+}
+
+// objHeader matches the start of an indirect object.
+var objHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// file resolves the objects of a PDF.
+type file struct {
+	data    []byte
+	offsets map[int]int
+	cache   map[int]any
+	fonts   map[ref]*font
+}
+
+// Parse extracts the text of every page of a PDF.
+func Parse(data []byte) (*Document, error) {
+	f := &file{data: data, offsets: map[int]int{}, cache: map[int]any{}, fonts: map[ref]*font{}}
+	for _, m := range objHeader.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		// Later definitions replace earlier ones, as in an incremental update
+		f.offsets[num] = m[1]
+	}
+
+	root, err := f.catalog()
+	if err != nil {
+		return nil, err
+	}
+	pagesRoot, ok := f.resolve(root["Pages"]).(dict)
+	if !ok {
+		return nil, fmt.Errorf("catalog has no page tree")
+	}
+	var pages []pageNode
+	if err := f.walkPages(pagesRoot, nil, &pages, 0); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	for i, p := range pages {
+		text, err := f.pageText(p)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", i+1, err)
+		}
+		doc.Pages = append(doc.Pages, text)
+	}
+	return doc, nil
+}
+
+// catalog returns the document catalog, from the trailer or else by search.
+func (f *file) catalog() (dict, error) {
+	if i := bytes.LastIndex(f.data, []byte("trailer")); i >= 0 {
+		l := &lexer{data: f.data, pos: i + len("trailer")}
+		if trailer, err := l.object(); err == nil {
+			if d, ok := trailer.(dict); ok {
+				if root, ok := f.resolve(d["Root"]).(dict); ok {
+					return root, nil
+				}
+			}
+		}
+	}
+	for num := range f.offsets {
+		if d, ok := f.resolve(ref{num: num}).(dict); ok && d["Type"] == name("Catalog") {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("no document catalog")
+}
+
+// resolve follows references, returning nil for missing objects.
+func (f *file) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		r, ok := v.(ref)
+		if !ok {
+			return v
+		}
+		v = f.load(r.num)
+	}
+	return nil
+}
+
+// load reads indirect object num.
+func (f *file) load(num int) any {
+	if v, ok := f.cache[num]; ok {
+		return v
+	}
+	off, ok := f.offsets[num]
+	if !ok {
+		return nil
+	}
+	f.cache[num] = nil // guards against reference cycles through /Length
+
+	l := &lexer{data: f.data, pos: off}
+	v, err := l.object()
+	if err != nil {
+		return nil
+	}
+	if d, ok := v.(dict); ok {
+		save := l.pos
+		if tok, err := l.token(); err == nil && tok == keyword("stream") {
+			v = f.streamData(d, l.pos)
+		} else {
+			l.pos = save
+		}
+	}
+	f.cache[num] = v
+	return v
+}
+
+// streamData slices out the data of a stream whose keyword ends at pos.
+func (f *file) streamData(d dict, pos int) stream {
+	if pos < len(f.data) && f.data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(f.data) && f.data[pos] == '\n' {
+		pos++
+	}
+	if n, ok := f.resolve(d["Length"]).(float64); ok && pos+int(n) <= len(f.data) {
+		return stream{dict: d, data: f.data[pos : pos+int(n)]}
+	}
+	end := bytes.Index(f.data[pos:], []byte("endstream"))
+	if end < 0 {
+		end = len(f.data) - pos
+	}
+	return stream{dict: d, data: bytes.TrimRight(f.data[pos:pos+end], "\r\n")}
+}
+
+// decode returns a stream's data with its filters undone.
+func (f *file) decode(s stream) ([]byte, error) {
+	var filters []any
+	switch v := f.resolve(s.dict["Filter"]).(type) {
+	case nil:
+	case name:
+		filters = []any{v}
+	case array:
+		filters = v
+	}
+	data := s.data
+	for _, filter := range filters {
+		switch f.resolve(filter) {
+		case name("FlateDecode"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			// Chrome's streams are complete, but be lenient like viewers are
+			out, err := io.ReadAll(r)
+			if err != nil && len(out) == 0 {
+				return nil, err
+			}
+			data = out
+		default:
+			return nil, fmt.Errorf("unsupported filter %v", filter)
+		}
+	}
+	return data, nil
+}
+
+// pageNode is a leaf of the page tree with its inherited resources.
+type pageNode struct {
+	dict      dict
+	resources dict
+}
+
+// walkPages collects the leaves of the page tree in order.
+func (f *file) walkPages(node, resources dict, out *[]pageNode, depth int) error {
+	if depth > 64 {
+		return fmt.Errorf("page tree too deep")
+	}
+	if r, ok := f.resolve(node["Resources"]).(dict); ok {
+		resources = r
+	}
+	if node["Type"] == name("Page") {
+		*out = append(*out, pageNode{dict: node, resources: resources})
+		return nil
+	}
+	kids, _ := f.resolve(node["Kids"]).(array)
+	for _, kid := range kids {
+		if d, ok := f.resolve(kid).(dict); ok {
+			if err := f.walkPages(d, resources, out, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pageText returns the text a page draws.
+func (f *file) pageText(p pageNode) (string, error) {
+	var contents []any
+	switch v := f.resolve(p.dict["Contents"]).(type) {
+	case stream:
+		contents = []any{v}
+	case array:
+		contents = v
+	}
+	var data []byte
+	for _, c := range contents {
+		s, ok := f.resolve(c).(stream)
+		if !ok {
+			continue
+		}
+		d, err := f.decode(s)
+		if err != nil {
+			return "", err
+		}
+		data = append(append(data, d...), '\n')
+	}
+
+	w := &textWriter{scale: 1}
+	if err := f.runContent(data, p.resources, w, 0); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(w.sb.String()), nil
+}
+
+// textWriter tracks the text position and joins shown runs into lines.
+// Positions are in text space scaled by the text matrix, which Chrome only
+// uses to translate and flip.
+type textWriter struct {
+	sb strings.Builder
+
+	font  *font
+	size  float64
+	scale float64
+	// lineX and lineY are the start of the current line; x is the pen.
+	lineX, lineY, x float64
+	// endX and endY are where the last shown run ended.
+	endX, endY float64
+	shown      bool
+	// space is whether the text so far ends in whitespace.
+	space bool
+}
+
+// moveTo starts a new line at x, y.
+func (w *textWriter) moveTo(x, y float64) {
+	w.lineX, w.lineY, w.x = x, y, x
+}
+
+// show appends the text of codes and advances the pen past them. The text is
+// put on a new line if the baseline moved since the last run, or after a
+// space if it starts over a fifth of an em from where the last run ended.
+func (w *textWriter) show(codes []byte) {
+	text, advance := w.font.decode(codes)
+	em := math.Max(w.size*w.scale, 1)
+	start := w.x
+	w.x += advance / 1000 * em
+	if text == "" {
+		return
+	}
+
+	runes := []rune(text)
+	if w.shown && !w.space && !unicode.IsSpace(runes[0]) {
+		switch {
+		case math.Abs(w.lineY-w.endY) > em/4:
+			w.sb.WriteByte('\n')
+		case start-w.endX > em/5 || w.endX-start > em:
+			w.sb.WriteByte(' ')
+		}
+	}
+	w.sb.WriteString(text)
+	w.shown = true
+	w.space = unicode.IsSpace(runes[len(runes)-1])
+	w.endX, w.endY = w.x, w.lineY
+}
+
+// runContent interprets a content stream's text operators, descending into
+// form XObjects.
+func (f *file) runContent(data []byte, resources dict, w *textWriter, depth int) error {
+	if depth > 16 {
+		return nil
+	}
+	fonts, _ := f.resolve(resources["Font"]).(dict)
+	xobjects, _ := f.resolve(resources["XObject"]).(dict)
+	var leading float64
+
+	l := &lexer{data: data}
+	var operands []any
+	for {
+		v, err := l.object()
+		if err == errEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		op, ok := v.(keyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+
+		num := func(i int) float64 {
+			if i < len(operands) {
+				n, _ := operands[i].(float64)
+				return n
+			}
+			return 0
+		}
+		str := func(i int) []byte {
+			if i < len(operands) {
+				b, _ := operands[i].(pdfBytes)
+				return b
+			}
+			return nil
+		}
+		nextLine := func() {
+			w.moveTo(w.lineX, w.lineY-math.Max(leading, w.size)*w.scale)
+		}
+
+		switch op {
+		case "BT":
+			w.scale = 1
+			w.moveTo(0, 0)
+		case "Tf":
+			if len(operands) > 1 {
+				if n, ok := operands[0].(name); ok {
+					w.font = f.font(fonts[n])
+				}
+				w.size = num(1)
+			}
+		case "TL":
+			leading = num(0)
+		case "Td":
+			w.moveTo(w.lineX+num(0)*w.scale, w.lineY+num(1)*w.scale)
+		case "TD":
+			leading = -num(1)
+			w.moveTo(w.lineX+num(0)*w.scale, w.lineY+num(1)*w.scale)
+		case "Tm":
+			w.scale = math.Max(math.Hypot(num(0), num(1)), 1e-6)
+			w.moveTo(num(4), num(5))
+		case "T*":
+			nextLine()
+		case "Tj":
+			w.show(str(0))
+		case "'":
+			nextLine()
+			w.show(str(0))
+		case "\"":
+			nextLine()
+			w.show(str(2))
+		case "TJ":
+			var items array
+			if len(operands) > 0 {
+				items, _ = operands[0].(array)
+			}
+			for _, item := range items {
+				switch v := item.(type) {
+				case pdfBytes:
+					w.show(v)
+				case float64:
+					w.x -= v / 1000 * w.size * w.scale
+				}
+			}
+		case "Do":
+			if len(operands) > 0 {
+				if n, ok := operands[0].(name); ok {
+					if s, ok := f.resolve(xobjects[n]).(stream); ok && s.dict["Subtype"] == name("Form") {
+						d, err := f.decode(s)
+						if err != nil {
+							return err
+						}
+						res, _ := f.resolve(s.dict["Resources"]).(dict)
+						if res == nil {
+							res = resources
+						}
+						if err := f.runContent(d, res, w, depth+1); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		case "BI":
+			for {
+				tok, err := l.token()
+				if err != nil || tok == keyword("ID") {
+					break
+				}
+			}
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
@@ -0,0 +1,144 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildPDF numbers objects from 1 in order and points the trailer at the
+// first as the catalog.
+func buildPDF(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	for i, o := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	b.WriteString("trailer\n<< /Size 99 /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+// flateStream returns a compressed stream object holding data.
+func flateStream(extra, data string) string {
+	var z bytes.Buffer
+	w := zlib.NewWriter(&z)
+	w.Write([]byte(data))
+	w.Close()
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode %s >>\nstream\n%s\nendstream", z.Len(), extra, z.String())
+}
+
+// TestLexer covers strings, names, references and containers.
+func TestLexer(t *testing.T) {
+	src := `(a (nested\) \(x) \101\n) <48 65 6> /A#20B 3 0 R [1 -2.5 true null] << /K /V >> Tj`
+	l := &lexer{data: []byte(src)}
+	want := []any{
+		pdfBytes("a (nested) (x) A\n"),
+		pdfBytes("He`"),
+		name("A B"),
+		ref{3, 0},
+		array{1.0, -2.5, true, nil},
+		dict{"K": name("V")},
+		keyword("Tj"),
+	}
+	for i, w := range want {
+		got, err := l.object()
+		if err != nil {
+			t.Fatalf("object %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("object %d: got %#v, want %#v", i, got, w)
+		}
+	}
+	if _, err := l.object(); err != errEOF {
+		t.Errorf("expected end of data, got %v", err)
+	}
+}
+
+// TestParse covers a two-page document with a CID font mapped through a
+// ToUnicode CMap, a simple font without one, inherited resources, a form
+// XObject and an inline image.
+func TestParse(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+1 beginbfchar
+<0001> <0031>
+endbfchar
+2 beginbfrange
+<0010> <0019> <0041>
+<0020> <0021> [<00660069> <0020>]
+endbfrange
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end`
+	page1 := `BT
+/F1 10 Tf
+1 0 0 -1 72 700 Tm
+[<0001>] TJ
+[<0010> -400 <0011>] TJ
+1 0 0 -1 72 712 Tm
+<00200021> Tj
+<0012> Tj
+ET
+BT
+/F2 12 Tf
+14 TL
+72 650 Td
+(Hello \(world\)) Tj
+T*
+(Next) Tj
+ET`
+	form := `BT
+/F2 10 Tf
+10 10 Td
+(Two) Tj
+20 0 Td
+(Words) Tj
+ET`
+	page2 := "q\nBI /W 2 /H 1 /BPC 8 /CS /G ID ab EI\nQ\nq\n/X1 Do\nQ"
+
+	data := buildPDF(
+		`<< /Type /Catalog /Pages 2 0 R >>`,
+		`<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 /Resources << /Font << /F1 5 0 R /F2 8 0 R >> >> >>`,
+		`<< /Type /Page /Parent 2 0 R /Contents 10 0 R >>`,
+		`<< /Type /Page /Parent 2 0 R /Contents [11 0 R] /Resources << /Font << /F2 8 0 R >> /XObject << /X1 12 0 R >> >> >>`,
+		`<< /Type /Font /Subtype /Type0 /BaseFont /Sans /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 7 0 R >>`,
+		`<< /Type /Font /Subtype /CIDFontType2 /DW 600 /W [16 [500 500 500] 32 33 400] >>`,
+		flateStream("", cmap),
+		`<< /Type /Font /Subtype /TrueType /BaseFont /Serif /FontDescriptor 9 0 R >>`,
+		`<< /Type /FontDescriptor /MissingWidth 500 >>`,
+		flateStream("", page1),
+		fmt.Sprintf("<< /Length 13 0 R >>\nstream\n%s\nendstream", page2),
+		flateStream("/Type /XObject /Subtype /Form /BBox [0 0 100 100]", form),
+		fmt.Sprint(len(page2)),
+	)
+
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	want := []string{
+		"1A B\nfi C\nHello (world)\nNext",
+		"Two Words",
+	}
+	if !reflect.DeepEqual(doc.Pages, want) {
+		t.Errorf("got pages %q, want %q", doc.Pages, want)
+	}
+	if got := doc.Text(); got != strings.Join(want, "\f") {
+		t.Errorf("got text %q", got)
+	}
+}
+
+// TestParseInvalid covers data that is not a PDF.
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte("<html></html>")); err == nil {
+		t.Error("expected an error for data without a catalog")
+	}
+}
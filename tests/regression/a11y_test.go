@@ -0,0 +1,76 @@
+package regression
+
+import (
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+	"michael-tests/helpers/audit"
+)
+
+// a11yPages lists every page type with the steps that bring it into an
+// audited state. Interactive states (loaded compare content, SSS mode, search
+// results) are audited because they render controls the initial page lacks.
+var a11yPages = []struct {
+	name  string
+	setup func(t *testing.T, b *e2e.Browser)
+}{
+	{"home", func(t *testing.T, b *e2e.Browser) {
+		if err := b.Navigate(helpers.BaseURL + "/"); err != nil {
+			t.Fatalf("Failed to navigate to home page: %v", err)
+		}
+	}},
+	{"bible-list", helpers.NavigateToBiblesList},
+	{"bible-overview", func(t *testing.T, b *e2e.Browser) {
+		if err := b.Navigate(helpers.BaseURL + "/bible/" + helpers.PlainBible(t) + "/"); err != nil {
+			t.Fatalf("Failed to navigate to Bible overview: %v", err)
+		}
+	}},
+	{"book", func(t *testing.T, b *e2e.Browser) {
+		if err := b.Navigate(helpers.BaseURL + "/bible/" + helpers.PlainBible(t) + "/gen/"); err != nil {
+			t.Fatalf("Failed to navigate to book page: %v", err)
+		}
+	}},
+	{"single", func(t *testing.T, b *e2e.Browser) {
+		helpers.OpenSingleChapter(t, b, helpers.StrongsBible(t), "Gen", 1)
+	}},
+	{"compare", setupCompareScene},
+	{"sss", setupSSSScene},
+	{"search", setupSearchScene},
+}
+
+// TestAccessibilityAudit runs the WCAG 2.2 AA rule set on every page type at
+// desktop and mobile sizes. Each run writes a11y-<page>.json to tests/artifacts.
+func TestAccessibilityAudit(t *testing.T) {
+	for _, vp := range visualViewports {
+		for _, page := range a11yPages {
+			t.Run(page.name+"-"+vp.name, func(t *testing.T) {
+				b := vp.open(t)
+				page.setup(t, b)
+				audit.Check(t, b, page.name, audit.Options{})
+			})
+		}
+	}
+}
+
+// reflowScript reports how far the page overflows the viewport horizontally.
+const reflowScript = `document.documentElement.scrollWidth - document.documentElement.clientWidth`
+
+// TestReflow tests that every page type fits the viewport width on every
+// device without horizontal scrolling (WCAG 1.4.10).
+func TestReflow(t *testing.T) {
+	for _, page := range a11yPages {
+		t.Run(page.name, func(t *testing.T) {
+			helpers.RunOnDevices(t, func(t *testing.T, b *e2e.Browser, d helpers.Device) {
+				page.setup(t, b)
+				overflow, err := b.Evaluate(reflowScript)
+				if err != nil {
+					t.Fatalf("Failed to measure page width: %v", err)
+				}
+				if px, ok := overflow.(float64); ok && px > 0 {
+					t.Errorf("Page scrolls %vpx horizontally at %dpx wide", px, d.Width)
+				}
+			})
+		})
+	}
+}
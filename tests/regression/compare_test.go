@@ -2,8 +2,8 @@
 package regression
 
 import (
+	"github.com/JuniperBible/magellan/pkg/e2e"
 	"testing"
-	"time"
 
 	"michael-tests/helpers"
 )
@@ -11,201 +11,131 @@ import (
 // TestComparePageLoads tests that the compare page loads with populated dropdowns.
 func TestComparePageLoads(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
+	page := helpers.OpenComparePage(t, b)
 
-	// Verify book select exists and has options (not just the placeholder)
-	helpers.ExpectOptionCount(t, b, "#book-select", 2) // At least placeholder + 1 book
+	// Verify book select has options besides the placeholder
+	if n := page.BookCount(); n < 1 {
+		t.Errorf("Book select has %d books, expected at least 1", n)
+	}
 
 	// Verify translation checkboxes exist
-	checkbox := b.Find(".translation-checkbox")
-	helpers.Assert(t, checkbox.ShouldExist())
+	helpers.Assert(t, page.TranslationCheckbox("").ShouldExist())
 }
 
 // TestCompareSelectTranslations tests selecting translations via checkboxes.
 func TestCompareSelectTranslations(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
-
-	// Check first translation checkbox (ASV is first in the list)
-	helpers.CheckCheckbox(t, b, ".translation-checkbox[value='asv']")
-
-	// Check second translation checkbox (DRC)
-	helpers.CheckCheckbox(t, b, ".translation-checkbox[value='drc']")
+	page := helpers.OpenComparePage(t, b)
 
-	// Select a book
-	helpers.SelectOption(t, b, "#book-select", "Gen")
-
-	// Wait for chapter select to be enabled
-	if err := b.WaitForEnabled("#chapter-select"); err != nil {
-		t.Fatalf("Chapter select did not enable: %v", err)
+	page.SelectTranslations(helpers.PlainBible(t), helpers.CatholicBible(t))
+	ch := page.OpenChapter("Gen", 1)
+	if ch.Mode != "compare" {
+		t.Errorf("Loaded mode %q, expected compare", ch.Mode)
 	}
 
-	// Select chapter 1
-	helpers.SelectOption(t, b, "#chapter-select", "1")
-
-	// Wait for comparison content to load
-	time.Sleep(500 * time.Millisecond)
-
-	// Verify parallel content has loaded
-	if err := b.WaitFor("#parallel-content .verse"); err != nil {
-		t.Logf("Parallel content may have different structure: %v", err)
+	// Verify parallel content has rendered
+	if page.VerseCount() == 0 {
+		t.Error("Parallel content has no verses after the chapter loaded")
 	}
 }
 
-// TestCompareToggleSSSMode tests toggling SSS (Side-by-Side) mode.
+// TestCompareToggleSSSMode tests toggling SSS (Side-by-Side) mode on every device.
 func TestCompareToggleSSSMode(t *testing.T) {
-	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
-
-	// Click SSS toggle button
-	sssToggle := b.Find("#sss-mode-btn")
-	if !sssToggle.Exists() {
-		t.Fatal("SSS mode button not found")
-	}
-
-	if err := sssToggle.Click(); err != nil {
-		t.Fatalf("Failed to click SSS toggle: %v", err)
-	}
-
-	// Wait for SSS mode to activate (normal mode hidden, SSS mode visible)
-	time.Sleep(300 * time.Millisecond)
+	helpers.RunOnDevices(t, func(t *testing.T, b *e2e.Browser, _ helpers.Device) {
+		page := helpers.OpenComparePage(t, b)
 
-	// Verify SSS mode is visible
-	helpers.ExpectVisible(t, b, "#sss-mode")
+		if !page.SSSButton().Exists() {
+			t.Fatal("SSS mode button not found")
+		}
+		sss := page.EnterSSS()
 
-	// Verify SSS Bible selectors are visible
-	helpers.ExpectVisible(t, b, "#sss-bible-left")
-	helpers.ExpectVisible(t, b, "#sss-bible-right")
+		// Verify SSS Bible selectors are visible
+		helpers.Assert(t, sss.LeftBibleSelect().ShouldBeVisible())
+		helpers.Assert(t, sss.RightBibleSelect().ShouldBeVisible())
 
-	// Verify normal mode is hidden
-	normalMode := b.Find("#normal-mode")
-	if normalMode.Visible() {
-		t.Error("Normal mode should be hidden in SSS mode")
-	}
+		// Verify normal mode is hidden
+		if page.NormalMode().Visible() {
+			t.Error("Normal mode should be hidden in SSS mode")
+		}
+	})
 }
 
 // TestCompareSSSModeSelection tests selecting Bibles in SSS mode.
 func TestCompareSSSModeSelection(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
-
-	// Enter SSS mode
-	helpers.WaitAndClick(t, b, "#sss-mode-btn")
-	time.Sleep(300 * time.Millisecond)
+	sss := helpers.OpenComparePage(t, b).EnterSSS()
 
 	// Verify SSS Bible selects have options
-	helpers.ExpectOptionCount(t, b, "#sss-bible-left", 2)
-	helpers.ExpectOptionCount(t, b, "#sss-bible-right", 2)
-
-	// Select left Bible (DRC)
-	helpers.SelectOption(t, b, "#sss-bible-left", "drc")
+	if left, right := sss.BibleCounts(); left < 1 || right < 1 {
+		t.Errorf("SSS Bible selects have %d and %d Bibles, expected at least 1 each", left, right)
+	}
 
-	// Select right Bible (ASV)
-	helpers.SelectOption(t, b, "#sss-bible-right", "asv")
+	sss.SelectBibles(helpers.CatholicBible(t), helpers.PlainBible(t))
 
 	// Verify SSS book select has options
-	helpers.ExpectOptionCount(t, b, "#sss-book-select", 2)
-
-	// Select book
-	helpers.SelectOption(t, b, "#sss-book-select", "Gen")
-
-	// Wait for chapter select to be enabled
-	if err := b.WaitForEnabled("#sss-chapter-select"); err != nil {
-		t.Fatalf("SSS chapter select did not enable: %v", err)
+	if n := sss.BookCount(); n < 1 {
+		t.Errorf("SSS book select has %d books, expected at least 1", n)
 	}
 
-	// Select chapter
-	helpers.SelectOption(t, b, "#sss-chapter-select", "1")
-
-	// Wait for panes to load
-	time.Sleep(500 * time.Millisecond)
+	if ch := sss.OpenChapter("Gen", 1); ch.Mode != "sss" {
+		t.Errorf("Loaded mode %q, expected sss", ch.Mode)
+	}
 
 	// Verify both panes have content
-	leftPane := b.Find("#sss-left-pane")
-	rightPane := b.Find("#sss-right-pane")
-	helpers.Assert(t, leftPane.ShouldExist())
-	helpers.Assert(t, rightPane.ShouldExist())
+	if left, right := sss.PaneVerseCounts(); left == 0 || right == 0 {
+		t.Errorf("SSS panes have %d and %d verses, expected both to have content", left, right)
+	}
 }
 
 // TestCompareChapterNavigation tests navigating to different chapters.
 func TestCompareChapterNavigation(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
-
-	// Check a translation
-	helpers.CheckCheckbox(t, b, ".translation-checkbox[value='asv']")
+	page := helpers.OpenComparePage(t, b)
 
-	// Select Genesis
-	helpers.SelectOption(t, b, "#book-select", "Gen")
-
-	// Wait for chapter select to be enabled
-	if err := b.WaitForEnabled("#chapter-select"); err != nil {
-		t.Fatalf("Chapter select did not enable: %v", err)
-	}
+	page.SelectTranslations(helpers.PlainBible(t))
+	page.OpenChapter("Gen", 1)
 
 	// Verify chapter select has options (Genesis has 50 chapters)
-	helpers.ExpectOptionCount(t, b, "#chapter-select", 50)
+	if n := page.ChapterCount(); n != 50 {
+		t.Errorf("Genesis has %d chapters, expected 50", n)
+	}
 
 	// Select chapter 2
-	helpers.SelectOption(t, b, "#chapter-select", "2")
-
-	// Wait for content to load
-	time.Sleep(500 * time.Millisecond)
+	if ch := page.OpenChapter("Gen", 2); ch.Chapter != 2 {
+		t.Errorf("Loaded chapter %d, expected 2", ch.Chapter)
+	}
 
 	// Change to a different book (Psalms has 150 chapters)
-	helpers.SelectOption(t, b, "#book-select", "Ps")
-
-	// Wait for chapter dropdown to update
-	time.Sleep(300 * time.Millisecond)
-
-	// Verify chapter count updated
-	helpers.ExpectOptionCount(t, b, "#chapter-select", 150)
+	page.OpenChapter("Ps", 1)
+	if n := page.ChapterCount(); n != 150 {
+		t.Errorf("Psalms has %d chapters, expected 150", n)
+	}
 }
 
 // TestCompareVerseGridSelection tests using the verse grid to select a specific verse.
 func TestCompareVerseGridSelection(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
-
-	// Select a translation and passage first
-	helpers.CheckCheckbox(t, b, ".translation-checkbox[value='asv']")
-	helpers.SelectOption(t, b, "#book-select", "Gen")
-
-	if err := b.WaitForEnabled("#chapter-select"); err != nil {
-		t.Fatalf("Chapter select did not enable: %v", err)
-	}
-	helpers.SelectOption(t, b, "#chapter-select", "1")
-
-	// Wait for verse grid to populate
-	time.Sleep(500 * time.Millisecond)
+	page := helpers.OpenComparePage(t, b)
 
-	// Look for verse buttons in the verse grid
-	verseBtn := b.Find("#verse-buttons .verse-btn")
-	if !verseBtn.Exists() {
-		t.Skip("Verse buttons not found - verse grid may not be populated yet")
-	}
+	page.SelectTranslations(helpers.PlainBible(t))
+	page.OpenChapter("Gen", 1)
 
-	// Click a verse button
-	if err := verseBtn.Click(); err != nil {
-		t.Fatalf("Failed to click verse button: %v", err)
+	// Select verse 3 from the verse grid
+	page.SelectVerse(3)
+	if !page.IsVerseSelected(3) {
+		t.Error("Verse 3 button is not marked as selected")
 	}
-
-	time.Sleep(200 * time.Millisecond)
-
-	// Verify verse is selected (should have selected class)
-	selectedBtn := b.Find("#verse-buttons .verse-btn.selected")
-	if selectedBtn.Exists() {
-		t.Log("Verse button shows selected state")
+	if page.IsVerseSelected(1) {
+		t.Error("Verse 1 button is still marked as selected")
 	}
 }
 
 // TestCompareHighlightToggle tests the highlight/diff toggle.
 func TestCompareHighlightToggle(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
+	page := helpers.OpenComparePage(t, b)
 
-	// Find highlight toggle checkbox
-	highlightToggle := b.Find("#highlight-toggle")
+	highlightToggle := page.HighlightToggle()
 	if !highlightToggle.Exists() {
 		t.Skip("Highlight toggle not found")
 	}
@@ -219,8 +149,6 @@ func TestCompareHighlightToggle(t *testing.T) {
 		t.Fatalf("Failed to click highlight toggle: %v", err)
 	}
 
-	time.Sleep(100 * time.Millisecond)
-
 	// Verify state changed
 	newChecked, _ := highlightToggle.IsChecked()
 	if newChecked == checked {
@@ -231,10 +159,9 @@ func TestCompareHighlightToggle(t *testing.T) {
 // TestCompareColorPicker tests the highlight color picker.
 func TestCompareColorPicker(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
+	page := helpers.OpenComparePage(t, b)
 
-	// Find color picker button
-	colorBtn := b.Find("#highlight-color-btn")
+	colorBtn := page.ColorButton()
 	if !colorBtn.Exists() {
 		t.Skip("Color picker button not found")
 	}
@@ -244,19 +171,13 @@ func TestCompareColorPicker(t *testing.T) {
 		t.Fatalf("Failed to click color button: %v", err)
 	}
 
-	time.Sleep(200 * time.Millisecond)
-
 	// Verify color picker is visible
-	colorPicker := b.Find("#highlight-color-picker")
-	if !colorPicker.Exists() || !colorPicker.Visible() {
-		t.Error("Color picker did not open")
-	}
+	helpers.Assert(t, page.ColorPicker().ShouldBeVisible())
 
 	// Click a color option
-	colorOption := b.Find("#highlight-color-picker .color-option")
-	if colorOption.Exists() {
+	if colorOption := page.ColorOption(); colorOption.Exists() {
 		if err := colorOption.Click(); err != nil {
-			t.Logf("Failed to click color option: %v", err)
+			t.Errorf("Failed to click color option: %v", err)
 		}
 	}
 }
@@ -264,33 +185,15 @@ func TestCompareColorPicker(t *testing.T) {
 // TestCompareExitSSSMode tests exiting SSS mode back to normal mode.
 func TestCompareExitSSSMode(t *testing.T) {
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToCompare(t, b)
+	sss := helpers.OpenComparePage(t, b).EnterSSS()
 
-	// Enter SSS mode
-	helpers.WaitAndClick(t, b, "#sss-mode-btn")
-	time.Sleep(300 * time.Millisecond)
-
-	// Verify we're in SSS mode
-	helpers.ExpectVisible(t, b, "#sss-mode")
-
-	// Click back button to exit SSS mode
-	backBtn := b.Find("#sss-back-btn")
-	if !backBtn.Exists() {
+	if !sss.BackButton().Exists() {
 		t.Fatal("SSS back button not found")
 	}
-
-	if err := backBtn.Click(); err != nil {
-		t.Fatalf("Failed to click back button: %v", err)
-	}
-
-	time.Sleep(300 * time.Millisecond)
-
-	// Verify we're back in normal mode
-	helpers.ExpectVisible(t, b, "#normal-mode")
+	sss.Exit()
 
 	// Verify SSS mode is hidden
-	sssMode := b.Find("#sss-mode")
-	if sssMode.Visible() {
+	if sss.Root().Visible() {
 		t.Error("SSS mode should be hidden after exiting")
 	}
 }
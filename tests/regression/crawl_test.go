@@ -0,0 +1,60 @@
+package regression
+
+import (
+	"testing"
+
+	"michael-tests/helpers"
+)
+
+// =============================================================================
+// CHAPTER CRAWL TESTS
+// =============================================================================
+
+// TestChapterCrawl tests generated pages against the data they are built
+// from. It reads bibles.json and bibles_auxiliary, then fetches each Bible's
+// book pages and a random sample of its chapter pages (-crawl, -crawl-seed).
+// Verse counts, numbers and text must match the source. Chapters the
+// content manifests find without text must have no page. Skipped chapters are listed in
+// tests/artifacts/TestChapterCrawl/skipped.txt.
+func TestChapterCrawl(t *testing.T) {
+	routes := helpers.PlanRoutes(helpers.SourceData(t), helpers.SourceManifests(t))
+	if len(routes.Chapters) == 0 {
+		t.Fatal("Source data generates no chapters")
+	}
+	routes.ReportSkipped(t)
+
+	chapters := map[string][]helpers.ChapterRoute{}
+	for _, ch := range helpers.SampleChapters(t, routes.Chapters) {
+		chapters[ch.Bible] = append(chapters[ch.Bible], ch)
+	}
+	books := map[string][]helpers.BookRoute{}
+	for _, book := range routes.Books {
+		books[book.Bible] = append(books[book.Bible], book)
+	}
+	skipped := map[string][]helpers.ChapterRoute{}
+	for _, ch := range routes.Skipped {
+		skipped[ch.Bible] = append(skipped[ch.Bible], ch)
+	}
+
+	for bible := range books {
+		t.Run(bible, func(t *testing.T) {
+			helpers.Parallel(t)
+			b := helpers.NewTestBrowser(t)
+			helpers.NavigateToBiblesList(t, b)
+
+			for _, book := range books[bible] {
+				for _, p := range helpers.CheckBook(t, b, book) {
+					t.Error(p)
+				}
+			}
+			for _, ch := range chapters[bible] {
+				for _, p := range helpers.CheckChapter(t, b, ch) {
+					t.Error(p)
+				}
+			}
+			for _, ch := range skipped[bible] {
+				helpers.ExpectNotGenerated(t, b, ch)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package regression
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+	"michael-tests/helpers/selectors"
+)
+
+// These tests make the test server misbehave for chapter and Bible pages and
+// check how the service worker, OfflineManager and BibleAPI cope. They skip
+// when running against an external BASE_URL.
+
+// chapterPattern matches every chapter page of a Bible.
+func chapterPattern(bible string) string {
+	return fmt.Sprintf("/bible/%s/*/*/", bible)
+}
+
+// openDownloads opens the offline settings with an active service worker and
+// returns them with the first Bible offered for download.
+func openDownloads(t *testing.T, b *e2e.Browser) (*helpers.OfflineSettings, string) {
+	t.Helper()
+	settings := helpers.OpenOfflineSettings(t, b)
+	helpers.WaitForServiceWorker(t, b)
+
+	bible := settings.FirstBible()
+	if bible == "" {
+		t.Fatal("No Bibles offered for download")
+	}
+	return settings, bible
+}
+
+// =============================================================================
+// DOWNLOAD FAULT TESTS
+// =============================================================================
+
+// TestDownloadReportsFailedChapters tests that chapters the server fails are
+// counted by the download and left out of the Bible's cache status.
+func TestDownloadReportsFailedChapters(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	settings, bible := openDownloads(t, b)
+
+	failed := fmt.Sprintf("/bible/%s/*/1/", bible)
+	faults := helpers.InjectFaults(t, helpers.Fault{Pattern: failed, Status: 503})
+
+	res := settings.Download(bible)
+	if !res.Success {
+		t.Fatalf("Download of %s failed outright: %s", bible, res.Error)
+	}
+	if faults.Hits() == 0 {
+		t.Fatal("No chapter request was faulted")
+	}
+	if res.FailedCount != faults.Hits() {
+		t.Errorf("Download reported %d failures, expected %d", res.FailedCount, faults.Hits())
+	}
+
+	for _, url := range helpers.OpenCacheStorage(t, b).URLs(helpers.ChaptersCachePrefix) {
+		if ok, _ := path.Match(failed, url); ok {
+			t.Errorf("Failed chapter %s is cached", url)
+		}
+	}
+
+	status := settings.CacheStatus(bible)
+	if status.IsFullyCached {
+		t.Error("Bible reported fully cached with failed chapters")
+	}
+	if got := status.CachedChapters + res.FailedCount; got != status.TotalChapters {
+		t.Errorf("%d cached + %d failed chapters, expected %d in total",
+			status.CachedChapters, res.FailedCount, status.TotalChapters)
+	}
+}
+
+// TestDownloadResumesAfterTransientFailure tests that downloading again after
+// a chapter failed once fills in the missing chapter. A one-off reset would
+// not do here: Chrome retries a reset on a reused connection by itself.
+func TestDownloadResumesAfterTransientFailure(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	settings, bible := openDownloads(t, b)
+
+	helpers.InjectFaults(t, helpers.Fault{Pattern: chapterPattern(bible), Status: 503, Times: 1})
+
+	if res := settings.Download(bible); res.FailedCount != 1 {
+		t.Fatalf("First download reported %d failures, expected 1", res.FailedCount)
+	}
+	if settings.CacheStatus(bible).IsFullyCached {
+		t.Fatal("Bible reported fully cached after a chapter failed")
+	}
+
+	if res := settings.Download(bible); res.FailedCount != 0 {
+		t.Fatalf("Second download reported %d failures, expected none", res.FailedCount)
+	}
+	if status := settings.CacheStatus(bible); !status.IsFullyCached {
+		t.Errorf("Bible not fully cached after resuming: %d of %d chapters",
+			status.CachedChapters, status.TotalChapters)
+	}
+}
+
+// TestDownloadFailsWithoutBibleOverview tests that a download whose Bible
+// page fails is reported as failed and caches nothing.
+func TestDownloadFailsWithoutBibleOverview(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	settings, bible := openDownloads(t, b)
+
+	helpers.InjectFaults(t, helpers.Fault{Pattern: fmt.Sprintf("/bible/%s/", bible), Status: 500})
+
+	settings.SelectBibles(bible)
+	if err := settings.DownloadButton().Click(); err != nil {
+		t.Fatalf("Failed to start download: %v", err)
+	}
+	if err := b.WaitForText(fmt.Sprintf(selectors.IDStatusFmt, bible), "Failed"); err != nil {
+		t.Fatalf("Download of %s was not reported as failed: %v", bible, err)
+	}
+
+	if status := settings.CacheStatus(bible); status.HasBibleOverview || status.CachedChapters > 0 {
+		t.Errorf("Failed download left cache entries: %+v", status)
+	}
+}
+
+// TestDownloadCancelledDuringSlowChapters tests cancelling a download while
+// chapter requests are stalled.
+func TestDownloadCancelledDuringSlowChapters(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	settings, bible := openDownloads(t, b)
+
+	helpers.InjectFaults(t, helpers.Fault{Pattern: chapterPattern(bible), Latency: 2 * time.Second})
+
+	settings.StartDownload(bible)
+	if res := settings.Cancel(bible); !res.Cancelled {
+		t.Errorf("Cancellation not reported: %+v", res)
+	}
+	if settings.CacheStatus(bible).IsFullyCached {
+		t.Error("Bible reported fully cached after cancelling")
+	}
+}
+
+// TestDownloadCompletesOnSlowNetwork tests that throttled chapter responses
+// still download completely.
+func TestDownloadCompletesOnSlowNetwork(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	settings, bible := openDownloads(t, b)
+
+	faults := helpers.InjectFaults(t, helpers.Fault{
+		Pattern:        chapterPattern(bible),
+		BytesPerSecond: 256 << 10,
+		Times:          3,
+	})
+
+	res := settings.Download(bible)
+	if faults.Hits() == 0 {
+		t.Fatal("No chapter request was throttled")
+	}
+	if res.FailedCount != 0 {
+		t.Errorf("Throttled download reported %d failures", res.FailedCount)
+	}
+	if !settings.CacheStatus(bible).IsFullyCached {
+		t.Error("Bible not fully cached after a throttled download")
+	}
+}
+
+// =============================================================================
+// CHAPTER FETCH FAULT TESTS
+// =============================================================================
+
+// TestChapterFallsBackToCacheOnReset tests that the service worker serves a
+// cached chapter when the network drops the connection.
+func TestChapterFallsBackToCacheOnReset(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	page := helpers.OpenSingleChapter(t, b, helpers.PlainBible(t), "Gen", 1)
+	chapter := "/bible/" + page.Bible + "/gen/1/"
+	helpers.WaitForServiceWorker(t, b)
+
+	// Reload through the service worker so it caches the page
+	if err := b.Reload(); err != nil {
+		t.Fatalf("Failed to reload page: %v", err)
+	}
+	helpers.WaitForCachedURL(t, b, "michael-chapters-", chapter)
+
+	faults := helpers.InjectFaults(t, helpers.Fault{Pattern: chapter, Reset: true})
+	if err := b.Reload(); err != nil {
+		t.Fatalf("Failed to reload page: %v", err)
+	}
+	if err := page.WaitForVerses(); err != nil {
+		t.Fatalf("Cached chapter did not load after a connection reset: %v", err)
+	}
+	if faults.Hits() == 0 {
+		t.Error("Chapter was not requested from the network")
+	}
+}
+
+// TestUncachedChapterReportsNetworkError tests the service worker's response
+// for a chapter that is neither reachable nor cached.
+func TestUncachedChapterReportsNetworkError(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	page := helpers.OpenSingleChapter(t, b, helpers.PlainBible(t), "Gen", 1)
+	helpers.WaitForServiceWorker(t, b)
+
+	chapter := "/bible/" + page.Bible + "/gen/2/"
+	helpers.InjectFaults(t, helpers.Fault{Pattern: chapter, Reset: true})
+	if err := b.Navigate(helpers.BaseURL + chapter); err != nil {
+		t.Fatalf("Failed to navigate: %v", err)
+	}
+
+	text, err := b.Find("body").Text()
+	if err != nil {
+		t.Fatalf("Failed to read page: %v", err)
+	}
+	if !strings.Contains(text, "no cache available") {
+		t.Errorf("Expected the service worker's network error, got %q", text)
+	}
+}
+
+// TestCompareChapterServerError tests that the compare page reports a
+// chapter the server fails to deliver.
+func TestCompareChapterServerError(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	bible := helpers.PlainBible(t)
+	helpers.InjectFaults(t, helpers.Fault{Pattern: "/bible/" + bible + "/gen/1/", Status: 500})
+
+	page := helpers.OpenComparePage(t, b)
+	page.SelectTranslations(bible)
+	page.OpenChapter("Gen", 1)
+
+	if n := page.VerseCount(); n != 0 {
+		t.Errorf("Rendered %d verses from a failed chapter", n)
+	}
+	text, _ := page.Content().Text()
+	if !strings.Contains(text, "No verses found") {
+		t.Errorf("Expected the no-verses message, got %q", text)
+	}
+}
+
+// TestCompareChapterTruncated tests that a truncated chapter only blanks out
+// its own translation in a comparison.
+func TestCompareChapterTruncated(t *testing.T) {
+	b := helpers.NewTestBrowser(t)
+	bible := helpers.PlainBible(t)
+	faults := helpers.InjectFaults(t, helpers.Fault{Pattern: "/bible/" + bible + "/gen/1/", TruncateAfter: 512})
+
+	page := helpers.OpenComparePage(t, b)
+	page.SelectTranslations(bible, helpers.CatholicBible(t))
+	page.OpenChapter("Gen", 1)
+
+	if faults.Hits() == 0 {
+		t.Fatal("Chapter request was not truncated")
+	}
+	if page.VerseCount() == 0 {
+		t.Fatal("No verses rendered from the intact translation")
+	}
+	text, _ := page.Content().Text()
+	if !strings.Contains(text, "Verse not available") {
+		t.Error("Truncated translation not marked as unavailable")
+	}
+}
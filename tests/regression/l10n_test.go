@@ -0,0 +1,125 @@
+package regression
+
+import (
+	"os"
+	"sort"
+	"testing"
+
+	"michael-tests/fixtures"
+	"michael-tests/helpers"
+)
+
+// l10nPages are the main pages visited in every language, by path below the
+// language prefix.
+var l10nPages = []struct {
+	name string
+	path string
+}{
+	{"home", "/"},
+	{"bible-list", "/bible/"},
+	{"compare", "/bible/compare/"},
+	{"search", "/bible/search/"},
+}
+
+// l10nDevices are the widths nav controls must fit at in every language.
+var l10nDevices = []helpers.Device{helpers.Desktop, helpers.IPhoneSE}
+
+// =============================================================================
+// TRANSLATION FILE TESTS
+// =============================================================================
+
+// TestLocalizationStrings tests that every language in i18n/ translates every
+// string English has and every string the layouts look up.
+func TestLocalizationStrings(t *testing.T) {
+	langs := helpers.LoadUILanguages(t)
+	en := langs[0]
+
+	ids := map[string]bool{}
+	for id, templates := range helpers.TemplateI18nIDs(t) {
+		if _, ok := en.Strings[id]; !ok {
+			t.Errorf("i18n/en.toml has no %q, used by %v", id, templates)
+		}
+		ids[id] = true
+	}
+	for id := range en.Strings {
+		ids[id] = true
+	}
+
+	for _, lang := range langs[1:] {
+		var missing []string
+		for id := range ids {
+			if _, ok := lang.Strings[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		sort.Strings(missing)
+		if len(missing) > 0 {
+			t.Errorf("i18n/%s.toml lacks %d strings: %v", lang.Code, len(missing), missing)
+		}
+	}
+}
+
+// =============================================================================
+// PAGE SWEEP TESTS
+// =============================================================================
+
+// TestLocalizationSweep tests the main pages in every language on a site
+// built with all of them and the fixture Bibles: lang and dir attributes,
+// template placeholders and English fallbacks in the rendered text, and nav
+// controls that overflow or clip their labels.
+func TestLocalizationSweep(t *testing.T) {
+	langs := helpers.LoadUILanguages(t)
+	en := langs[0]
+
+	dataDir, err := fixtures.Default().WriteTemp()
+	if err != nil {
+		t.Fatalf("Failed to write fixtures: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dataDir) })
+
+	codes := make([]string, len(langs))
+	for i, lang := range langs {
+		codes[i] = lang.Code
+	}
+	srv, err := helpers.StartServer(helpers.ServerOptions{DataDir: dataDir, Languages: codes})
+	if err != nil {
+		t.Fatalf("Failed to start multilingual server: %v", err)
+	}
+	t.Cleanup(srv.Close)
+
+	for _, lang := range langs {
+		t.Run(lang.Code, func(t *testing.T) {
+			helpers.Parallel(t)
+			for _, d := range l10nDevices {
+				b := helpers.NewDeviceBrowser(t, d)
+				for _, page := range l10nPages {
+					url := srv.URL + lang.Prefix() + page.path
+					if err := b.Navigate(url); err != nil {
+						t.Errorf("Failed to navigate to %s: %v", url, err)
+						continue
+					}
+					report := helpers.InspectLocalization(t, b, lang, en)
+
+					// Attributes and text do not depend on the device
+					if d == l10nDevices[0] {
+						if report.Lang != lang.Code {
+							t.Errorf("%s: expected lang=%q, got %q", page.name, lang.Code, report.Lang)
+						}
+						if report.Dir != lang.Dir() {
+							t.Errorf("%s: expected dir=%q, got %q", page.name, lang.Dir(), report.Dir)
+						}
+						for _, p := range report.Placeholders {
+							t.Errorf("%s: placeholder leak in %s", page.name, p)
+						}
+						for _, u := range report.Untranslated {
+							t.Errorf("%s: untranslated English in %s", page.name, u)
+						}
+					}
+					for _, o := range report.Overflow {
+						t.Errorf("%s on %s: %s", page.name, d.Name, o)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package regression
+
+import (
+	"os"
+	"testing"
+
+	"michael-tests/helpers"
+)
+
+// TestMain builds the site and serves it on a random port for the whole package.
+func TestMain(m *testing.M) {
+	os.Exit(helpers.RunTests(m))
+}
@@ -0,0 +1,61 @@
+package regression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+)
+
+// perfSamples is how many cold loads of each page are measured; budgets are
+// checked against the median.
+const perfSamples = 3
+
+// perfPages lists the page types with performance budgets in
+// tests/testdata/perf-budgets.json, reusing the visual and audit scenes.
+var perfPages = []struct {
+	name  string
+	setup func(t *testing.T, b *e2e.Browser)
+}{
+	{"bible-list", helpers.NavigateToBiblesList},
+	{"single", setupSingleScene},
+	{"compare", setupCompareScene},
+	{"sss", setupSSSScene},
+	{"search", setupSearchScene},
+}
+
+// TestPerformanceBudgets tests the Web Vitals of every page type against its
+// budget. Each run appends to history.jsonl and writes trend.md in
+// tests/artifacts/TestPerformanceBudgets.
+func TestPerformanceBudgets(t *testing.T) {
+	budgets := helpers.LoadPerfBudgets(t)
+	results := map[string]helpers.Vitals{}
+
+	for _, page := range perfPages {
+		t.Run(page.name, func(t *testing.T) {
+			var samples []helpers.Vitals
+			for i := range perfSamples {
+				t.Run(fmt.Sprint(i+1), func(t *testing.T) {
+					b := helpers.NewTestBrowser(t)
+					vitals := helpers.RecordVitals(t, b)
+					page.setup(t, b)
+					samples = append(samples, vitals.Collect())
+				})
+			}
+			if len(samples) < perfSamples {
+				t.Fatalf("Only %d of %d samples of %s completed", len(samples), perfSamples, page.name)
+			}
+
+			v := helpers.MedianVitals(samples)
+			results[page.name] = v
+			t.Logf("LCP %.0f ms, CLS %.3f, TBT %.0f ms, %d long tasks, JS heap %d KB, %d KB in %d requests",
+				v.LCP, v.CLS, v.TBT, v.LongTasks, v.JSHeap/1024, v.Transfer/1024, v.Requests)
+			helpers.CheckPerfBudget(t, budgets, page.name, v)
+		})
+	}
+
+	if len(results) > 0 {
+		helpers.WritePerfTrend(t, budgets, results)
+	}
+}
@@ -0,0 +1,70 @@
+package regression
+
+import (
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+)
+
+// leaveSiteDataScript stores something in every kind of site storage the
+// pool must wipe between tests.
+const leaveSiteDataScript = `
+(async () => {
+	localStorage.setItem('pool-probe', '1');
+	await caches.open('pool-probe');
+	await new Promise((resolve, reject) => {
+		const req = indexedDB.open('pool-probe', 1);
+		req.onsuccess = () => { req.result.close(); resolve(); };
+		req.onerror = () => reject(req.error);
+	});
+	return true;
+})()
+`
+
+// siteDataScript lists what site storage the page can see.
+const siteDataScript = `
+(async () => ({
+	localStorage: localStorage.length,
+	caches: (await caches.keys()).length,
+	databases: (await indexedDB.databases()).length,
+	serviceWorkers: (await navigator.serviceWorker.getRegistrations()).length
+}))()
+`
+
+// TestBrowserPoolIsolation tests that a browser handed out again by the pool
+// carries no site data from the test that used it before.
+func TestBrowserPoolIsolation(t *testing.T) {
+	var first *e2e.Browser
+	t.Run("leave-data", func(t *testing.T) {
+		first = helpers.NewTestBrowser(t)
+		helpers.NavigateToBiblesList(t, first)
+		helpers.WaitForServiceWorker(t, first)
+		if _, err := first.Evaluate(leaveSiteDataScript); err != nil {
+			t.Fatalf("Failed to store site data: %v", err)
+		}
+	})
+
+	t.Run("reuse", func(t *testing.T) {
+		b := helpers.NewTestBrowser(t)
+		if b != first {
+			t.Skip("Browser was not reused; set MICHAEL_BROWSER_POOL above 0")
+		}
+		if err := b.Navigate(helpers.BaseURL + "/manifest.json"); err != nil {
+			t.Fatalf("Failed to navigate: %v", err)
+		}
+		data, err := b.Evaluate(siteDataScript)
+		if err != nil {
+			t.Fatalf("Failed to read site data: %v", err)
+		}
+		counts, ok := data.(map[string]interface{})
+		if !ok {
+			t.Fatal("Unexpected site data response type")
+		}
+		for kind, n := range counts {
+			if n != float64(0) {
+				t.Errorf("Reused browser has %v %s entries left over", n, kind)
+			}
+		}
+	})
+}
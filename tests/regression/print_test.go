@@ -0,0 +1,131 @@
+package regression
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+	"michael-tests/helpers/selectors"
+)
+
+// printMarks are the elements that must not print, by label. Every scene has
+// the header nav and the install banners; share menus are opened where the
+// scene has them.
+var printMarks = []struct {
+	label    string
+	selector string
+}{
+	{"nav", selectors.ClassChrome},
+	{"install-banner", selectors.IDPwaInstallBanner + ", " + selectors.IDPwaIosInstructions},
+	{"share", selectors.ClassShareMenu + ", " + selectors.ClassShareWrapper},
+}
+
+// printScenes are the pages people print. Each lists the verse elements that
+// must print in order, group after group, a pattern each verse's printed text
+// must start with, and the page count the print must fit in.
+var printScenes = []struct {
+	name     string
+	setup    func(t *testing.T, b *e2e.Browser)
+	verses   []string
+	numbered string
+	minPages int
+	maxPages int
+}{
+	{
+		name:     "single",
+		setup:    setupPrintSingle,
+		verses:   []string{selectors.ClassVerse + "[data-verse]"},
+		numbered: `^\s*%d\s*\D`,
+		minPages: 1,
+		maxPages: 4,
+	},
+	{
+		name:     "compare",
+		setup:    setupCompareScene,
+		verses:   []string{selectors.IDParallelContent + " " + selectors.ClassParallelVerse},
+		numbered: `^\D*1:%d\D`,
+		minPages: 2,
+		maxPages: 12,
+	},
+	{
+		name:  "sss",
+		setup: setupSSSScene,
+		verses: []string{
+			selectors.IDSssLeftPane + " " + selectors.ClassParallelVerse,
+			selectors.IDSssRightPane + " " + selectors.ClassParallelVerse,
+		},
+		numbered: `^\s*%d\s*\D`,
+		minPages: 2,
+		maxPages: 8,
+	},
+}
+
+// setupPrintSingle shows the plain Bible's Genesis 1 with its chapter share menu open.
+func setupPrintSingle(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	helpers.OpenSingleChapter(t, b, helpers.PlainBible(t), "Gen", 1).OpenShareMenu()
+}
+
+// showInstallBanners un-hides the install banners, as pwa-install.js does
+// when the browser offers installation.
+func showInstallBanners(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	_, err := b.Evaluate(fmt.Sprintf(`document.querySelectorAll(%q).forEach((el) => {
+		el.classList.remove('hidden');
+		el.removeAttribute('aria-hidden');
+	})`, selectors.IDPwaInstallBanner+", "+selectors.IDPwaIosInstructions))
+	if err != nil {
+		t.Fatalf("Failed to show install banners: %v", err)
+	}
+}
+
+// =============================================================================
+// PRINT OUTPUT TESTS
+// =============================================================================
+
+// TestPrintOutput tests the text layer of chapter, compare and SSS pages
+// printed to PDF: no navigation, share menus or install banners, every verse
+// with its number and in order, and a bounded page count. The PDFs are kept
+// in tests/artifacts/TestPrintOutput.
+func TestPrintOutput(t *testing.T) {
+	for _, scene := range printScenes {
+		t.Run(scene.name, func(t *testing.T) {
+			b := helpers.NewTestBrowser(t)
+			scene.setup(t, b)
+			showInstallBanners(t, b)
+
+			var marked []string
+			for _, m := range printMarks {
+				if helpers.MarkForPrint(t, b, m.label, m.selector) > 0 {
+					marked = append(marked, m.label)
+				} else if m.label != "share" {
+					t.Errorf("No %s elements (%s) to check on the %s page", m.label, m.selector, scene.name)
+				}
+			}
+
+			var verses []string
+			for _, selector := range scene.verses {
+				texts := helpers.PrintedTexts(t, b, selector)
+				if len(texts) == 0 {
+					t.Fatalf("No verses match %s", selector)
+				}
+				for i, text := range texts {
+					numbered := regexp.MustCompile(fmt.Sprintf(scene.numbered, i+1))
+					if text != "" && !numbered.MatchString(text) {
+						t.Errorf("Verse %d of %s does not print its number first: %.60q", i+1, selector, text)
+					}
+				}
+				verses = append(verses, texts...)
+			}
+
+			doc := helpers.PrintToPDF(t, b, scene.name)
+			for _, label := range marked {
+				doc.ExpectUnmarked(t, label)
+			}
+			doc.ExpectInOrder(t, verses)
+			doc.ExpectPages(t, scene.minPages, scene.maxPages)
+		})
+	}
+}
@@ -2,10 +2,14 @@ package regression
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
-	"time"
 
+	"github.com/JuniperBible/magellan/pkg/e2e"
 	"michael-tests/helpers"
 )
 
@@ -14,7 +18,7 @@ import (
 // =============================================================================
 
 // loadManifest navigates to manifest.json and returns the parsed manifest map.
-func loadManifest(t *testing.T, b *helpers.Browser) map[string]interface{} {
+func loadManifest(t *testing.T, b *e2e.Browser) map[string]interface{} {
 	t.Helper()
 	if err := b.Navigate(helpers.BaseURL + "/manifest.json"); err != nil {
 		t.Fatalf("Failed to navigate to manifest: %v", err)
@@ -41,7 +45,7 @@ func getManifestIcons(t *testing.T, manifest map[string]interface{}) []interface
 }
 
 // checkMetaTag verifies a meta tag exists by name selector and logs the result.
-func checkMetaTag(t *testing.T, b *helpers.Browser, selector, label string) {
+func checkMetaTag(t *testing.T, b *e2e.Browser, selector, label string) {
 	t.Helper()
 	el := b.Find(selector)
 	if !el.Exists() {
@@ -50,7 +54,7 @@ func checkMetaTag(t *testing.T, b *helpers.Browser, selector, label string) {
 }
 
 // checkLinkTag verifies a link tag exists by selector and logs the result.
-func checkLinkTag(t *testing.T, b *helpers.Browser, selector, label string) {
+func checkLinkTag(t *testing.T, b *e2e.Browser, selector, label string) {
 	t.Helper()
 	el := b.Find(selector)
 	if !el.Exists() {
@@ -64,6 +68,7 @@ func checkLinkTag(t *testing.T, b *helpers.Browser, selector, label string) {
 
 // TestManifestExists verifies that the manifest.json file is accessible.
 func TestManifestExists(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/manifest.json"); err != nil {
@@ -122,6 +127,7 @@ func checkManifestIconCount(t *testing.T, manifest map[string]interface{}, minCo
 
 // TestManifestRequiredFields verifies all required PWA manifest fields are present.
 func TestManifestRequiredFields(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	manifest := loadManifest(t, b)
 
@@ -157,6 +163,7 @@ func collectIconSizes(icons []interface{}, required map[string]bool) {
 
 // TestManifestIconSizes verifies icons have required sizes (192x192 and 512x512).
 func TestManifestIconSizes(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	manifest := loadManifest(t, b)
 	icons := getManifestIcons(t, manifest)
@@ -195,6 +202,7 @@ func iconListHasMaskable(icons []interface{}) bool {
 
 // TestManifestMaskableIcon verifies a maskable icon is present for Android.
 func TestManifestMaskableIcon(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	manifest := loadManifest(t, b)
 	icons := getManifestIcons(t, manifest)
@@ -212,6 +220,7 @@ func TestManifestMaskableIcon(t *testing.T) {
 
 // TestPWAIconsAccessible verifies all icon files return HTTP 200.
 func TestPWAIconsAccessible(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	icons := []string{
@@ -237,6 +246,7 @@ func TestPWAIconsAccessible(t *testing.T) {
 
 // TestSVGLogoExists verifies the SVG logo file exists.
 func TestSVGLogoExists(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/icons/logo.svg"); err != nil {
@@ -260,7 +270,7 @@ func TestSVGLogoExists(t *testing.T) {
 // =============================================================================
 
 // checkManifestLink verifies the manifest link tag points to /manifest.json.
-func checkManifestLink(t *testing.T, b *helpers.Browser) {
+func checkManifestLink(t *testing.T, b *e2e.Browser) {
 	t.Helper()
 	manifestLink := b.Find("link[rel='manifest']")
 	if !manifestLink.Exists() {
@@ -275,7 +285,7 @@ func checkManifestLink(t *testing.T, b *helpers.Browser) {
 }
 
 // checkThemeColorMeta verifies theme-color meta tag exists and logs its value.
-func checkThemeColorMeta(t *testing.T, b *helpers.Browser) {
+func checkThemeColorMeta(t *testing.T, b *e2e.Browser) {
 	t.Helper()
 	themeColor := b.Find("meta[name='theme-color']")
 	if !themeColor.Exists() {
@@ -288,6 +298,7 @@ func checkThemeColorMeta(t *testing.T, b *helpers.Browser) {
 
 // TestPWAMetaTags verifies all PWA meta tags are present in the HTML head.
 func TestPWAMetaTags(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -309,6 +320,7 @@ func TestPWAMetaTags(t *testing.T) {
 
 // TestServiceWorkerExists verifies the service worker file is accessible.
 func TestServiceWorkerExists(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/sw.js"); err != nil {
@@ -338,6 +350,7 @@ func TestServiceWorkerExists(t *testing.T) {
 
 // TestServiceWorkerHasFingerprintedCSS verifies SW includes fingerprinted CSS path.
 func TestServiceWorkerHasFingerprintedCSS(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/sw.js"); err != nil {
@@ -364,11 +377,10 @@ func TestServiceWorkerHasFingerprintedCSS(t *testing.T) {
 
 // TestServiceWorkerRegistration verifies SW registers successfully on page load.
 func TestServiceWorkerRegistration(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
-
-	// Wait for SW registration
-	time.Sleep(2 * time.Second)
+	helpers.WaitForServiceWorker(t, b)
 
 	// Check if service worker is registered via JavaScript
 	registered, err := b.Evaluate(`
@@ -392,11 +404,10 @@ func TestServiceWorkerRegistration(t *testing.T) {
 
 // TestServiceWorkerActivation verifies SW activates and claims clients.
 func TestServiceWorkerActivation(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
-
-	// Wait for SW to activate
-	time.Sleep(3 * time.Second)
+	helpers.WaitForServiceWorker(t, b)
 
 	// Check SW state
 	state, err := b.Evaluate(`
@@ -421,30 +432,64 @@ func TestServiceWorkerActivation(t *testing.T) {
 	}
 }
 
-// TestServiceWorkerPrecache verifies SW pre-caches shell assets on install.
+// servedBySite reports whether the site serves path, for pre-cache entries
+// that only exist in some builds, such as chapters of a vendored Bible.
+func servedBySite(t *testing.T, path string) bool {
+	t.Helper()
+	resp, err := http.Get(helpers.BaseURL + path)
+	if err != nil {
+		t.Fatalf("Failed to fetch %s: %v", path, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// TestServiceWorkerPrecache verifies SW pre-caches every SHELL_ASSETS and
+// DEFAULT_CHAPTERS entry on install.
 func TestServiceWorkerPrecache(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	// Wait for SW to install and cache
-	time.Sleep(3 * time.Second)
+	// Install pre-caches the shell and default chapters before activation
+	helpers.WaitForServiceWorker(t, b)
 
-	// Check if shell cache exists
-	cacheExists, err := b.Evaluate(`
-		(async () => {
-			const cacheNames = await caches.keys();
-			return cacheNames.some(name => name.includes('michael-shell'));
-		})()
-	`)
+	manifest := helpers.LoadServiceWorkerManifest(t)
+	storage := helpers.OpenCacheStorage(t, b)
 
-	if err != nil {
-		t.Fatalf("Failed to check cache: %v", err)
+	shell := storage.URLs(manifest.ShellCache())
+	for _, asset := range manifest.ShellAssets {
+		if !slices.Contains(shell, asset) {
+			t.Errorf("Shell asset %s not pre-cached in %s", asset, manifest.ShellCache())
+		}
 	}
 
-	if cacheExists != true {
-		t.Error("Shell cache not created")
-	} else {
-		t.Log("Shell cache exists")
+	chapters := storage.URLs(manifest.ChaptersCache())
+	for _, chapter := range manifest.DefaultChapters {
+		if slices.Contains(chapters, chapter) {
+			continue
+		}
+		if servedBySite(t, chapter) {
+			t.Errorf("Default chapter %s not pre-cached in %s", chapter, manifest.ChaptersCache())
+		} else {
+			t.Logf("Default chapter %s is not in this build", chapter)
+		}
+	}
+}
+
+// TestServiceWorkerCacheVersions verifies every cache belongs to the running
+// build's CACHE_VERSION.
+func TestServiceWorkerCacheVersions(t *testing.T) {
+	helpers.Parallel(t)
+	b := helpers.NewTestBrowser(t)
+	helpers.NavigateToBiblesList(t, b)
+	helpers.WaitForServiceWorker(t, b)
+
+	manifest := helpers.LoadServiceWorkerManifest(t)
+	for _, name := range helpers.OpenCacheStorage(t, b).Names("michael-") {
+		if !strings.HasSuffix(name, "-v"+manifest.Version) {
+			t.Errorf("Cache %s is not from build %s", name, manifest.Version)
+		}
 	}
 }
 
@@ -454,11 +499,13 @@ func TestServiceWorkerPrecache(t *testing.T) {
 
 // TestOfflineFallbackPage verifies offline fallback shows for uncached pages.
 func TestOfflineFallbackPage(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	// Load main page to register SW
 	helpers.NavigateToBiblesList(t, b)
-	time.Sleep(3 * time.Second)
+	helpers.WaitForServiceWorker(t, b)
+	helpers.WaitForCachedURL(t, b, "michael-shell-", "/offline.html")
 
 	// Go offline
 	if err := b.SetOffline(true); err != nil {
@@ -470,8 +517,6 @@ func TestOfflineFallbackPage(t *testing.T) {
 		// Navigation might fail, which is expected
 	}
 
-	time.Sleep(1 * time.Second)
-
 	// Check for offline page content
 	content, _ := b.PageContent()
 	isOfflinePage := strings.Contains(content, "offline") ||
@@ -482,19 +527,22 @@ func TestOfflineFallbackPage(t *testing.T) {
 	b.SetOffline(false)
 
 	if !isOfflinePage {
-		t.Log("Offline page may not have specific markers - checking for basic content")
-	} else {
-		t.Log("Offline fallback page displayed")
+		t.Error("Offline fallback page not displayed for an uncached page")
 	}
 }
 
 // TestOfflineWithCachedCSS verifies cached pages render with styles when offline.
 func TestOfflineWithCachedCSS(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
-	// Visit page to cache it and its CSS
+	// Visit page to register the SW, then reload through it to cache the page
 	helpers.NavigateToBiblesList(t, b)
-	time.Sleep(3 * time.Second)
+	helpers.WaitForServiceWorker(t, b)
+	if err := b.Reload(); err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	helpers.WaitForCachedURL(t, b, "michael-chapters-", "/bible/")
 
 	// Go offline
 	if err := b.SetOffline(true); err != nil {
@@ -505,7 +553,6 @@ func TestOfflineWithCachedCSS(t *testing.T) {
 	if err := b.Reload(); err != nil {
 		t.Fatalf("Failed to reload: %v", err)
 	}
-	time.Sleep(1 * time.Second)
 
 	// Check if CSS is loaded by checking a styled element
 	hasStyles, err := b.Evaluate(`
@@ -532,62 +579,31 @@ func TestOfflineWithCachedCSS(t *testing.T) {
 	}
 }
 
-// initiateBibleDownload finds the download form, checks a Bible, and clicks download.
-// Returns false and calls t.Skip if required UI elements are absent.
-func initiateBibleDownload(t *testing.T, b *helpers.Browser) bool {
-	t.Helper()
-	if !b.Find("#offline-download-form").Exists() {
-		t.Skip("Offline download form not found on page")
-		return false
-	}
-	checkbox := b.Find(".bible-download-checkbox")
-	if !checkbox.Exists() {
-		t.Skip("No Bible download checkboxes found")
-		return false
-	}
-	if err := checkbox.Click(); err != nil {
-		t.Fatalf("Failed to click checkbox: %v", err)
-	}
-	downloadBtn := b.Find("#download-offline-btn")
-	if !downloadBtn.Exists() {
-		t.Skip("Download button not found")
-		return false
-	}
-	if err := downloadBtn.Click(); err != nil {
-		t.Fatalf("Failed to click download: %v", err)
-	}
-	return true
-}
-
 // TestBibleDownloadFlow tests the full Bible download workflow.
 func TestBibleDownloadFlow(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
-	if err := b.Navigate(helpers.BaseURL + "/bible/"); err != nil {
-		t.Fatalf("Failed to navigate: %v", err)
-	}
-
-	if !initiateBibleDownload(t, b) {
-		return
-	}
-
-	// Wait for download to start (check for progress indicator)
-	time.Sleep(2 * time.Second)
+	settings := helpers.OpenOfflineSettings(t, b)
+	helpers.WaitForServiceWorker(t, b)
 
-	if progressBar := b.Find("#download-progress-bar"); progressBar.Exists() {
-		t.Log("Download progress bar displayed")
+	bible := settings.FirstBible()
+	if bible == "" {
+		t.Fatal("No Bibles offered for download")
 	}
 
-	if status := b.Find(".bible-download-status"); status.Exists() {
-		text, _ := status.Text()
-		t.Logf("Download status: %s", text)
+	// Wait for the service worker to report progress
+	p := settings.StartDownload(bible)
+	if p.Bible != bible {
+		t.Errorf("Progress reported for %q, expected %q", p.Bible, bible)
 	}
-
-	t.Log("Bible download flow initiated successfully")
+	t.Logf("Downloading %s: %d%% (%d/%d chapters)", p.Bible, p.Progress, p.Completed, p.Total)
+	t.Logf("Download status: %s", settings.Status(bible))
 }
 
 // TestDownloadCancellation tests cancelling an in-progress download.
 func TestDownloadCancellation(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/bible/"); err != nil {
@@ -610,36 +626,56 @@ func TestDownloadCancellation(t *testing.T) {
 	}
 }
 
-// TestCacheStatusAPI tests the getCacheStatus API.
+// TestCacheStatusAPI tests that getCacheStatus counts exactly the chapters
+// held in the chapters cache.
 func TestCacheStatusAPI(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
+	helpers.NavigateToBiblesList(t, b)
 
-	if err := b.Navigate(helpers.BaseURL + "/bible/"); err != nil {
-		t.Fatalf("Failed to navigate: %v", err)
+	// getCacheStatus asks the active service worker
+	helpers.WaitForServiceWorker(t, b)
+
+	manifest := helpers.LoadServiceWorkerManifest(t)
+	storage := helpers.OpenCacheStorage(t, b)
+	chapters := cachedChapters(storage, manifest)
+	if got := chapterCount(t, b); got != len(chapters) {
+		t.Fatalf("getCacheStatus reported %d chapters, cache holds %d", got, len(chapters))
+	}
+	if len(chapters) == 0 {
+		t.Skip("No chapters cached to evict")
 	}
 
-	// Wait for OfflineManager
-	time.Sleep(2 * time.Second)
+	storage.Delete(manifest.ChaptersCache(), chapters[0])
+	if got := chapterCount(t, b); got != len(chapters)-1 {
+		t.Errorf("getCacheStatus reported %d chapters after evicting %s, expected %d",
+			got, chapters[0], len(chapters)-1)
+	}
+}
 
-	// Test getCacheStatus
-	status, err := b.Evaluate(`
-		(async () => {
-			if (!window.Michael?.OfflineManager) return null;
-			await window.Michael.OfflineManager.initialize('/sw.js');
-			const status = await window.Michael.OfflineManager.getCacheStatus();
-			return status;
-		})()
-	`)
+// chapterPage matches the chapter URLs getCacheStatus counts.
+var chapterPage = regexp.MustCompile(`^/bible/[^/]+/[^/]+/\d+/?$`)
 
-	if err != nil {
-		t.Fatalf("Failed to get cache status: %v", err)
+// cachedChapters returns the chapter pages in the build's chapters cache.
+func cachedChapters(storage *helpers.CacheStorage, manifest helpers.ServiceWorkerManifest) []string {
+	var chapters []string
+	for _, url := range storage.URLs(manifest.ChaptersCache()) {
+		if chapterPage.MatchString(url) {
+			chapters = append(chapters, url)
+		}
 	}
+	return chapters
+}
 
+// chapterCount returns the chapterCount from getCacheStatus.
+func chapterCount(t *testing.T, b *e2e.Browser) int {
+	t.Helper()
+	status := helpers.GetCacheStatus(t, b)
 	if status == nil {
-		t.Error("getCacheStatus returned null")
-	} else {
-		t.Logf("Cache status API working: %v", status)
+		t.Fatal("getCacheStatus returned null")
 	}
+	count, _ := status["chapterCount"].(float64)
+	return int(count)
 }
 
 // =============================================================================
@@ -648,6 +684,7 @@ func TestCacheStatusAPI(t *testing.T) {
 
 // TestInstallBannerExists verifies the install banner markup exists.
 func TestInstallBannerExists(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -672,6 +709,7 @@ func TestInstallBannerExists(t *testing.T) {
 
 // TestInstallBannerHiddenByDefault verifies banner starts hidden.
 func TestInstallBannerHiddenByDefault(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -691,6 +729,7 @@ func TestInstallBannerHiddenByDefault(t *testing.T) {
 
 // TestIOSInstructionsBanner verifies iOS instructions element exists.
 func TestIOSInstructionsBanner(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -704,11 +743,11 @@ func TestIOSInstructionsBanner(t *testing.T) {
 
 // TestPWAInstallAPI verifies the PWAInstall API is available.
 func TestPWAInstallAPI(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	// Wait for script to load
-	time.Sleep(1 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.PWAInstall")
 
 	// Check API functions exist
 	apis, err := b.Evaluate(`
@@ -744,10 +783,11 @@ func TestPWAInstallAPI(t *testing.T) {
 
 // TestUserStorageAPI verifies IndexedDB storage API is available.
 func TestUserStorageAPI(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(1 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.UserStorage")
 
 	// Check API functions
 	apis, err := b.Evaluate(`
@@ -786,6 +826,7 @@ func TestUserStorageAPI(t *testing.T) {
 
 // TestIndexedDBSupported verifies IndexedDB is supported.
 func TestIndexedDBSupported(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -806,10 +847,11 @@ func TestIndexedDBSupported(t *testing.T) {
 
 // TestUserStorageReadingProgress tests saving and retrieving reading progress.
 func TestUserStorageReadingProgress(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(1 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.UserStorage")
 
 	// Test save and retrieve progress
 	result, err := b.Evaluate(`
@@ -855,10 +897,11 @@ func TestUserStorageReadingProgress(t *testing.T) {
 
 // TestUserStorageBookmarks tests adding and retrieving bookmarks.
 func TestUserStorageBookmarks(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(1 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.UserStorage")
 
 	result, err := b.Evaluate(`
 		(async () => {
@@ -899,10 +942,11 @@ func TestUserStorageBookmarks(t *testing.T) {
 
 // TestUserStorageSettings tests saving and retrieving settings.
 func TestUserStorageSettings(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(1 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.UserStorage")
 
 	result, err := b.Evaluate(`
 		(async () => {
@@ -950,10 +994,11 @@ func TestUserStorageSettings(t *testing.T) {
 
 // TestReadingTrackerAPI verifies the ReadingTracker API is available.
 func TestReadingTrackerAPI(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToSingle(t, b, "asv", "Gen", 1)
+	helpers.NavigateToSingle(t, b, helpers.PlainBible(t), "Gen", 1)
 
-	time.Sleep(2 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.ReadingTracker")
 
 	apis, err := b.Evaluate(`
 		({
@@ -984,22 +1029,29 @@ func TestReadingTrackerAPI(t *testing.T) {
 
 // TestReadingTrackerAutoSave verifies progress is auto-saved on chapter pages.
 func TestReadingTrackerAutoSave(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToSingle(t, b, "asv", "Gen", 1)
+	bible := helpers.PlainBible(t)
+	helpers.NavigateToSingle(t, b, bible, "Gen", 1)
 
-	// Wait for tracker to initialize and save
-	time.Sleep(3 * time.Second)
+	// Wait for the tracker to initialize and save
+	helpers.WaitForCondition(t, b, "reading progress in "+bible, fmt.Sprintf(`
+		(async () => {
+			const storage = window.Michael?.UserStorage;
+			return !!storage && (await storage.getProgress(%q)) !== null;
+		})()
+	`, bible))
 
 	// Check if progress was saved
-	result, err := b.Evaluate(`
+	result, err := b.Evaluate(fmt.Sprintf(`
 		(async () => {
 			const storage = window.Michael?.UserStorage;
 			if (!storage) return null;
 
-			const progress = await storage.getProgress('asv');
+			const progress = await storage.getProgress(%q);
 			return progress;
 		})()
-	`)
+	`, bible))
 
 	if err != nil {
 		t.Fatalf("Failed to check auto-saved progress: %v", err)
@@ -1014,10 +1066,17 @@ func TestReadingTrackerAutoSave(t *testing.T) {
 
 // TestReadingStreak verifies reading streak tracking.
 func TestReadingStreak(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
-	helpers.NavigateToSingle(t, b, "asv", "Gen", 1)
+	helpers.NavigateToSingle(t, b, helpers.PlainBible(t), "Gen", 1)
 
-	time.Sleep(3 * time.Second)
+	// The tracker records today's reading once it has initialized
+	helpers.WaitForCondition(t, b, "the reading streak", `
+		(async () => {
+			const storage = window.Michael?.UserStorage;
+			return !!storage && !!(await storage.getSetting('readingStreak', null))?.lastReadDate;
+		})()
+	`)
 
 	// Check streak info
 	streak, err := b.Evaluate(`
@@ -1049,10 +1108,11 @@ func TestReadingStreak(t *testing.T) {
 
 // TestBackgroundSyncAPI verifies background sync API is available.
 func TestBackgroundSyncAPI(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(2 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.OfflineManager")
 
 	apis, err := b.Evaluate(`
 		({
@@ -1083,6 +1143,7 @@ func TestBackgroundSyncAPI(t *testing.T) {
 
 // TestServiceWorkerSyncHandler verifies SW has sync event handler.
 func TestServiceWorkerSyncHandler(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/sw.js"); err != nil {
@@ -1108,6 +1169,7 @@ func TestServiceWorkerSyncHandler(t *testing.T) {
 
 // TestInstallBannerStyles verifies install banner CSS is applied.
 func TestInstallBannerStyles(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -1139,6 +1201,7 @@ func TestInstallBannerStyles(t *testing.T) {
 
 // TestInstallBannerAccessibility verifies install banner has proper ARIA attributes.
 func TestInstallBannerAccessibility(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -1173,6 +1236,7 @@ func TestInstallBannerAccessibility(t *testing.T) {
 
 // TestOfflineMessagesAccessibility verifies offline messages have proper roles.
 func TestOfflineMessagesAccessibility(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 
 	if err := b.Navigate(helpers.BaseURL + "/bible/"); err != nil {
@@ -1194,6 +1258,7 @@ func TestOfflineMessagesAccessibility(t *testing.T) {
 
 // TestMobilePWAMetaTags verifies mobile-specific PWA meta tags.
 func TestMobilePWAMetaTags(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewMobileBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -1219,6 +1284,7 @@ func TestMobilePWAMetaTags(t *testing.T) {
 
 // TestMobileInstallBanner verifies install banner works on mobile viewport.
 func TestMobileInstallBanner(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewMobileBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
@@ -1237,10 +1303,11 @@ func TestMobileInstallBanner(t *testing.T) {
 
 // TestOfflineManagerErrorHandling tests error handling in offline manager.
 func TestOfflineManagerErrorHandling(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(2 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.OfflineManager")
 
 	// Try to download a non-existent Bible
 	result, err := b.Evaluate(`
@@ -1279,10 +1346,11 @@ func TestOfflineManagerErrorHandling(t *testing.T) {
 
 // TestUserStorageErrorHandling tests IndexedDB error handling.
 func TestUserStorageErrorHandling(t *testing.T) {
+	helpers.Parallel(t)
 	b := helpers.NewTestBrowser(t)
 	helpers.NavigateToBiblesList(t, b)
 
-	time.Sleep(1 * time.Second)
+	helpers.WaitForGlobal(t, b, "Michael.UserStorage")
 
 	// Test getting non-existent data returns null/default
 	result, err := b.Evaluate(`
@@ -0,0 +1,122 @@
+package regression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+	"michael-tests/helpers/selectors"
+)
+
+// contrastLimit caps how many elements of each target are measured, which
+// is plenty to catch every color pair a chapter uses.
+const contrastLimit = 200
+
+// contrastTargets are the colored texts whose contrast is checked in every
+// color mode, with the scene that shows them.
+var contrastTargets = []struct {
+	name     string
+	setup    func(t *testing.T, b *e2e.Browser)
+	selector string
+}{
+	{"diff-insert", setupSSSScene, fmt.Sprintf(selectors.ClassDiffFmt, "insert")},
+	{"diff-subst", setupSSSScene, fmt.Sprintf(selectors.ClassDiffFmt, "subst")},
+	{"diff-punct", setupSSSScene, fmt.Sprintf(selectors.ClassDiffFmt, "punct")},
+	{"diff-spelling", setupSSSScene, fmt.Sprintf(selectors.ClassDiffFmt, "spelling")},
+	{"diff-omit", setupSSSScene, fmt.Sprintf(selectors.ClassDiffFmt, "omit")},
+	{"strongs-word", setupSingleScene, selectors.ClassStrongsWord},
+	{"strongs-ref", setupSingleScene, selectors.ClassStrongsRef},
+	{"verse-num", setupPlainSingle, selectors.ClassProse + " " + selectors.ClassVerse + " sup"},
+	{"parallel-verse-num", setupSSSScene, selectors.ClassParallelVerseNum},
+}
+
+// setupPlainSingle shows the plain Bible's Genesis 1, whose verse numbers
+// are plain text.
+func setupPlainSingle(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	helpers.OpenSingleChapter(t, b, helpers.PlainBible(t), "Gen", 1)
+}
+
+// motionPages are the pages checked for motion under reduced motion.
+var motionPages = []struct {
+	name  string
+	setup func(t *testing.T, b *e2e.Browser)
+}{
+	{"compare", setupCompareScene},
+	{"sss", setupSSSScene},
+	{"single", setupSingleScene},
+	{"search", setupSearchScene},
+}
+
+// =============================================================================
+// COLOR SCHEME TESTS
+// =============================================================================
+
+// TestColorSchemeContrast tests that diff highlights, Strong's links and verse
+// numbers meet WCAG AA text contrast in light, dark, more-contrast and
+// forced-colors modes. Targets a page does not render, such as a diff kind
+// Genesis 1 lacks, are logged and skipped.
+func TestColorSchemeContrast(t *testing.T) {
+	for _, mode := range helpers.ColorModes {
+		for _, target := range contrastTargets {
+			t.Run(mode.Name+"/"+target.name, func(t *testing.T) {
+				b := helpers.NewTestBrowser(t)
+				helpers.EmulateMediaFeatures(t, b, mode.Features)
+				target.setup(t, b)
+
+				samples := helpers.MeasureContrast(t, b, target.selector, contrastLimit)
+				if len(samples) == 0 {
+					t.Skipf("No visible %s text on this page", target.selector)
+				}
+				t.Logf("Measured %d %s elements", len(samples), target.selector)
+				helpers.CheckContrast(t, target.name, samples)
+			})
+		}
+	}
+}
+
+// TestReducedMotion tests that nothing animates, transitions or scrolls
+// smoothly when the user prefers reduced motion.
+func TestReducedMotion(t *testing.T) {
+	for _, page := range motionPages {
+		t.Run(page.name, func(t *testing.T) {
+			b := helpers.NewTestBrowser(t)
+			helpers.EmulateMediaFeatures(t, b, map[string]string{"prefers-reduced-motion": "reduce"})
+			page.setup(t, b)
+
+			for _, m := range helpers.MotionOffenders(t, b) {
+				t.Errorf("Moves under reduced motion: %s", m)
+			}
+		})
+	}
+}
+
+// TestThemeToggle tests that the theme starts from the system color scheme,
+// that the toggle switches it, and that the choice survives a reload and is
+// already applied when the reloaded page first paints.
+func TestThemeToggle(t *testing.T) {
+	for _, scheme := range []string{"light", "dark"} {
+		t.Run(scheme, func(t *testing.T) {
+			b := helpers.NewTestBrowser(t)
+			helpers.EmulateMediaFeatures(t, b, map[string]string{"prefers-color-scheme": scheme})
+			setupPlainSingle(t, b)
+
+			if got := helpers.CurrentTheme(t, b); got != scheme {
+				t.Errorf("Expected the %s system scheme to start the %s theme, got %q", scheme, scheme, got)
+			}
+			chosen := helpers.ToggleTheme(t, b)
+
+			firstPaint := helpers.RecordFirstPaintTheme(t, b)
+			if err := b.Reload(); err != nil {
+				t.Fatalf("Failed to reload: %v", err)
+			}
+			if got := firstPaint.Theme(); got != chosen {
+				t.Errorf("Reloaded page first painted the %q theme, expected %q", got, chosen)
+			}
+			if got := helpers.CurrentTheme(t, b); got != chosen {
+				t.Errorf("Reloaded page has the %q theme, expected %q", got, chosen)
+			}
+		})
+	}
+}
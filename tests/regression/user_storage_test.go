@@ -0,0 +1,157 @@
+package regression
+
+import (
+	"testing"
+	"time"
+
+	"michael-tests/helpers"
+)
+
+// These tests seed the user database from tests/testdata/userstorage instead
+// of building it through the UI, and check what user-storage.js leaves behind
+// against golden snapshots in tests/golden.
+
+// =============================================================================
+// SEEDED STATE TESTS
+// =============================================================================
+
+// TestUserStorageReadsSeededData tests that UserStorage sees records written
+// before the page loaded.
+func TestUserStorageReadsSeededData(t *testing.T) {
+	helpers.Parallel(t)
+	b := helpers.NewTestBrowser(t)
+	helpers.SeedUserStorage(t, b, helpers.LoadUserData(t, "reader"))
+	helpers.NavigateToBiblesList(t, b)
+
+	result, err := b.Evaluate(`
+		(async () => {
+			const storage = window.Michael.UserStorage;
+			const lastRead = await storage.getLastRead();
+			return {
+				lastRead: lastRead ? lastRead.bibleId + ' ' + lastRead.bookId + ' ' + lastRead.chapter : '',
+				asvBookmarks: (await storage.getBookmarks('asv')).length,
+				notes: (await storage.getAllNotes()).length,
+				fontSize: await storage.getSetting('fontSize', 0)
+			};
+		})()
+	`)
+	if err != nil {
+		t.Fatalf("Failed to read seeded user data: %v", err)
+	}
+
+	got, ok := result.(map[string]interface{})
+	if !ok {
+		t.Fatal("Unexpected UserStorage response type")
+	}
+	if got["lastRead"] != "asv gen 3" {
+		t.Errorf("Expected last read asv gen 3, got %v", got["lastRead"])
+	}
+	if got["asvBookmarks"] != float64(1) {
+		t.Errorf("Expected 1 asv bookmark, got %v", got["asvBookmarks"])
+	}
+	if got["notes"] != float64(1) {
+		t.Errorf("Expected 1 note, got %v", got["notes"])
+	}
+	if got["fontSize"] != float64(18) {
+		t.Errorf("Expected font size 18, got %v", got["fontSize"])
+	}
+}
+
+// TestUserStorageEditsSnapshot tests the records left by editing seeded data
+// through the UserStorage API.
+func TestUserStorageEditsSnapshot(t *testing.T) {
+	helpers.Parallel(t)
+	b := helpers.NewTestBrowser(t)
+	helpers.SeedUserStorage(t, b, helpers.LoadUserData(t, "reader"))
+	helpers.NavigateToBiblesList(t, b)
+
+	since := time.Now()
+	_, err := b.Evaluate(`
+		(async () => {
+			const storage = window.Michael.UserStorage;
+			await storage.saveProgress('asv', 'gen', 4, 0);
+			await storage.addBookmark('asv', 'John 3:16');
+			await storage.updateNote(1, { content: 'First gospel' });
+			await storage.removeSetting('theme');
+			return true;
+		})()
+	`)
+	if err != nil {
+		t.Fatalf("Failed to edit user data: %v", err)
+	}
+
+	helpers.MatchUserStorageGolden(t, b, "userstorage-edits", since)
+}
+
+// =============================================================================
+// SCHEMA VERSION TESTS
+// =============================================================================
+
+// TestUserStorageUpgradesOlderSchemas tests that opening a database seeded at
+// each older DB_VERSION upgrades it to the current layout and keeps its
+// records.
+func TestUserStorageUpgradesOlderSchemas(t *testing.T) {
+	helpers.Parallel(t)
+	current := helpers.CurrentUserStorageSchema()
+	if len(helpers.UserStorageSchemas) < 2 {
+		t.Skipf("user-storage.js has no schema older than DB_VERSION %d", current.Version)
+	}
+	fixture := helpers.LoadUserData(t, "reader")
+
+	for _, schema := range helpers.UserStorageSchemas[:len(helpers.UserStorageSchemas)-1] {
+		data := helpers.UserData{}
+		for store, records := range fixture {
+			if _, ok := schema.Stores[store]; ok {
+				data[store] = records
+			}
+		}
+
+		b := helpers.NewTestBrowser(t)
+		helpers.SeedUserStorageSchema(t, b, schema, data)
+		helpers.NavigateToBiblesList(t, b)
+		if _, err := b.Evaluate(`window.Michael.UserStorage.init().then(() => true)`); err != nil {
+			t.Fatalf("Failed to open database seeded at version %d: %v", schema.Version, err)
+		}
+
+		snap := helpers.SnapshotUserStorage(t, b)
+		if snap.Version != current.Version {
+			t.Errorf("Version %d database opened at version %d, expected %d", schema.Version, snap.Version, current.Version)
+		}
+		for store := range current.Stores {
+			if _, ok := snap.Stores[store]; !ok {
+				t.Errorf("Upgrade from version %d did not create store %s", schema.Version, store)
+			}
+		}
+		for store, records := range data {
+			if len(snap.Stores[store]) != len(records) {
+				t.Errorf("Upgrade from version %d left %d records in %s, expected %d",
+					schema.Version, len(snap.Stores[store]), store, len(records))
+			}
+		}
+	}
+}
+
+// TestUserStorageRejectsNewerSchema tests that a database written by a newer
+// build is reported rather than silently downgraded.
+func TestUserStorageRejectsNewerSchema(t *testing.T) {
+	helpers.Parallel(t)
+	b := helpers.NewTestBrowser(t)
+	newer := helpers.CurrentUserStorageSchema()
+	newer.Version++
+	helpers.SeedUserStorageSchema(t, b, newer, helpers.LoadUserData(t, "reader"))
+	helpers.NavigateToBiblesList(t, b)
+
+	name, err := b.Evaluate(`
+		window.Michael.UserStorage.init().then(() => '', (e) => e && e.name)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if name != "VersionError" {
+		t.Errorf("Expected VersionError opening a version %d database, got %q", newer.Version, name)
+	}
+
+	if snap := helpers.SnapshotUserStorage(t, b); snap.Version != newer.Version {
+		t.Errorf("Database version changed from %d to %d", newer.Version, snap.Version)
+	}
+}
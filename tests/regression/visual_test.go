@@ -0,0 +1,85 @@
+package regression
+
+import (
+	"testing"
+
+	"github.com/JuniperBible/magellan/pkg/e2e"
+	"michael-tests/helpers"
+)
+
+// visualScene brings a page into a stable state for a golden screenshot.
+type visualScene struct {
+	name  string
+	setup func(t *testing.T, b *e2e.Browser)
+}
+
+// visualViewports reuses the desktop and mobile browser profiles.
+var visualViewports = []struct {
+	name string
+	open func(t *testing.T) *e2e.Browser
+}{
+	{"desktop", helpers.NewTestBrowser},
+	{"mobile", helpers.NewMobileBrowser},
+}
+
+// visualScenes covers the pages styled by theme-compare.css and theme-strongs.css.
+var visualScenes = []visualScene{
+	{"compare", setupCompareScene},
+	{"sss", setupSSSScene},
+	{"single", setupSingleScene},
+	{"search", setupSearchScene},
+}
+
+// setupCompareScene shows the plain and Catholic Bibles' Genesis 1 side by
+// side in normal mode.
+func setupCompareScene(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	page := helpers.OpenComparePage(t, b)
+	page.SelectTranslations(helpers.PlainBible(t), helpers.CatholicBible(t))
+	page.OpenChapter("Gen", 1)
+}
+
+// setupSSSScene shows the Catholic and plain Bibles' Genesis 1 in
+// side-by-side mode.
+func setupSSSScene(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	sss := helpers.OpenComparePage(t, b).EnterSSS()
+	sss.SelectBibles(helpers.CatholicBible(t), helpers.PlainBible(t))
+	sss.OpenChapter("Gen", 1)
+}
+
+// setupSingleScene shows the Strong's Bible's Genesis 1.
+func setupSingleScene(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	page := helpers.OpenSingleChapter(t, b, helpers.StrongsBible(t), "Gen", 1)
+	if !page.HasStrongs() {
+		t.Fatal("Strong's references did not render")
+	}
+}
+
+// setupSearchScene shows results for a plain text query in the plain Bible.
+func setupSearchScene(t *testing.T, b *e2e.Browser) {
+	t.Helper()
+	results := helpers.OpenSearchPage(t, b).Search("shepherd", helpers.SearchOptions{Bible: helpers.PlainBible(t)})
+	if len(results.Refs) == 0 {
+		t.Fatalf("Search results did not render: %s", results.Message)
+	}
+}
+
+// TestVisualGolden compares every scene on every viewport against tests/golden.
+// Run with -update to re-bless the golden images after an intended change.
+func TestVisualGolden(t *testing.T) {
+	if !helpers.GoldensCommitted(t, "*.png") {
+		t.Skip("No golden images are committed yet; bless them with make update-golden")
+	}
+	for _, vp := range visualViewports {
+		for _, scene := range visualScenes {
+			name := scene.name + "-" + vp.name
+			t.Run(name, func(t *testing.T) {
+				b := vp.open(t)
+				scene.setup(t, b)
+				helpers.MatchGolden(t, b, name)
+			})
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package selectorgen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SelectorFuncs are the functions and methods whose string arguments are
+// CSS selectors: the magellan browser methods and the helpers wrapping them.
+var SelectorFuncs = map[string]bool{
+	"Find": true, "FindAll": true,
+	"WaitFor": true, "WaitForVisible": true, "WaitForHidden": true,
+	"WaitForEnabled": true, "WaitForText": true,
+	"WaitAndClick": true, "WaitAndType": true, "SelectOption": true,
+	"CheckCheckbox": true, "ExpectVisible": true, "ExpectHidden": true,
+	"ExpectText": true, "ExpectOptionCount": true,
+	"countElements": true, "optionCount": true, "openShareMenu": true,
+}
+
+// Ref is one ID or class named in a selector. A Name containing "*" came
+// from a printf verb.
+type Ref struct {
+	Kind Kind
+	Name string
+}
+
+// Problem is a selector in Go source naming something no template or script
+// defines.
+type Problem struct {
+	Pos      token.Position
+	Selector string
+	Ref      Ref
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %q selects %s %s%s, which no template or script defines",
+		p.Pos, p.Selector, p.Ref.Kind, p.Ref.Kind.Prefix(), strings.ReplaceAll(p.Ref.Name, "*", "%s"))
+}
+
+// Refs returns the IDs and classes named in a CSS selector, skipping
+// attribute selectors and quoted strings. It returns nil for strings that
+// cannot be selectors, such as script expressions passed to Evaluate.
+func Refs(selector string) []Ref {
+	if !looksLikeSelector(selector) {
+		return nil
+	}
+	var refs []Ref
+	for i := 0; i < len(selector); i++ {
+		switch c := selector[i]; c {
+		case '[':
+			if end := strings.IndexByte(selector[i:], ']'); end >= 0 {
+				i += end
+			}
+		case '\'', '"':
+			if end := strings.IndexByte(selector[i+1:], c); end >= 0 {
+				i += end + 1
+			}
+		case '#', '.':
+			name, n := readName(selector[i+1:])
+			if name != "" {
+				kind := Class
+				if c == '#' {
+					kind = ID
+				}
+				refs = append(refs, Ref{kind, name})
+			}
+			i += n
+		}
+	}
+	return refs
+}
+
+// looksLikeSelector reports whether s uses only the characters of a CSS
+// selector outside its attribute selectors and strings.
+func looksLikeSelector(s string) bool {
+	depth := 0
+	for _, c := range s {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth > 0:
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-_#.:>+~,*()% ", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// readName reads an identifier from s, turning printf verbs into "*". It
+// returns "" if s does not start with one.
+func readName(s string) (string, int) {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '%' && i+1 < len(s) && strings.IndexByte("sdv", s[i+1]) >= 0:
+			b.WriteByte('*')
+			i += 2
+			continue
+		case c == '-' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return b.String(), i
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String(), i
+}
+
+// Check parses the Go files under each directory and returns every selector
+// that names an ID or class the registry does not define. Selectors are the
+// string literals passed to SelectorFuncs or declared as constants. Generated
+// files are skipped; their constants come from the registry.
+func Check(reg *Registry, dirs ...string) ([]Problem, error) {
+	var problems []Problem
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_gen.go") {
+				return err
+			}
+			file, err := parser.ParseFile(fset, p, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.GenDecl:
+					if n.Tok != token.CONST {
+						return true
+					}
+					for _, spec := range n.Specs {
+						for _, v := range spec.(*ast.ValueSpec).Values {
+							problems = append(problems, checkExpr(reg, fset, v)...)
+						}
+					}
+				case *ast.CallExpr:
+					if SelectorFuncs[calleeName(n.Fun)] {
+						for _, arg := range n.Args {
+							problems = append(problems, checkExpr(reg, fset, arg)...)
+						}
+					}
+				}
+				return true
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return problems, nil
+}
+
+// calleeName returns the function or method name of a call.
+func calleeName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+// checkExpr checks each double-quoted string literal in a chain of
+// concatenations.
+func checkExpr(reg *Registry, fset *token.FileSet, expr ast.Expr) []Problem {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return append(checkExpr(reg, fset, e.X), checkExpr(reg, fset, e.Y)...)
+		}
+	case *ast.BasicLit:
+		if e.Kind != token.STRING || !strings.HasPrefix(e.Value, `"`) {
+			return nil
+		}
+		selector, err := strconv.Unquote(e.Value)
+		if err != nil {
+			return nil
+		}
+		var problems []Problem
+		for _, ref := range Refs(selector) {
+			if !reg.Defines(ref.Kind, ref.Name) {
+				problems = append(problems, Problem{Pos: fset.Position(e.Pos()), Selector: selector, Ref: ref})
+			}
+		}
+		return problems
+	}
+	return nil
+}
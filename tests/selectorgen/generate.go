@@ -0,0 +1,81 @@
+package selectorgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var nameSeparator = regexp.MustCompile(`[-_*]+`)
+
+// Generate returns Go source for package pkg declaring one constant per
+// registry entry, such as IDBookSelect = "#book-select" and, for patterns,
+// IDBibleFmt = "#bible-%s".
+func Generate(reg *Registry, pkg string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by selectorgen; DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	type named struct {
+		name string
+		Entry
+	}
+	used := make(map[string]bool)
+	for _, kind := range []Kind{ID, Class} {
+		var consts []named
+		for _, e := range reg.Entries() {
+			if e.Kind != kind {
+				continue
+			}
+			name := constName(e)
+			for n := 2; used[name]; n++ {
+				name = constName(e) + strconv.Itoa(n)
+			}
+			used[name] = true
+			consts = append(consts, named{name, e})
+		}
+		slices.SortFunc(consts, func(a, b named) int { return strings.Compare(a.name, b.name) })
+
+		fmt.Fprintf(&b, "\n// Element %ss.\nconst (\n", kind)
+		for _, c := range consts {
+			fmt.Fprintf(&b, "\t// %s is defined in %s.\n", c.name, describeSources(c.Sources))
+			fmt.Fprintf(&b, "\t%s = %q\n", c.name, c.Selector())
+		}
+		b.WriteString(")\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// constName returns the exported constant name for an entry.
+func constName(e Entry) string {
+	var b strings.Builder
+	if e.Kind == ID {
+		b.WriteString("ID")
+	} else {
+		b.WriteString("Class")
+	}
+	for _, part := range nameSeparator.Split(e.Name, -1) {
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	if e.IsPattern() {
+		b.WriteString("Fmt")
+	}
+	return b.String()
+}
+
+// describeSources names the first source and counts the rest.
+func describeSources(sources []string) string {
+	switch len(sources) {
+	case 1:
+		return sources[0]
+	case 2:
+		return sources[0] + " and " + sources[1]
+	default:
+		return fmt.Sprintf("%s and %d other files", sources[0], len(sources)-1)
+	}
+}
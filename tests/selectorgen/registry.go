@@ -0,0 +1,126 @@
+// Package selectorgen builds a registry of the element IDs and classes that
+// the site's templates and scripts define, generates typed selector constants
+// from it, and checks the tests' selectors against it.
+package selectorgen
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Kind is the selector kind of a registry entry.
+type Kind int
+
+const (
+	// ID is an element id, selected with #name.
+	ID Kind = iota
+	// Class is an element class, selected with .name.
+	Class
+)
+
+// Prefix returns the CSS prefix for the kind.
+func (k Kind) Prefix() string {
+	if k == ID {
+		return "#"
+	}
+	return "."
+}
+
+// String returns "id" or "class".
+func (k Kind) String() string {
+	if k == ID {
+		return "id"
+	}
+	return "class"
+}
+
+// Entry is one ID or class and the files that define it. A Name containing
+// "*" is a pattern: part of it is filled in when the page renders, as in
+// id="bible-{{ .id }}".
+type Entry struct {
+	Kind    Kind
+	Name    string
+	Sources []string
+}
+
+// IsPattern reports whether the entry has a rendered-in part.
+func (e Entry) IsPattern() bool { return strings.Contains(e.Name, "*") }
+
+// Selector returns the CSS selector for the entry. Patterns use %s for the
+// rendered-in part.
+func (e Entry) Selector() string {
+	return e.Kind.Prefix() + strings.ReplaceAll(e.Name, "*", "%s")
+}
+
+type entryKey struct {
+	kind Kind
+	name string
+}
+
+// Registry is the set of IDs and classes defined by the site.
+type Registry struct {
+	entries map[entryKey]*Entry
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{entries: make(map[entryKey]*Entry)}
+}
+
+// Add records that source defines name. Names without a static part, such as
+// a class that is entirely a template variable, are ignored.
+func (r *Registry) Add(kind Kind, name, source string) {
+	if strings.Trim(name, "*-_") == "" {
+		return
+	}
+	key := entryKey{kind, name}
+	e, ok := r.entries[key]
+	if !ok {
+		e = &Entry{Kind: kind, Name: name}
+		r.entries[key] = e
+	}
+	if !slices.Contains(e.Sources, source) {
+		e.Sources = append(e.Sources, source)
+		slices.Sort(e.Sources)
+	}
+}
+
+// Entries returns every entry, IDs first, each kind sorted by name.
+func (r *Registry) Entries() []Entry {
+	out := make([]Entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		out = append(out, *e)
+	}
+	slices.SortFunc(out, func(a, b Entry) int {
+		if a.Kind != b.Kind {
+			return int(a.Kind) - int(b.Kind)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// Defines reports whether the site defines name. A name containing "*" (from
+// a %s in a test selector) matches an identical pattern or any exact name it
+// covers; an exact name matches itself or any pattern that covers it.
+func (r *Registry) Defines(kind Kind, name string) bool {
+	if _, ok := r.entries[entryKey{kind, name}]; ok {
+		return true
+	}
+	for key := range r.entries {
+		if key.kind != kind {
+			continue
+		}
+		if strings.Contains(name, "*") {
+			if ok, _ := path.Match(name, key.name); ok {
+				return true
+			}
+		} else if strings.Contains(key.name, "*") {
+			if ok, _ := path.Match(key.name, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
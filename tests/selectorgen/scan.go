@@ -0,0 +1,246 @@
+package selectorgen
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TemplateDirs are the directories, relative to the site root, whose Hugo
+// templates and HTML are scanned. The content adapter renders the verse
+// markup and static holds the offline fallback page.
+var TemplateDirs = []string{"layouts", "content", "static"}
+
+// ScriptDirs are the directories whose scripts are scanned for the classes
+// and IDs they add at runtime, such as the share menu and Strong's tooltip.
+var ScriptDirs = []string{"assets/js"}
+
+// wild marks a rendered-in part of a name while values are being assembled.
+const wild = "\x00"
+
+// Scan walks the site rooted at root and returns the registry of everything
+// its templates and scripts define.
+func Scan(root string) (*Registry, error) {
+	reg := NewRegistry()
+	for _, dir := range TemplateDirs {
+		if err := walk(root, dir, []string{".html", ".gotmpl"}, func(rel, src string) {
+			ScanTemplate(reg, rel, src)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, dir := range ScriptDirs {
+		if err := walk(root, dir, []string{".js"}, func(rel, src string) {
+			ScanScript(reg, rel, src)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return reg, nil
+}
+
+// walk calls fn with the slash-separated path and contents of every file
+// under root/dir with one of the extensions.
+func walk(root, dir string, exts []string, fn func(rel, src string)) error {
+	base := filepath.Join(root, dir)
+	if _, err := os.Stat(base); err != nil {
+		return fmt.Errorf("scan %s: %w", dir, err)
+	}
+	return filepath.WalkDir(base, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := filepath.Ext(p)
+		if !strings.HasSuffix(p, ".min.js") && slices.Contains(exts, ext) {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			fn(filepath.ToSlash(rel), string(data))
+		}
+		return nil
+	})
+}
+
+// =============================================================================
+// Templates
+// =============================================================================
+
+var (
+	templateComment = regexp.MustCompile(`(?s)\{\{-?\s*/\*.*?\*/\s*-?\}\}`)
+	htmlComment     = regexp.MustCompile(`(?s)<!--.*?-->`)
+	templateAction  = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+	// dictParam matches a partial parameter such as "toggleId" "highlight-toggle"
+	dictParam = regexp.MustCompile(`"(\w*(?:[iI]d|[cC]lass))"\s+"([^"{}]*)"`)
+	// nameAssign matches an action assigning an ID or class variable, such as
+	// $verseButtonsId := printf "%s%sverse-buttons" $idPrefix $separator
+	nameAssign    = regexp.MustCompile(`^\{\{-?\s*\$\w*([iI]d|[cC]lass)\s*:?=`)
+	templateQuote = regexp.MustCompile(`"([^"]*)"`)
+	// controlAction matches actions that render nothing themselves
+	controlAction = regexp.MustCompile(`^\{\{-?\s*(?:if|else|end|with|range|define|block|break|continue|\$\w+\s*:?=)\b`)
+	attrStart     = regexp.MustCompile(`(?:^|[\s"'])(id|class)\s*=\s*(["'])`)
+	formatVerb    = regexp.MustCompile(`%[sdv]`)
+	validName     = regexp.MustCompile(`^[A-Za-z_\-*][A-Za-z0-9_\-*]*$`)
+)
+
+// ScanTemplate adds the IDs and classes defined by a Hugo template: id= and
+// class= attributes, including markup built with printf, and ID and class
+// parameters passed to partials.
+func ScanTemplate(reg *Registry, source, src string) {
+	src = templateComment.ReplaceAllString(src, "")
+	src = htmlComment.ReplaceAllString(src, "")
+
+	for _, action := range templateAction.FindAllString(src, -1) {
+		if m := nameAssign.FindStringSubmatch(action); m != nil {
+			kind := Class
+			if strings.EqualFold(m[1], "id") {
+				kind = ID
+			}
+			for _, lit := range templateQuote.FindAllStringSubmatch(action, -1) {
+				addNames(reg, kind, lit[1], source)
+			}
+			continue
+		}
+		for _, m := range dictParam.FindAllStringSubmatch(action, -1) {
+			kind := Class
+			if strings.HasSuffix(strings.ToLower(m[1]), "id") {
+				kind = ID
+			}
+			addNames(reg, kind, m[2], source)
+		}
+	}
+
+	// Markup built inside actions escapes its quotes
+	src = strings.ReplaceAll(src, `\"`, `"`)
+	scanAttributes(reg, source, src, func(value string, i int) (string, int) {
+		if !strings.HasPrefix(value[i:], "{{") {
+			return "", 0
+		}
+		end := strings.Index(value[i:], "}}")
+		if end < 0 {
+			return "", len(value) - i
+		}
+		action := value[i : i+end+2]
+		if controlAction.MatchString(action) {
+			return " ", len(action)
+		}
+		return wild, len(action)
+	})
+}
+
+// scanAttributes adds the value of every id= and class= attribute in src.
+// expr recognizes an embedded expression at value[i:], returning what it
+// contributes to the value and its length, or a zero length if there is none.
+func scanAttributes(reg *Registry, source, src string, expr func(value string, i int) (string, int)) {
+	for _, loc := range attrStart.FindAllStringSubmatchIndex(src, -1) {
+		kind := Class
+		if src[loc[2]:loc[3]] == "id" {
+			kind = ID
+		}
+		quote := src[loc[4]]
+		rest := src[loc[5]:]
+
+		var b strings.Builder
+		for i := 0; i < len(rest) && rest[i] != quote && rest[i] != '\n'; {
+			if out, n := expr(rest, i); n > 0 {
+				b.WriteString(out)
+				i += n
+				continue
+			}
+			b.WriteByte(rest[i])
+			i++
+		}
+		addNames(reg, kind, b.String(), source)
+	}
+}
+
+// addNames adds each whitespace-separated name in value. Rendered-in parts,
+// whether template expressions or printf verbs, become "*".
+func addNames(reg *Registry, kind Kind, value, source string) {
+	value = formatVerb.ReplaceAllString(value, wild)
+	for _, name := range strings.Fields(value) {
+		for strings.Contains(name, wild+wild) {
+			name = strings.ReplaceAll(name, wild+wild, wild)
+		}
+		name = strings.ReplaceAll(name, wild, "*")
+		if validName.MatchString(name) {
+			reg.Add(kind, name, source)
+		}
+	}
+}
+
+// =============================================================================
+// Scripts
+// =============================================================================
+
+var (
+	blockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	lineComment  = regexp.MustCompile(`(?m)^\s*//.*$`)
+	jsString     = regexp.MustCompile("'([^'\\\\\\n]*)'|\"([^\"\\\\\\n]*)\"|`([^`]*)`")
+	classNameSet = regexp.MustCompile(`\.className\s*=\s*([^;\n]+)`)
+	classListAdd = regexp.MustCompile(`classList\.(add|toggle|replace)\(([^)]*)\)`)
+	idSet        = regexp.MustCompile("\\.id\\s*=\\s*('[^'\\n]*'|\"[^\"\\n]*\"|`[^`]*`)")
+	setAttribute = regexp.MustCompile(`setAttribute\(\s*['"](id|class)['"]\s*,\s*([^)]*)\)`)
+	templateExpr = regexp.MustCompile(`\$\{[^}]*\}`)
+)
+
+// ScanScript adds the IDs and classes a script gives the elements it creates:
+// className and id assignments, classList.add, setAttribute and markup in
+// string literals.
+func ScanScript(reg *Registry, source, src string) {
+	src = blockComment.ReplaceAllString(src, "")
+	src = lineComment.ReplaceAllString(src, "")
+
+	for _, m := range classNameSet.FindAllStringSubmatch(src, -1) {
+		addLiterals(reg, Class, m[1], source, -1)
+	}
+	for _, m := range classListAdd.FindAllStringSubmatch(src, -1) {
+		limit := -1
+		if m[1] == "toggle" {
+			// The second argument is the force flag
+			limit = 1
+		}
+		addLiterals(reg, Class, m[2], source, limit)
+	}
+	for _, m := range idSet.FindAllStringSubmatch(src, -1) {
+		addLiterals(reg, ID, m[1], source, 1)
+	}
+	for _, m := range setAttribute.FindAllStringSubmatch(src, -1) {
+		kind := Class
+		if m[1] == "id" {
+			kind = ID
+		}
+		addLiterals(reg, kind, m[2], source, 1)
+	}
+
+	scanAttributes(reg, source, src, func(value string, i int) (string, int) {
+		if !strings.HasPrefix(value[i:], "${") {
+			return "", 0
+		}
+		end := strings.IndexByte(value[i:], '}')
+		if end < 0 {
+			return "", len(value) - i
+		}
+		return wild, end + 1
+	})
+}
+
+// addLiterals adds the names in up to limit string literals in expr, or all
+// of them when limit is negative.
+func addLiterals(reg *Registry, kind Kind, expr, source string, limit int) {
+	for _, m := range jsString.FindAllStringSubmatch(expr, limit) {
+		value := m[1] + m[2]
+		if m[3] != "" {
+			value = templateExpr.ReplaceAllString(m[3], wild)
+		}
+		addNames(reg, kind, value, source)
+	}
+}
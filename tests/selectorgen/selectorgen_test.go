@@ -0,0 +1,176 @@
+package selectorgen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// names returns the registry's entries as "#id" and ".class" selectors.
+func names(reg *Registry) []string {
+	var out []string
+	for _, e := range reg.Entries() {
+		out = append(out, e.Kind.Prefix()+e.Name)
+	}
+	return out
+}
+
+// TestScanTemplate covers attributes, partial parameters, printf markup and
+// rendered-in names.
+func TestScanTemplate(t *testing.T) {
+	src := `{{/* Usage: {{ partial "x.html" (dict "id" "from-comment") }} */}}
+<!-- <div id="from-html-comment"></div> -->
+{{ $gridId := printf "%s%sverse-grid" $prefix $sep }}
+<div id="book-select" class="select w-auto{{ if .compact }} select--compact{{ end }}">
+<input id="bible-{{ .id }}" class="{{ $class }}">
+<span class="footnotes-section{{ $hiddenClass }}"></span>
+{{ partial "michael/sss-toggle.html" (dict "id" "sss-mode-btn" "statusId" "sss-mode-status" "optionClass" "color-option") }}
+{{ $content = printf "%s<span class=\"verse\" data-verse=\"%d\">" $content $n }}`
+
+	reg := NewRegistry()
+	ScanTemplate(reg, "layouts/test.html", src)
+	want := []string{
+		"#*verse-grid", "#bible-*", "#book-select", "#sss-mode-btn", "#sss-mode-status",
+		".color-option", ".footnotes-section*", ".select", ".select--compact", ".verse", ".w-auto",
+	}
+	if got := names(reg); !slices.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+// TestScanScript covers className, classList, id and markup in strings.
+func TestScanScript(t *testing.T) {
+	src := `/* el.className = 'from-comment'; */
+// btn.className = 'from-line-comment';
+btn.className = isActive ? 'chip is-active' : 'chip';
+msg.className = ` + "`offline-message offline-message--${type}`" + `;
+el.classList.add('copied', 'share-toast--visible');
+el.classList.toggle('hidden', !checked);
+if (b.id === currentBook) {}
+allBtn.id = 'all-verses-btn';
+tip.setAttribute('id', 'strongs-tooltip');
+root.innerHTML = '<div class="search-result">' + x + '</div>';`
+
+	reg := NewRegistry()
+	ScanScript(reg, "assets/js/test.js", src)
+	want := []string{
+		"#all-verses-btn", "#strongs-tooltip",
+		".chip", ".copied", ".hidden", ".is-active", ".offline-message", ".offline-message--*",
+		".search-result", ".share-toast--visible",
+	}
+	if got := names(reg); !slices.Equal(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+// TestRefs verifies which names are read from test selectors.
+func TestRefs(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []Ref
+	}{
+		{"#book-select", []Ref{{ID, "book-select"}}},
+		{".verse[data-verse='%d']", []Ref{{Class, "verse"}}},
+		{"#verse-buttons .verse-btn.is-active", []Ref{{ID, "verse-buttons"}, {Class, "verse-btn"}, {Class, "is-active"}}},
+		{".strongs-ref, .strongs-word", []Ref{{Class, "strongs-ref"}, {Class, "strongs-word"}}},
+		{"#bible-%s", []Ref{{ID, "bible-*"}}},
+		{"a[href$='.html']", nil},
+		{"meta[name='theme-color']", nil},
+		{"window.Michael && window.Michael.OfflineManager", nil},
+		{"Failed to load page.", nil},
+	}
+	for _, tt := range tests {
+		if got := Refs(tt.selector); !slices.Equal(got, tt.want) {
+			t.Errorf("Refs(%q) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+}
+
+// TestDefines verifies matching between exact names and patterns.
+func TestDefines(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add(ID, "book-select", "a.html")
+	reg.Add(ID, "bible-*", "a.html")
+	reg.Add(ID, "*-color-btn", "a.html")
+	reg.Add(Class, "*", "a.html")
+
+	tests := []struct {
+		kind Kind
+		name string
+		want bool
+	}{
+		{ID, "book-select", true},
+		{Class, "book-select", false},
+		{ID, "bible-asv", true},
+		{ID, "bible-*", true},
+		{ID, "highlight-color-btn", true},
+		{ID, "book-*", true},
+		{ID, "chapter-select", false},
+		{Class, "anything", false},
+	}
+	for _, tt := range tests {
+		if got := reg.Defines(tt.kind, tt.name); got != tt.want {
+			t.Errorf("Defines(%s, %q) = %v, want %v", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestGenerate verifies constant naming, including patterns and collisions.
+func TestGenerate(t *testing.T) {
+	reg := NewRegistry()
+	reg.Add(ID, "book-select", "a.html")
+	reg.Add(ID, "bible-*", "a.html")
+	reg.Add(Class, "bible-chip__status", "a.html")
+	reg.Add(Class, "bible-chip--status", "b.html")
+
+	src, err := Generate(reg, "selectors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`IDBookSelect = "#book-select"`,
+		`IDBibleFmt = "#bible-%s"`,
+		`ClassBibleChipStatus = ".bible-chip--status"`,
+		`ClassBibleChipStatus2 = ".bible-chip__status"`,
+	} {
+		if !bytes.Contains(src, []byte(want)) {
+			t.Errorf("generated source is missing %s:\n%s", want, src)
+		}
+	}
+}
+
+// TestSiteSelectors fails when helpers/selectors is stale or when a test
+// selects an ID or class that no template or script defines any more.
+func TestSiteSelectors(t *testing.T) {
+	reg, err := Scan(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := Generate(reg, "selectors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join("..", "helpers", "selectors", "selectors_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("helpers/selectors is out of date with the templates; run go generate ./helpers/selectors")
+	}
+
+	problems, err := Check(reg, filepath.Join("..", "helpers"), filepath.Join("..", "regression"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, p := range problems {
+		msgs = append(msgs, p.String())
+	}
+	if len(msgs) > 0 {
+		t.Errorf("selectors name IDs or classes that no longer exist:\n%s", strings.Join(msgs, "\n"))
+	}
+}